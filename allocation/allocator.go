@@ -0,0 +1,455 @@
+package allocation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+// DefaultFeeRate : 업비트 KRW 마켓 기본 수수료율 (예약 금액 계산용)
+const DefaultFeeRate = 0.0005
+
+var (
+	ErrAllocationExceeded = errors.New("allocation: order exceeds budget")
+	ErrOrderTooLarge      = errors.New("allocation: order exceeds max order size")
+	ErrUnknownPair        = errors.New("allocation: pair not allocated")
+)
+
+// quoter : LastQuote 를 제공하는 브로커(거래소)라면 체결가/수량이 비어있는 주문 정산과 평가금액 계산에 사용
+type quoter interface {
+	LastQuote(pair string) (float64, error)
+}
+
+// Allocator : 하나의 KRW 잔고를 예산(종목 또는 전략) 단위로 나눠 관리합니다.
+// 각 전략은 Broker(budget)가 돌려주는 브로커만 사용하므로, 다른 예산에 배정된 KRW를 쓸 수 없습니다.
+// 예산별 보유 수량/손익은 이 Allocator 를 거쳐 체결된 주문 기준으로 집계합니다.
+type Allocator struct {
+	FeeRate     float64
+	MaxOrderKRW float64 // 매수 1건 최대 금액. 0 이면 제한 없음
+
+	broker  interfaces.Broker
+	weights map[string]float64
+
+	mu      sync.Mutex
+	budgets map[string]*budget
+	owners  map[string]map[string]bool // pair → 그 종목을 거래하는 예산
+	pending map[string]pendingOrder    // key=ExchangeID, 체결 확정 전 주문
+}
+
+type budget struct {
+	funded   float64 // 배정받은 금액
+	cash     float64 // 사용 가능한 KRW (예약분 차감 후)
+	reserved float64 // 체결 대기 중인 매수 예약분
+	holdings map[string]*holding
+	trades   int
+}
+
+// holding : 예산이 보유한 코인 수량과 매입금액(수수료 포함)
+type holding struct {
+	quantity float64
+	cost     float64
+}
+
+type pendingOrder struct {
+	budget   string
+	pair     string
+	side     model.SideType
+	reserved float64
+}
+
+// Stats : 예산 하나의 현재 손익
+type Stats struct {
+	Name     string  `json:"name"`
+	Funded   float64 `json:"funded"`
+	Cash     float64 `json:"cash"`
+	Holdings float64 `json:"holdings"` // 보유 코인 평가금액 (마지막 시세 기준)
+	Equity   float64 `json:"equity"`
+	PnL      float64 `json:"pnl"`
+	PnLRate  float64 `json:"pnl_rate"`
+	Trades   int     `json:"trades"`
+}
+
+// EqualWeights : 모든 예산에 같은 비중을 부여합니다.
+func EqualWeights(names []string) map[string]float64 {
+	weights := make(map[string]float64, len(names))
+	for _, name := range names {
+		weights[name] = 1
+	}
+	return weights
+}
+
+// NewAllocator : weights 의 key 는 예산 이름(종목 또는 전략), 값은 상대 비중이며 합이 1일 필요는 없습니다.
+func NewAllocator(broker interfaces.Broker, weights map[string]float64) *Allocator {
+	a := &Allocator{
+		FeeRate: DefaultFeeRate,
+		broker:  broker,
+		weights: make(map[string]float64, len(weights)),
+		budgets: make(map[string]*budget, len(weights)),
+		owners:  make(map[string]map[string]bool),
+		pending: make(map[string]pendingOrder),
+	}
+	for name, w := range weights {
+		if w > 0 {
+			name = normalize(name)
+			a.weights[name] = w
+			a.budgets[name] = &budget{holdings: make(map[string]*holding)}
+		}
+	}
+	return a
+}
+
+// Fund : capital 을 비중대로 나눠 예산을 (재)설정합니다. 진행 중인 예약은 유지됩니다.
+func (a *Allocator) Fund(capital float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var total, pending float64
+	for _, w := range a.weights {
+		total += w
+	}
+	if total == 0 {
+		return
+	}
+	for _, b := range a.budgets {
+		pending += b.reserved
+	}
+
+	available := math.Max(capital-pending, 0)
+	for name, w := range a.weights {
+		b := a.budgets[name]
+		b.cash = available * w / total
+		b.funded = b.cash + b.reserved
+		log.Infof("[Allocator] %s budget=%.2f KRW (weight=%.2f)", name, b.cash, w/total)
+	}
+}
+
+// Budgets : 예산 이름 목록 (정렬)
+func (a *Allocator) Budgets() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	names := make([]string, 0, len(a.budgets))
+	for name := range a.budgets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Available : 예산에 남아있는 사용 가능 KRW
+func (a *Allocator) Available(name string) float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if b, ok := a.budgets[normalize(name)]; ok {
+		return b.cash
+	}
+	return 0
+}
+
+// Stats : 예산의 평가금액과 손익. 보유 코인은 브로커의 LastQuote 로 평가합니다.
+func (a *Allocator) Stats(name string) Stats {
+	name = normalize(name)
+
+	a.mu.Lock()
+	b, ok := a.budgets[name]
+	if !ok {
+		a.mu.Unlock()
+		return Stats{Name: name}
+	}
+	stats := Stats{Name: name, Funded: b.funded, Cash: b.cash + b.reserved, Trades: b.trades}
+	holdings := make(map[string]holding, len(b.holdings))
+	for pair, h := range b.holdings {
+		holdings[pair] = *h
+	}
+	a.mu.Unlock()
+
+	for pair, h := range holdings {
+		if h.quantity <= 0 {
+			continue
+		}
+		if price := a.quote(pair); price > 0 {
+			stats.Holdings += h.quantity * price
+		} else {
+			stats.Holdings += h.cost
+		}
+	}
+	stats.Equity = stats.Cash + stats.Holdings
+	stats.PnL = stats.Equity - stats.Funded
+	if stats.Funded > 0 {
+		stats.PnLRate = stats.PnL / stats.Funded
+	}
+	return stats
+}
+
+// Broker : budget 예산 안에서만 매수하는 브로커를 반환합니다.
+// pairs 를 생략하면 예산 이름을 종목으로 사용합니다. (종목별 배분)
+func (a *Allocator) Broker(name string, pairs ...string) interfaces.Broker {
+	name = normalize(name)
+	if len(pairs) == 0 {
+		pairs = []string{name}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	allowed := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		pair = strings.ToUpper(pair)
+		allowed[pair] = true
+		if a.owners[pair] == nil {
+			a.owners[pair] = make(map[string]bool)
+		}
+		a.owners[pair][name] = true
+	}
+	return &budgetBroker{Broker: a.broker, allocator: a, budget: name, pairs: allowed}
+}
+
+// OnOrderExecuted : consumer.OrderExecutedCallback 으로 등록해 체결 결과를 예산에 반영합니다.
+func (a *Allocator) OnOrderExecuted(order model.Order, err error) {
+	a.mu.Lock()
+	p, ok := a.pending[order.ExchangeID]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	delete(a.pending, order.ExchangeID)
+	a.mu.Unlock()
+
+	if err != nil {
+		// 미체결 취소 등: 예약분 반환
+		a.release(p)
+		return
+	}
+	a.apply(p, order)
+}
+
+// reserve : 매수 주문 전에 예산에서 amount 를 예약합니다.
+func (a *Allocator) reserve(name string, amount float64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b := a.budgets[name]
+	if b == nil {
+		return fmt.Errorf("%w: budget %s", ErrUnknownPair, name)
+	}
+	if a.MaxOrderKRW > 0 && amount > a.MaxOrderKRW {
+		return fmt.Errorf("%w: %s amount=%.2f, max=%.2f", ErrOrderTooLarge, name, amount, a.MaxOrderKRW)
+	}
+	if amount > b.cash {
+		return fmt.Errorf("%w: %s need=%.2f, available=%.2f", ErrAllocationExceeded, name, amount, b.cash)
+	}
+	b.cash -= amount
+	b.reserved += amount
+	return nil
+}
+
+// commit : 주문 결과에 따라 체결 확정을 기다리거나 즉시 정산합니다.
+func (a *Allocator) commit(p pendingOrder, order model.Order, err error) {
+	switch {
+	case err != nil:
+		a.release(p)
+	case order.ExchangeID == "":
+		// 추적할 수 없는 주문은 응답 기준으로 바로 정산
+		a.apply(p, order)
+	default:
+		a.mu.Lock()
+		a.pending[order.ExchangeID] = p
+		a.mu.Unlock()
+	}
+}
+
+func (a *Allocator) release(p pendingOrder) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if b := a.budgets[p.budget]; b != nil && p.side == model.SideTypeBuy {
+		b.reserved -= p.reserved
+		b.cash += p.reserved
+	}
+}
+
+// apply : 체결된 주문을 예산의 KRW/보유 수량에 반영합니다.
+func (a *Allocator) apply(p pendingOrder, order model.Order) {
+	price, quantity := order.Price, order.Quantity
+	if price <= 0 || quantity <= 0 {
+		// 시장가 주문의 REST 응답에는 체결가/수량이 비어있을 수 있음
+		price = a.quote(p.pair)
+		if p.side == model.SideTypeBuy && quantity <= 0 && price > 0 {
+			quantity = p.reserved / (1 + a.FeeRate) / price
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b := a.budgets[p.budget]
+	if b == nil {
+		return
+	}
+	h := b.holdings[p.pair]
+	if h == nil {
+		h = &holding{}
+		b.holdings[p.pair] = h
+	}
+	b.trades++
+
+	switch p.side {
+	case model.SideTypeBuy:
+		cost := price * quantity * (1 + a.FeeRate)
+		if cost <= 0 || cost > p.reserved {
+			cost = p.reserved
+		}
+		b.reserved -= p.reserved
+		b.cash += p.reserved - cost
+		h.quantity += quantity
+		h.cost += cost
+	case model.SideTypeSell:
+		b.cash += price * quantity * (1 - a.FeeRate)
+		if h.quantity > 0 {
+			sold := math.Min(quantity, h.quantity)
+			h.cost -= h.cost * sold / h.quantity
+			h.quantity -= sold
+		}
+	}
+}
+
+// shared : 여러 예산이 같은 종목을 거래하는지 여부
+func (a *Allocator) shared(pair string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.owners[pair]) > 1
+}
+
+func (a *Allocator) holding(name, pair string) holding {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if b := a.budgets[name]; b != nil && b.holdings[pair] != nil {
+		return *b.holdings[pair]
+	}
+	return holding{}
+}
+
+func (a *Allocator) quote(pair string) float64 {
+	if q, ok := a.broker.(quoter); ok {
+		if price, err := q.LastQuote(pair); err == nil {
+			return price
+		}
+	}
+	return 0
+}
+
+func normalize(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// budgetBroker : 한 예산 전용 브로커. 매수는 예산을 예약한 뒤 실제 브로커로 전달합니다.
+type budgetBroker struct {
+	interfaces.Broker
+	allocator *Allocator
+	budget    string
+	pairs     map[string]bool
+}
+
+// Position : quote(KRW)는 실제 잔고와 예산 중 작은 값으로 제한합니다.
+// 같은 종목을 다른 예산도 거래한다면 보유 수량/평단도 이 예산이 체결한 몫만 보여줍니다.
+func (p *budgetBroker) Position(pair string) (asset, quote, avgBuyPrice float64, err error) {
+	asset, quote, avgBuyPrice, err = p.Broker.Position(pair)
+	if err != nil {
+		return
+	}
+	quote = math.Min(quote, p.allocator.Available(p.budget))
+
+	pair = strings.ToUpper(pair)
+	if p.allocator.shared(pair) {
+		h := p.allocator.holding(p.budget, pair)
+		asset = math.Min(asset, h.quantity)
+		avgBuyPrice = 0
+		if h.quantity > 0 {
+			avgBuyPrice = h.cost / h.quantity / (1 + p.allocator.FeeRate)
+		}
+	}
+	return
+}
+
+func (p *budgetBroker) CreateOrderLimit(side model.SideType, pair string,
+	quantity float64, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
+	return p.submit(side, pair, quantity*limit*(1+p.allocator.FeeRate), func() (model.Order, error) {
+		return p.Broker.CreateOrderLimit(side, pair, quantity, limit, tif...)
+	})
+}
+
+// CreateOrderMarket : 매수 quantity 는 수수료 포함 KRW 금액
+func (p *budgetBroker) CreateOrderMarket(side model.SideType, pair string, quantity float64) (model.Order, error) {
+	return p.submit(side, pair, quantity, func() (model.Order, error) {
+		return p.Broker.CreateOrderMarket(side, pair, quantity)
+	})
+}
+
+// CreateOrderLimitIdentified : 감싼 브로커가 identifier 를 지원하지 않으면 identifier 없이 주문합니다.
+func (p *budgetBroker) CreateOrderLimitIdentified(identifier string, side model.SideType, pair string,
+	quantity float64, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
+	identified, ok := p.Broker.(interfaces.IdentifiedBroker)
+	if !ok {
+		return p.CreateOrderLimit(side, pair, quantity, limit, tif...)
+	}
+	return p.submit(side, pair, quantity*limit*(1+p.allocator.FeeRate), func() (model.Order, error) {
+		return identified.CreateOrderLimitIdentified(identifier, side, pair, quantity, limit, tif...)
+	})
+}
+
+// CreateOrderMarketIdentified : 감싼 브로커가 identifier 를 지원하지 않으면 identifier 없이 주문합니다.
+func (p *budgetBroker) CreateOrderMarketIdentified(identifier string, side model.SideType, pair string, quantity float64) (model.Order, error) {
+	identified, ok := p.Broker.(interfaces.IdentifiedBroker)
+	if !ok {
+		return p.CreateOrderMarket(side, pair, quantity)
+	}
+	return p.submit(side, pair, quantity, func() (model.Order, error) {
+		return identified.CreateOrderMarketIdentified(identifier, side, pair, quantity)
+	})
+}
+
+func (p *budgetBroker) CreateOrderBest(side model.SideType, pair string, quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
+	return p.submit(side, pair, quantity*(1+p.allocator.FeeRate), func() (model.Order, error) {
+		return p.Broker.CreateOrderBest(side, pair, quantity, tif...)
+	})
+}
+
+// CreateOrderBestIdentified : 감싼 브로커가 identifier 를 지원하지 않으면 identifier 없이 주문합니다.
+func (p *budgetBroker) CreateOrderBestIdentified(identifier string, side model.SideType, pair string,
+	quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
+	identified, ok := p.Broker.(interfaces.IdentifiedBroker)
+	if !ok {
+		return p.CreateOrderBest(side, pair, quantity, tif...)
+	}
+	return p.submit(side, pair, quantity*(1+p.allocator.FeeRate), func() (model.Order, error) {
+		return identified.CreateOrderBestIdentified(identifier, side, pair, quantity, tif...)
+	})
+}
+
+func (p *budgetBroker) submit(side model.SideType, pair string, amount float64, create func() (model.Order, error)) (model.Order, error) {
+	pair = strings.ToUpper(pair)
+	if !p.pairs[pair] {
+		return model.Order{}, fmt.Errorf("%w: budget %s cannot trade %s", ErrUnknownPair, p.budget, pair)
+	}
+
+	pending := pendingOrder{budget: p.budget, pair: pair, side: side}
+	if side == model.SideTypeBuy {
+		if err := p.allocator.reserve(p.budget, amount); err != nil {
+			return model.Order{}, err
+		}
+		pending.reserved = amount
+	}
+
+	order, err := create()
+	p.allocator.commit(pending, order, err)
+	return order, err
+}
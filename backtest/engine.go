@@ -0,0 +1,330 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"raccoon/consumer"
+	"raccoon/exchange"
+	"raccoon/execution"
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/oms"
+	"raccoon/portfolio"
+	"raccoon/risk"
+	"raccoon/strategy"
+	"raccoon/utils/log"
+	"raccoon/utils/tools"
+)
+
+var ErrNoStrategy = errors.New("backtest: no strategy registered")
+
+// EquityPoint : 특정 시각(봉 마감 시각)의 평가금액
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"` // KRW + 보유 코인 평가금액
+	Cash   float64   `json:"cash"`   // KRW (묶인 금액 포함)
+}
+
+type Result struct {
+	Start       time.Time
+	End         time.Time
+	InitialKRW  float64
+	FinalEquity float64
+	FeeRate     float64
+
+	Orders   []model.Order // 체결된 주문 (체결 순서)
+	Rejected int           // 브로커가 거절한 주문 수
+	Equity   []EquityPoint
+
+	// Candles : pair별로 브로커 체결에 사용된 캔들 (벤치마크 계산용)
+	Candles map[string][]model.Candle
+}
+
+type strategyRun struct {
+	pair       string
+	strategy   interfaces.Strategy
+	controller *strategy.Controller
+}
+
+type feedKey struct {
+	pair      string
+	timeframe string
+}
+
+type event struct {
+	key     feedKey
+	closeAt time.Time
+	candle  model.Candle
+}
+
+// Engine : 과거 캔들로 DataFeedSubscription -> Controller -> 주문 피드 -> BacktestBroker 를
+// 하나의 고루틴에서 순서대로 돌리는 백테스트 엔진입니다.
+// 각 봉은 마감 시각 순으로 처리되고, 그 봉에서 나온 주문은 다음 봉으로 넘어가기 전에 체결됩니다.
+// 같은 입력이면 항상 같은 결과가 나옵니다.
+type Engine struct {
+	feeder interfaces.DataFeeder
+
+	clock          *tools.SimulatedClock
+	broker         *exchange.BacktestBroker
+	dataFeed       *feed.DataFeedSubscription
+	orderFeed      *feed.OrderFeedSubscription
+	consumerBroker *consumer.OrderFeedConsumerBroker
+	orderManager   *oms.Manager
+	accountant     *portfolio.Accountant
+	executor       *execution.Executor
+
+	initialKRW float64
+	runs       []*strategyRun
+	pairs      map[string]bool
+	orders     []model.Order
+	rejected   int
+}
+
+func NewEngine(feeder interfaces.DataFeeder, initialKRW float64) *Engine {
+	clock := tools.NewSimulatedClock(time.Time{})
+	broker := exchange.NewBackTestBroker("", initialKRW)
+	broker.SetClock(clock)
+
+	e := &Engine{
+		feeder:         feeder,
+		clock:          clock,
+		broker:         broker,
+		dataFeed:       feed.NewDataFeed(feeder),
+		orderFeed:      feed.NewSyncOrderFeed(),
+		consumerBroker: consumer.NewOrderFeedConsumerBroker(broker),
+		orderManager:   oms.NewManager(broker),
+		accountant:     portfolio.NewAccountant(portfolio.CostAverage, broker),
+		executor:       execution.NewExecutor(broker),
+		initialKRW:     initialKRW,
+		pairs:          make(map[string]bool),
+	}
+	e.consumerBroker.AddOrderExecutedCallback(e.onOrderExecuted)
+	e.consumerBroker.SetOrderManager(e.orderManager)
+	e.consumerBroker.SetAccountant(e.accountant)
+	e.executor.SetClock(clock)
+	e.executor.FeeRate = broker.FeeRate
+	e.consumerBroker.SetExecutor(e.executor)
+	return e
+}
+
+// OrderFeed : 전략 생성 시 넘겨줄 주문 피드 (동기 모드)
+func (e *Engine) OrderFeed() *feed.OrderFeedSubscription {
+	return e.orderFeed
+}
+
+func (e *Engine) Broker() *exchange.BacktestBroker {
+	return e.broker
+}
+
+// OrderManager : 백테스트 주문의 체결 과정을 추적하는 주문 관리자
+func (e *Engine) OrderManager() *oms.Manager {
+	return e.orderManager
+}
+
+// Accountant : 백테스트 체결의 실현/미실현 손익 (평가는 마지막으로 처리한 봉의 가격)
+func (e *Engine) Accountant() *portfolio.Accountant {
+	return e.accountant
+}
+
+// SetRiskManager : 실거래와 같은 리스크 검사를 백테스트 주문에도 적용합니다. 주문 빈도 제한은 Clock() 을 쓰면 시뮬레이션 시각 기준
+func (e *Engine) SetRiskManager(m *risk.Manager) {
+	e.consumerBroker.SetRiskManager(m)
+}
+
+// Executor : 백테스트의 TWAP/iceberg/chase 실행기. 봉마다 시뮬레이션 시각으로 진행됩니다.
+func (e *Engine) Executor() *execution.Executor {
+	return e.executor
+}
+
+func (e *Engine) Clock() tools.Clock {
+	return e.clock
+}
+
+// AddOrderExecutedCallback : 체결/거절 결과를 추가로 받고 싶을 때 등록
+func (e *Engine) AddOrderExecutedCallback(cb consumer.OrderExecutedCallback) {
+	e.consumerBroker.AddOrderExecutedCallback(cb)
+}
+
+// AddStrategy : pair에 전략을 등록합니다. 전략은 실거래와 동일하게 Controller를 통해 호출됩니다.
+func (e *Engine) AddStrategy(pair string, strat interfaces.Strategy) *strategy.Controller {
+	ctrl := strategy.NewStrategyController(pair, strat, e.broker)
+	if managed, ok := strategy.OrderManaged(strat); ok {
+		managed.SetOrderManager(e.orderManager)
+	}
+	e.dataFeed.Subscribe(pair, strat.Timeframe(), consumer.NewDataFeedConsumerStrategy(ctrl).OnCandle, true)
+
+	if !e.pairs[pair] {
+		e.pairs[pair] = true
+		e.orderFeed.SubscribeAck(pair, "", e.consumerBroker.HandleOrder)
+	}
+
+	e.runs = append(e.runs, &strategyRun{
+		pair:       pair,
+		strategy:   strat,
+		controller: ctrl,
+	})
+	return ctrl
+}
+
+// Run : [start, end] 구간을 시뮬레이션합니다. 워밍업 봉은 start 이전 구간에서 추가로 불러옵니다.
+func (e *Engine) Run(start, end time.Time) (*Result, error) {
+	if len(e.runs) == 0 {
+		return nil, ErrNoStrategy
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("backtest: invalid period %v ~ %v", start, end)
+	}
+
+	keys, warmups := e.feedKeys()
+	drivers, err := driverFeeds(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []event
+	candlesByPair := make(map[string][]model.Candle)
+	for _, key := range keys {
+		dur, _ := timeframeDuration(key.timeframe)
+		from := start.Add(-time.Duration(warmups[key]) * dur)
+
+		candles, err := e.feeder.CandlesByPeriod(key.pair, key.timeframe, from, end)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: load candles %s-%s: %w", key.pair, key.timeframe, err)
+		}
+		sort.SliceStable(candles, func(i, j int) bool {
+			return candles[i].Time.Before(candles[j].Time)
+		})
+
+		var warmup []model.Candle
+		for _, candle := range candles {
+			closeAt := candle.Time.Add(dur)
+			if closeAt.After(end) {
+				continue // 아직 마감되지 않은 봉
+			}
+			candle.Complete = true
+			if candle.Time.Before(start) {
+				warmup = append(warmup, candle)
+				continue
+			}
+			events = append(events, event{key: key, closeAt: closeAt, candle: candle})
+			if drivers[key.pair] == key {
+				candlesByPair[key.pair] = append(candlesByPair[key.pair], candle)
+			}
+		}
+		log.Infof("[Backtest] %s-%s: %d warmup, %d candles", key.pair, key.timeframe, len(warmup), len(candles)-len(warmup))
+		e.dataFeed.Preload(key.pair, key.timeframe, warmup)
+	}
+
+	// 마감 시각 순. 같은 시각이면 체결용(가장 짧은) 봉을 먼저 처리해 브로커 가격을 그 시각 종가로 맞춘 뒤
+	// 긴 봉을 전달합니다. 나머지는 등록 순서(keys 순서)를 유지
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].closeAt.Equal(events[j].closeAt) {
+			return events[i].closeAt.Before(events[j].closeAt)
+		}
+		return drivers[events[i].key.pair] == events[i].key && drivers[events[j].key.pair] != events[j].key
+	})
+
+	e.clock.Set(start)
+	for _, run := range e.runs {
+		run.controller.Start()
+	}
+	e.orderFeed.Start()
+	defer e.orderFeed.Stop()
+
+	var curve []EquityPoint
+	for _, ev := range events {
+		e.clock.Set(ev.closeAt)
+
+		// 이전 봉까지 걸어둔 지정가 주문은 새 봉의 가격 범위로 먼저 체결
+		if drivers[ev.key.pair] == ev.key {
+			e.broker.OnCandle(ev.candle)
+			if len(e.orderManager.OpenOrders(ev.key.pair)) > 0 {
+				e.orderManager.Poll()
+			}
+		}
+		e.dataFeed.Publish(ev.key.pair, ev.key.timeframe, ev.candle)
+		// 실행 알고리즘은 봉 마감 시각 기준으로 다음 자식 주문을 냄
+		e.executor.Step()
+
+		point := EquityPoint{
+			Time:   ev.closeAt,
+			Equity: e.broker.Equity(),
+			Cash:   e.broker.Cash(),
+		}
+		if n := len(curve); n > 0 && curve[n-1].Time.Equal(point.Time) {
+			curve[n-1] = point
+		} else {
+			curve = append(curve, point)
+		}
+	}
+
+	return &Result{
+		Start:       start,
+		End:         end,
+		InitialKRW:  e.initialKRW,
+		FinalEquity: e.broker.Equity(),
+		FeeRate:     e.broker.FeeRate,
+		Orders:      e.orders,
+		Rejected:    e.rejected,
+		Equity:      curve,
+		Candles:     candlesByPair,
+	}, nil
+}
+
+func (e *Engine) onOrderExecuted(order model.Order, err error) {
+	if err != nil {
+		e.rejected++
+		log.Warnf("[Backtest] order rejected at %s: %v", e.clock.Now().Format(time.DateTime), err)
+		return
+	}
+	e.orders = append(e.orders, order)
+}
+
+// feedKeys : 등록 순서대로 중복 없는 (pair, timeframe) 목록과 각 피드의 최대 워밍업 봉 수
+func (e *Engine) feedKeys() ([]feedKey, map[feedKey]int) {
+	var keys []feedKey
+	warmups := make(map[feedKey]int)
+	for _, run := range e.runs {
+		key := feedKey{pair: run.pair, timeframe: run.strategy.Timeframe()}
+		if _, ok := warmups[key]; !ok {
+			keys = append(keys, key)
+		}
+		if run.strategy.WarmupPeriod() > warmups[key] {
+			warmups[key] = run.strategy.WarmupPeriod()
+		}
+	}
+	return keys, warmups
+}
+
+// driverFeeds : pair마다 가장 짧은 타임프레임만 브로커 체결에 사용합니다.
+// 긴 봉으로도 체결을 시키면 이미 지나간 구간의 고가/저가로 주문이 체결되는 미래참조가 생깁니다.
+func driverFeeds(keys []feedKey) (map[string]feedKey, error) {
+	drivers := make(map[string]feedKey)
+	for _, key := range keys {
+		dur, err := timeframeDuration(key.timeframe)
+		if err != nil {
+			return nil, err
+		}
+		cur, ok := drivers[key.pair]
+		if !ok {
+			drivers[key.pair] = key
+			continue
+		}
+		curDur, _ := timeframeDuration(cur.timeframe)
+		if dur < curDur {
+			drivers[key.pair] = key
+		}
+	}
+	return drivers, nil
+}
+
+func timeframeDuration(tf string) (time.Duration, error) {
+	if tf == "1s" {
+		return time.Second, nil
+	}
+	return tools.ParseTimeframeToDuration(tf)
+}
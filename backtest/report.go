@@ -0,0 +1,479 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"raccoon/model"
+)
+
+const (
+	daysPerYear = 365 // 코인 시장은 24시간/365일 거래
+	year        = daysPerYear * 24 * time.Hour
+)
+
+// Duration : JSON에서 "1h30m0s" 처럼 사람이 읽을 수 있는 문자열로 표현되는 time.Duration
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Ratio : 분모가 0 이라 무한대일 수 있는 비율. +Inf 는 JSON 에서 "∞" 로 저장합니다 (encoding/json 은 Inf 를 쓰지 못함).
+type Ratio float64
+
+// infinity : Ratio 의 +Inf 표기
+const infinity = "∞"
+
+func (r Ratio) String() string {
+	if math.IsInf(float64(r), 1) {
+		return infinity
+	}
+	return fmt.Sprintf("%.2f", float64(r))
+}
+
+func (r Ratio) MarshalJSON() ([]byte, error) {
+	if math.IsInf(float64(r), 1) {
+		return json.Marshal(infinity)
+	}
+	return json.Marshal(float64(r))
+}
+
+func (r *Ratio) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		if s != infinity {
+			return fmt.Errorf("backtest: invalid ratio %q", s)
+		}
+		*r = Ratio(math.Inf(1))
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*r = Ratio(v)
+	return nil
+}
+
+// Trade : 매도 1건 단위의 실현 손익 (평균 단가 기준)
+type Trade struct {
+	Pair       string    `json:"pair"`
+	EntryTime  time.Time `json:"entry_time"` // 포지션을 처음 연 시각
+	ExitTime   time.Time `json:"exit_time"`
+	Quantity   float64   `json:"quantity"`
+	EntryPrice float64   `json:"entry_price"` // 수수료 포함 평균 매입가
+	ExitPrice  float64   `json:"exit_price"`
+	Profit     float64   `json:"profit"`     // 수수료 차감 후 실현 손익 (KRW)
+	ProfitPct  float64   `json:"profit_pct"` // 매입 원가 대비 수익률
+	Holding    Duration  `json:"holding"`
+}
+
+// Benchmark : 같은 pair를 시작 시점에 전액 매수해 끝까지 보유한 경우
+type Benchmark struct {
+	Pair             string  `json:"pair"`
+	TotalReturn      float64 `json:"total_return"`
+	AnnualizedReturn float64 `json:"annualized_return"`
+	MaxDrawdown      float64 `json:"max_drawdown"`
+}
+
+type Report struct {
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	InitialKRW  float64   `json:"initial_krw"`
+	FinalEquity float64   `json:"final_equity"`
+
+	TotalReturn         float64  `json:"total_return"`
+	AnnualizedReturn    float64  `json:"annualized_return"`
+	MaxDrawdown         float64  `json:"max_drawdown"` // 고점 대비 최대 하락률 (0.1 = 10%)
+	MaxDrawdownDuration Duration `json:"max_drawdown_duration"`
+	Sharpe              float64  `json:"sharpe"`
+	Sortino             float64  `json:"sortino"`
+	Calmar              float64  `json:"calmar"`
+
+	Trades         int      `json:"trades"`
+	WinRate        float64  `json:"win_rate"`
+	ProfitFactor   Ratio    `json:"profit_factor"` // 총이익/총손실. 이익만 있으면 +Inf("∞"), 이익이 없으면 0
+	AvgHoldingTime Duration `json:"avg_holding_time"`
+	Exposure       float64  `json:"exposure"` // 코인을 보유하고 있던 시간 비율
+
+	Benchmarks   []Benchmark   `json:"benchmarks"`
+	EquityCurve  []EquityPoint `json:"equity_curve"`
+	ClosedTrades []Trade       `json:"closed_trades"`
+
+	feeRate float64
+	candles map[string]candleSeries
+	source  *Result
+}
+
+type candleSeries struct {
+	times  []time.Time
+	opens  []float64
+	closes []float64
+}
+
+// NewReport : 백테스트 결과로 성과 지표를 계산합니다.
+func NewReport(result *Result) *Report {
+	r := &Report{
+		Start:       result.Start,
+		End:         result.End,
+		InitialKRW:  result.InitialKRW,
+		FinalEquity: result.FinalEquity,
+		EquityCurve: result.Equity,
+		feeRate:     result.FeeRate,
+		source:      result,
+		candles:     make(map[string]candleSeries),
+	}
+
+	values := make([]float64, 0, len(result.Equity)+1)
+	times := make([]time.Time, 0, len(result.Equity)+1)
+	values = append(values, result.InitialKRW)
+	times = append(times, result.Start)
+	for _, p := range result.Equity {
+		values = append(values, p.Equity)
+		times = append(times, p.Time)
+	}
+
+	elapsed := result.End.Sub(result.Start)
+	r.TotalReturn = totalReturn(result.InitialKRW, result.FinalEquity)
+	r.AnnualizedReturn = annualize(r.TotalReturn, elapsed)
+
+	maxDD, ddDuration := maxDrawdown(times, values)
+	r.MaxDrawdown = maxDD
+	r.MaxDrawdownDuration = Duration(ddDuration)
+
+	ppy := periodsPerYear(times)
+	returns := periodReturns(values)
+	r.Sharpe = sharpe(returns, ppy)
+	r.Sortino = sortino(returns, ppy)
+	if r.MaxDrawdown > 0 {
+		r.Calmar = r.AnnualizedReturn / r.MaxDrawdown
+	}
+
+	r.ClosedTrades = closedTrades(result)
+	r.Trades = len(r.ClosedTrades)
+	var wins int
+	var grossProfit, grossLoss float64
+	var holding time.Duration
+	for _, t := range r.ClosedTrades {
+		if t.Profit > 0 {
+			wins++
+			grossProfit += t.Profit
+		} else {
+			grossLoss -= t.Profit
+		}
+		holding += time.Duration(t.Holding)
+	}
+	if r.Trades > 0 {
+		r.WinRate = float64(wins) / float64(r.Trades)
+		r.AvgHoldingTime = Duration(holding / time.Duration(r.Trades))
+	}
+	switch {
+	case grossLoss > 0:
+		r.ProfitFactor = Ratio(grossProfit / grossLoss)
+	case grossProfit > 0:
+		r.ProfitFactor = Ratio(math.Inf(1))
+	}
+
+	var exposed int
+	for _, p := range result.Equity {
+		if p.Equity-p.Cash > 1e-9*p.Equity {
+			exposed++
+		}
+	}
+	if len(result.Equity) > 0 {
+		r.Exposure = float64(exposed) / float64(len(result.Equity))
+	}
+
+	pairs := make([]string, 0, len(result.Candles))
+	for pair := range result.Candles {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	for _, pair := range pairs {
+		cs := toCandleSeries(result.Candles[pair])
+		if len(cs.times) == 0 {
+			continue
+		}
+		r.candles[pair] = cs
+		r.Benchmarks = append(r.Benchmarks, r.benchmark(pair, cs))
+	}
+
+	return r
+}
+
+// Source : 리포트를 만든 원본 백테스트 결과
+func (r *Report) Source() *Result {
+	return r.source
+}
+
+// JSON : 리포트를 들여쓰기 된 JSON으로 직렬화
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// LoadReport : JSON() 으로 저장한 리포트를 읽습니다.
+// 원본 캔들은 저장되지 않으므로 BenchmarkCurve 는 nil 을 반환합니다.
+func LoadReport(path string) (*Report, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{}
+	if err := json.Unmarshal(raw, r); err != nil {
+		return nil, fmt.Errorf("backtest: %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// BenchmarkCurve : 매수 후 보유 전략의 평가금액을 equity curve와 같은 시각들로 계산합니다.
+func (r *Report) BenchmarkCurve(pair string) []float64 {
+	cs, ok := r.candles[pair]
+	if !ok {
+		return nil
+	}
+	units := r.InitialKRW / (cs.opens[0] * (1 + r.feeRate))
+
+	out := make([]float64, len(r.EquityCurve))
+	idx := -1
+	for i, p := range r.EquityCurve {
+		// p.Time 이전에 시작한 마지막 봉 (= p.Time 까지 마감된 봉)
+		for idx+1 < len(cs.times) && cs.times[idx+1].Before(p.Time) {
+			idx++
+		}
+		if idx < 0 {
+			out[i] = r.InitialKRW
+			continue
+		}
+		out[i] = units * cs.closes[idx] * (1 - r.feeRate)
+	}
+	return out
+}
+
+// DrawdownCurve : equity curve 각 시점의 고점 대비 하락률
+func (r *Report) DrawdownCurve() []float64 {
+	out := make([]float64, len(r.EquityCurve))
+	peak := r.InitialKRW
+	for i, p := range r.EquityCurve {
+		peak = math.Max(peak, p.Equity)
+		if peak > 0 {
+			out[i] = (peak - p.Equity) / peak
+		}
+	}
+	return out
+}
+
+func (r *Report) benchmark(pair string, cs candleSeries) Benchmark {
+	units := r.InitialKRW / (cs.opens[0] * (1 + r.feeRate))
+
+	values := make([]float64, 0, len(cs.closes)+1)
+	values = append(values, r.InitialKRW)
+	for _, c := range cs.closes {
+		values = append(values, units*c*(1-r.feeRate))
+	}
+	times := append([]time.Time{r.Start}, cs.times...)
+
+	b := Benchmark{Pair: pair}
+	b.TotalReturn = totalReturn(r.InitialKRW, values[len(values)-1])
+	b.AnnualizedReturn = annualize(b.TotalReturn, r.End.Sub(r.Start))
+	b.MaxDrawdown, _ = maxDrawdown(times, values)
+	return b
+}
+
+func toCandleSeries(candles []model.Candle) candleSeries {
+	var cs candleSeries
+	for _, c := range candles {
+		cs.times = append(cs.times, c.Time)
+		cs.opens = append(cs.opens, c.Open)
+		cs.closes = append(cs.closes, c.Close)
+	}
+	return cs
+}
+
+// closedTrades : 체결 내역을 pair별 평균 단가로 따라가며 매도 건마다 실현 손익을 계산합니다.
+func closedTrades(result *Result) []Trade {
+	type position struct {
+		quantity float64
+		cost     float64 // 수수료 포함 매입 원가
+		openedAt time.Time
+	}
+	positions := make(map[string]*position)
+
+	var trades []Trade
+	for _, order := range result.Orders {
+		pos, ok := positions[order.Pair]
+		if !ok {
+			pos = &position{}
+			positions[order.Pair] = pos
+		}
+
+		switch order.Side {
+		case model.SideTypeBuy:
+			if pos.quantity <= 0 {
+				pos.openedAt = order.UpdatedAt
+			}
+			pos.quantity += order.Quantity
+			pos.cost += order.Price * order.Quantity * (1 + result.FeeRate)
+		case model.SideTypeSell:
+			if pos.quantity <= 0 {
+				continue
+			}
+			quantity := math.Min(order.Quantity, pos.quantity)
+			avg := pos.cost / pos.quantity
+			basis := avg * quantity
+			proceeds := order.Price * quantity * (1 - result.FeeRate)
+
+			trades = append(trades, Trade{
+				Pair:       order.Pair,
+				EntryTime:  pos.openedAt,
+				ExitTime:   order.UpdatedAt,
+				Quantity:   quantity,
+				EntryPrice: avg,
+				ExitPrice:  order.Price,
+				Profit:     proceeds - basis,
+				ProfitPct:  (proceeds - basis) / basis,
+				Holding:    Duration(order.UpdatedAt.Sub(pos.openedAt)),
+			})
+
+			pos.quantity -= quantity
+			pos.cost -= basis
+			if pos.quantity <= 1e-12 {
+				*pos = position{}
+			}
+		}
+	}
+	return trades
+}
+
+func totalReturn(initial, final float64) float64 {
+	if initial <= 0 {
+		return 0
+	}
+	return final/initial - 1
+}
+
+// annualize : 기간 수익률을 연환산. 기간이 너무 짧아 값이 발산하면 0
+func annualize(total float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 || total <= -1 {
+		return 0
+	}
+	v := math.Pow(1+total, float64(year)/float64(elapsed)) - 1
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
+
+// maxDrawdown : 최대 낙폭과, 고점에서 회복(또는 종료)까지 가장 오래 걸린 기간
+func maxDrawdown(times []time.Time, values []float64) (float64, time.Duration) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var maxDD float64
+	var longest time.Duration
+	peak, peakTime := values[0], times[0]
+	for i, v := range values {
+		if v >= peak {
+			longest = max(longest, times[i].Sub(peakTime))
+			peak, peakTime = v, times[i]
+			continue
+		}
+		if peak > 0 {
+			maxDD = math.Max(maxDD, (peak-v)/peak)
+		}
+	}
+	if values[len(values)-1] < peak {
+		longest = max(longest, times[len(times)-1].Sub(peakTime))
+	}
+	return maxDD, longest
+}
+
+// periodsPerYear : equity curve 간격(중앙값)으로 연간 기간 수를 추정
+func periodsPerYear(times []time.Time) float64 {
+	if len(times) < 2 {
+		return 0
+	}
+	gaps := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return 0
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return float64(year) / float64(gaps[len(gaps)/2])
+}
+
+func periodReturns(values []float64) []float64 {
+	returns := make([]float64, 0, len(values))
+	for i := 1; i < len(values); i++ {
+		if values[i-1] > 0 {
+			returns = append(returns, values[i]/values[i-1]-1)
+		}
+	}
+	return returns
+}
+
+// sharpe : 무위험 수익률 0 기준 연환산 샤프 비율
+func sharpe(returns []float64, ppy float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	mean, std := meanStd(returns)
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(ppy)
+}
+
+// sortino : 하방 편차만 위험으로 보는 연환산 소르티노 비율
+func sortino(returns []float64, ppy float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	mean, _ := meanStd(returns)
+	var downside float64
+	for _, r := range returns {
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	downside = math.Sqrt(downside / float64(len(returns)))
+	if downside == 0 {
+		return 0
+	}
+	return mean / downside * math.Sqrt(ppy)
+}
+
+func meanStd(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)-1))
+}
@@ -0,0 +1,177 @@
+package backtest
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+const (
+	echartsAssetsHost = "https://go-echarts.github.io/go-echarts-assets/assets/"
+	maxChartPoints    = 2000 // 분봉 수개월치를 그대로 그리면 브라우저가 버거워서 샘플링
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{"pct": formatPct}).Parse(`<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="UTF-8">
+<title>Backtest Report</title>
+<script src="{{.EchartsJS}}"></script>
+<style>
+body { font-family: sans-serif; margin: 20px; }
+table { border-collapse: collapse; margin-bottom: 20px; }
+td, th { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th { background: #f4f4f4; text-align: left; }
+</style>
+</head>
+<body>
+<h2>Backtest Report ({{.Period}})</h2>
+<table>
+{{range .Metrics}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{if .Benchmarks}}<h3>Buy &amp; Hold</h3>
+<table>
+<tr><th>Pair</th><th>Return</th><th>Annualized</th><th>Max Drawdown</th></tr>
+{{range .Benchmarks}}<tr><td>{{.Pair}}</td><td>{{pct .TotalReturn}}</td><td>{{pct .AnnualizedReturn}}</td><td>{{pct .MaxDrawdown}}</td></tr>
+{{end}}</table>{{end}}
+{{range .Charts}}{{.Element}}{{.Script}}
+{{end}}
+</body>
+</html>
+`))
+
+type reportMetric struct {
+	Name  string
+	Value string
+}
+
+type reportChart struct {
+	Element template.HTML
+	Script  template.HTML
+}
+
+// WriteHTML : 지표 표와 equity/drawdown 차트(go-echarts)를 담은 HTML 페이지를 씁니다.
+func (r *Report) WriteHTML(w io.Writer) error {
+	data := struct {
+		EchartsJS  string
+		Period     string
+		Metrics    []reportMetric
+		Benchmarks []Benchmark
+		Charts     []reportChart
+	}{
+		EchartsJS:  echartsAssetsHost + opts.EchartsJS,
+		Period:     fmt.Sprintf("%s ~ %s", r.Start.Format(time.DateTime), r.End.Format(time.DateTime)),
+		Metrics:    r.metrics(),
+		Benchmarks: r.Benchmarks,
+	}
+
+	for _, chart := range []*charts.Line{r.equityChart(), r.drawdownChart()} {
+		snippet := chart.RenderSnippet()
+		data.Charts = append(data.Charts, reportChart{
+			Element: template.HTML(snippet.Element),
+			Script:  template.HTML(snippet.Script),
+		})
+	}
+
+	return reportTemplate.Execute(w, data)
+}
+
+func (r *Report) metrics() []reportMetric {
+	return []reportMetric{
+		{"Initial KRW", fmt.Sprintf("%.0f", r.InitialKRW)},
+		{"Final Equity", fmt.Sprintf("%.0f", r.FinalEquity)},
+		{"Total Return", formatPct(r.TotalReturn)},
+		{"Annualized Return", formatPct(r.AnnualizedReturn)},
+		{"Max Drawdown", formatPct(r.MaxDrawdown)},
+		{"Max Drawdown Duration", time.Duration(r.MaxDrawdownDuration).String()},
+		{"Sharpe", fmt.Sprintf("%.2f", r.Sharpe)},
+		{"Sortino", fmt.Sprintf("%.2f", r.Sortino)},
+		{"Calmar", fmt.Sprintf("%.2f", r.Calmar)},
+		{"Trades", fmt.Sprintf("%d", r.Trades)},
+		{"Win Rate", formatPct(r.WinRate)},
+		{"Profit Factor", r.ProfitFactor.String()},
+		{"Avg Holding Time", time.Duration(r.AvgHoldingTime).String()},
+		{"Exposure", formatPct(r.Exposure)},
+	}
+}
+
+func (r *Report) equityChart() *charts.Line {
+	idx := sampleIndexes(len(r.EquityCurve))
+
+	line := newReportLine("Equity")
+	line.SetXAxis(r.sampledTimes(idx))
+	line.AddSeries("Strategy", toLineData(idx, func(i int) float64 { return r.EquityCurve[i].Equity }))
+	for _, b := range r.Benchmarks {
+		curve := r.BenchmarkCurve(b.Pair)
+		if curve == nil {
+			continue // LoadReport 로 읽은 리포트는 캔들이 없음
+		}
+		line.AddSeries("Buy&Hold "+b.Pair, toLineData(idx, func(i int) float64 { return curve[i] }))
+	}
+	return line
+}
+
+func (r *Report) drawdownChart() *charts.Line {
+	idx := sampleIndexes(len(r.EquityCurve))
+	dd := r.DrawdownCurve()
+
+	line := newReportLine("Drawdown (%)")
+	line.SetXAxis(r.sampledTimes(idx))
+	line.AddSeries("Drawdown", toLineData(idx, func(i int) float64 { return 0 - dd[i]*100 }))
+	return line
+}
+
+func newReportLine(title string) *charts.Line {
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Width: "1200px", Height: "450px"}),
+		charts.WithTitleOpts(opts.Title{Title: title}),
+		charts.WithTooltipOpts(opts.Tooltip{Trigger: "axis"}),
+		charts.WithDataZoomOpts(opts.DataZoom{Type: "slider"}),
+		charts.WithYAxisOpts(opts.YAxis{Scale: opts.Bool(true)}),
+	)
+	return line
+}
+
+func (r *Report) sampledTimes(idx []int) []string {
+	out := make([]string, len(idx))
+	for i, j := range idx {
+		out[i] = r.EquityCurve[j].Time.Format("2006-01-02 15:04")
+	}
+	return out
+}
+
+func toLineData(idx []int, value func(i int) float64) []opts.LineData {
+	out := make([]opts.LineData, len(idx))
+	for i, j := range idx {
+		out[i] = opts.LineData{Value: value(j)}
+	}
+	return out
+}
+
+// sampleIndexes : 최대 maxChartPoints 개의 인덱스를 고르게 뽑되 마지막 점은 항상 포함
+func sampleIndexes(n int) []int {
+	if n == 0 {
+		return nil
+	}
+	step := 1
+	if n > maxChartPoints {
+		step = (n + maxChartPoints - 1) / maxChartPoints
+	}
+	idx := make([]int, 0, n/step+1)
+	for i := 0; i < n; i += step {
+		idx = append(idx, i)
+	}
+	if idx[len(idx)-1] != n-1 {
+		idx = append(idx, n-1)
+	}
+	return idx
+}
+
+func formatPct(v float64) string {
+	return fmt.Sprintf("%.2f%%", v*100)
+}
@@ -1,15 +1,15 @@
-package main
+// Package validate : 전략 없이 월별 추세와 MA/RSI/MACD/볼린저 밴드/스토캐스틱 신호만으로 매매해 보는 신호 검증.
+// 예전 programs/backtesting 의 validate 프로그램을 raccoon backtest -validate 로 옮긴 것입니다.
+package validate
 
 import (
 	"fmt"
+	"io"
 	"math"
-	"os"
 	"sort"
 	"time"
 
-	"raccoon/exchange"
 	"raccoon/model"
-	"raccoon/utils/log"
 )
 
 // ======= 타입 정의 =======
@@ -558,7 +558,7 @@ func generateSignals(data []model.Candle, indicators map[string][]float64, trend
 // ======= 4. 백테스트 함수 =======
 
 // backtest는 매매 신호를 기반으로 백테스트를 수행합니다.
-func backtest(data []model.Candle, signals []Signal, initialBalance float64) float64 {
+func backtest(w io.Writer, data []model.Candle, signals []Signal, initialBalance float64) float64 {
 	balance := initialBalance
 	position := 0.0
 	buyPrice := 0.0
@@ -601,9 +601,9 @@ func backtest(data []model.Candle, signals []Signal, initialBalance float64) flo
 					Position:  position,
 					Balance:   balance,
 				})
-				fmt.Printf("매수: %s | 매수가: %.2f\n", candle.Time.Format("2006-01-02"), candle.Close)
+				fmt.Fprintf(w, "매수: %s | 매수가: %.2f\n", candle.Time.Format("2006-01-02"), candle.Close)
 			} else {
-				fmt.Printf("매수 신호 발생: %s | 하지만 잔고가 충분하지 않습니다.\n", candle.Time.Format("2006-01-02"))
+				fmt.Fprintf(w, "매수 신호 발생: %s | 하지만 잔고가 충분하지 않습니다.\n", candle.Time.Format("2006-01-02"))
 			}
 		} else if signal.Action == "SELL" && position > 0 {
 			// 매도 조건: 매도가격이 매수가격보다 높을 때 매도
@@ -621,19 +621,19 @@ func backtest(data []model.Candle, signals []Signal, initialBalance float64) flo
 					Position:  position,
 					Balance:   balance,
 				})
-				fmt.Printf("매도: %s | 매도가: %.2f\n", candle.Time.Format("2006-01-02"), candle.Close)
+				fmt.Fprintf(w, "매도: %s | 매도가: %.2f\n", candle.Time.Format("2006-01-02"), candle.Close)
 
 				// 현재 수익률 계산
 				currentReturn := ((candle.Close - buyPrice) / buyPrice) * 100
-				fmt.Printf("현재 수익률: %.2f%%\n", currentReturn)
+				fmt.Fprintf(w, "현재 수익률: %.2f%%\n", currentReturn)
 
 				// 이전 수익률과의 차이 계산
 				if !firstTrade {
 					deltaReturn := currentReturn - previousReturn
-					fmt.Printf("직전 수익률 대비 차이: %.2f%%\n", deltaReturn)
+					fmt.Fprintf(w, "직전 수익률 대비 차이: %.2f%%\n", deltaReturn)
 				} else {
 					firstTrade = false
-					fmt.Println("직전 수익률 없음 (첫 번째 매도)")
+					fmt.Fprintln(w, "직전 수익률 없음 (첫 번째 매도)")
 				}
 
 				// 이전 수익률 업데이트
@@ -643,7 +643,7 @@ func backtest(data []model.Candle, signals []Signal, initialBalance float64) flo
 					maxBalance = balance
 				}
 			} else {
-				fmt.Printf("매도 조건 발생: %s | 하지만 매도가 %.2f가 매수가 %.2f보다 낮아 판매하지 않음\n",
+				fmt.Fprintf(w, "매도 조건 발생: %s | 하지만 매도가 %.2f가 매수가 %.2f보다 낮아 판매하지 않음\n",
 					candle.Time.Format("2006-01-02"), candle.Close, buyPrice)
 			}
 		}
@@ -669,56 +669,44 @@ func backtest(data []model.Candle, signals []Signal, initialBalance float64) flo
 				Position:  position,
 				Balance:   balance,
 			})
-			fmt.Printf("최종 매도: %s | 매도가: %.2f\n", finalDate.Format("2006-01-02"), finalClose)
-			fmt.Printf("최종 수익률: %.2f%%\n", ((finalClose-buyPrice)/buyPrice)*100)
+			fmt.Fprintf(w, "최종 매도: %s | 매도가: %.2f\n", finalDate.Format("2006-01-02"), finalClose)
+			fmt.Fprintf(w, "최종 수익률: %.2f%%\n", ((finalClose-buyPrice)/buyPrice)*100)
 		} else {
-			fmt.Printf("최종 포지션 보유 중: %s | 현재 가격: %.2f, 매수가: %.2f | 포지션 가치 반영: %.2f\n",
+			fmt.Fprintf(w, "최종 포지션 보유 중: %s | 현재 가격: %.2f, 매수가: %.2f | 포지션 가치 반영: %.2f\n",
 				finalDate.Format("2006-01-02"), finalClose, buyPrice, position*finalClose)
 		}
 	}
 
 	// 거래 내역 출력
-	fmt.Println("\n--- 거래 내역 ---")
+	fmt.Fprintln(w, "\n--- 거래 내역 ---")
 	for idx, trade := range trades {
 		if trade.TradeType == "BUY" {
-			fmt.Printf("거래 %d: 매수\n", idx+1)
-			fmt.Printf("  매수일: %s | 매수가: %.2f\n", trade.BuyDate.Format("2006-01-02"), trade.BuyPrice)
+			fmt.Fprintf(w, "거래 %d: 매수\n", idx+1)
+			fmt.Fprintf(w, "  매수일: %s | 매수가: %.2f\n", trade.BuyDate.Format("2006-01-02"), trade.BuyPrice)
 		} else if trade.TradeType == "SELL" || trade.TradeType == "FINAL_SELL" {
-			fmt.Printf("거래 %d: 매도\n", idx+1)
-			fmt.Printf("  매도일: %s | 매도가: %.2f\n", trade.SellDate.Format("2006-01-02"), trade.SellPrice)
-			fmt.Printf("  수익률: %.2f%%\n", trade.Return)
-			fmt.Printf("  거래 기간: %.0f 일\n", trade.Duration.Hours()/24)
+			fmt.Fprintf(w, "거래 %d: 매도\n", idx+1)
+			fmt.Fprintf(w, "  매도일: %s | 매도가: %.2f\n", trade.SellDate.Format("2006-01-02"), trade.SellPrice)
+			fmt.Fprintf(w, "  수익률: %.2f%%\n", trade.Return)
+			fmt.Fprintf(w, "  거래 기간: %.0f 일\n", trade.Duration.Hours()/24)
 		}
 	}
-	fmt.Println("-----------------")
+	fmt.Fprintln(w, "-----------------")
 
 	totalReturn := ((balance - initialCapital) / initialCapital) * 100
-	fmt.Printf("\n최종 잔고: %.2f\n", balance)
-	fmt.Printf("총 수익률: %.2f%%\n", totalReturn)
-	fmt.Printf("최대 잔고: %.2f\n", maxBalance)
+	fmt.Fprintf(w, "\n최종 잔고: %.2f\n", balance)
+	fmt.Fprintf(w, "총 수익률: %.2f%%\n", totalReturn)
+	fmt.Fprintf(w, "최대 잔고: %.2f\n", maxBalance)
 	return balance
 }
 
-// ======= 5. 메인 함수 =======
+// ======= 5. 실행 =======
 
-func main() {
-	// 환경 변수에서 API 키 가져오기
-	apiKey := os.Getenv("UPBIT_ACCESS_KEY")
-	secretKey := os.Getenv("UPBIT_SECRET_KEY")
-	pairs := []string{"KRW-DOGE"}
-	upbit, err := exchange.NewUpbit(apiKey, secretKey, pairs)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	KSTloc, _ := time.LoadLocation("Asia/Seoul")
-	start := time.Date(2021, time.January, 01, 00, 00, 0, 0, KSTloc)
-	end := time.Date(2025, time.January, 23, 00, 00, 0, 0, KSTloc)
-	data, err := upbit.CandlesByPeriod(pairs[0], "1d", start, end)
-	if err != nil {
-		log.Fatal(err)
-	}
+// TrendThreshold : 월간 수익률이 이 값(%) 이상이면 상승, -이 값 이하면 하락 추세
+const TrendThreshold = 10.0
 
+// Run : data(시간순 캔들, 보통 일봉)로 월별 추세와 지표 신호를 만들어 initialBalance 로 매매해 보고 거래 내역을 w 에 씁니다.
+// 최종 잔고를 반환합니다.
+func Run(w io.Writer, data []model.Candle, initialBalance float64) float64 {
 	// 월별로 데이터를 분류
 	monthlyMap := make(map[string][]model.Candle)
 	for _, candle := range data {
@@ -737,8 +725,7 @@ func main() {
 	}
 
 	// 추세 감지
-	threshold := 10.0 // 임계값 설정 (예: 10%)
-	detectMonthlyTrends(months, threshold)
+	detectMonthlyTrends(months, TrendThreshold)
 
 	// 전체 데이터에 대한 추세 할당 (매일의 추세를 월별로 할당)
 	trends := make([]TrendType, len(data))
@@ -787,9 +774,8 @@ func main() {
 
 	// 매매 신호 생성
 	signals := generateSignals(data, indicators, trends)
-	fmt.Printf("총 매매 신호 수: %d\n", len(signals)) // 신호 수 출력
+	fmt.Fprintf(w, "총 매매 신호 수: %d\n", len(signals)) // 신호 수 출력
 
 	// 백테스트 실행
-	initialBalance := 1000000000.0 // 초기 자본 (예: 1,000,000,000 KRW)
-	backtest(data, signals, initialBalance)
+	return backtest(w, data, signals, initialBalance)
 }
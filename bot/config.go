@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"raccoon/config"
+	"raccoon/exits"
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/journal"
+	"raccoon/notification"
+	"raccoon/portfolio"
+	"raccoon/risk"
+	"raccoon/storage"
+	"raccoon/strategy"
+	"raccoon/utils/log"
+)
+
+// NewRaccoonFromConfig : 설정 파일 내용대로 전략/알림/웹서버/리스크 한도를 구성합니다.
+// mode=paper 면 실제 주문 대신 가상 잔고(paper.initial_krw)로 체결합니다.
+func NewRaccoonFromConfig(cfg *config.Config) (*Raccoon, error) {
+	apiKey, secretKey, credErr := cfg.Credentials()
+	if credErr != nil && cfg.Mode != config.ModePaper {
+		return nil, credErr
+	}
+
+	specs, err := StrategySpecs(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var r *Raccoon
+	if cfg.Mode == config.ModePaper {
+		r, err = NewPaperRaccoon(apiKey, secretKey, specs, cfg.Paper.InitialKRW)
+	} else {
+		r, err = NewRaccoon(apiKey, secretKey, specs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	killSwitch, err := risk.OpenKillSwitch(cfg.KillSwitch.Path)
+	if err != nil {
+		return nil, err
+	}
+	r.SetKillSwitch(killSwitch)
+
+	if cfg.Mode != config.ModePaper && !cfg.Journal.Disabled {
+		j, err := journal.Open(cfg.Journal.Path)
+		if err != nil {
+			return nil, err
+		}
+		r.SetJournal(j)
+	}
+
+	if !cfg.Storage.Disabled {
+		store, err := storage.Open(cfg.Storage.Path)
+		if err != nil {
+			if r.journal != nil {
+				r.journal.Close()
+			}
+			return nil, err
+		}
+		r.SetStorage(store)
+		if err := r.SetExitStore(store); err != nil {
+			if r.journal != nil {
+				r.journal.Close()
+			}
+			store.Close()
+			return nil, err
+		}
+	}
+
+	method, err := portfolio.ParseCostMethod(cfg.Portfolio.CostMethod)
+	if err != nil {
+		return nil, fmt.Errorf("%w: portfolio.cost_method: %v", config.ErrInvalidConfig, err)
+	}
+	r.SetCostMethod(method)
+
+	r.webAddr = cfg.WebServer.Address
+	if cfg.WebServer.Disabled {
+		r.webAddr = ""
+	}
+
+	if token, chatID, ok := cfg.TelegramCredentials(); ok {
+		r.SetNotifier(notification.NewTelegramNotifier(token, chatID))
+		log.Infof("텔레그램 notifier 설정 완료.")
+	} else if cfg.Notifier.Type != "" {
+		log.Warnf("notifier=%s 환경변수(%s, %s)가 설정되지 않았습니다. 알림 기능 미사용.",
+			cfg.Notifier.Type, cfg.Notifier.TokenEnv, cfg.Notifier.ChatIDEnv)
+	}
+
+	r.SetRiskLimits(cfg.Risk)
+	return r, nil
+}
+
+// StrategySpecs : 설정의 전략 선언을 StrategySpec 으로 변환합니다.
+// 종류/파라미터 오류는 실행 전에 모두 모아서 반환합니다.
+func StrategySpecs(cfg *config.Config) ([]StrategySpec, error) {
+	specs := make([]StrategySpec, 0, len(cfg.Strategies))
+	var errs []error
+	for i, sc := range cfg.Strategies {
+		// 주문 피드 없이 한 번 만들어 종류/파라미터를 미리 검증
+		if _, err := buildStrategy(sc, nil); err != nil {
+			errs = append(errs, fmt.Errorf("strategies[%d]: %w", i, err))
+			continue
+		}
+		sc := sc
+		specs = append(specs, StrategySpec{
+			Name:   sc.Name,
+			Pair:   sc.Pair,
+			Weight: sc.Weight,
+			Exits: exits.Policy{
+				StopLoss:     sc.Exits.StopLoss,
+				TakeProfit:   sc.Exits.TakeProfit,
+				TrailingStop: sc.Exits.TrailingStop,
+			},
+			New: func(orderFeed *feed.OrderFeedSubscription) interfaces.Strategy {
+				strat, _ := buildStrategy(sc, orderFeed)
+				return strat
+			},
+		})
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w:\n%w", config.ErrInvalidConfig, errors.Join(errs...))
+	}
+	return specs, nil
+}
+
+func buildStrategy(sc config.StrategyConfig, orderFeed *feed.OrderFeedSubscription) (interfaces.Strategy, error) {
+	strat, err := strategy.New(sc.Type, orderFeed, sc.Params)
+	if err != nil {
+		if errors.Is(err, strategy.ErrUnknownStrategy) {
+			return nil, fmt.Errorf("type: %w", err)
+		}
+		return nil, fmt.Errorf("params: %w", err)
+	}
+	return strategy.WithTimeframe(strat, sc.Timeframe), nil
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"raccoon/exits"
+	"raccoon/utils/log"
+)
+
+// exitTimeframe : 보호 청산을 확인하는 봉. 전략 봉 마감을 기다리지 않도록 진행 중인 1초봉까지 봄
+const exitTimeframe = "1s"
+
+// Exits : 전략 밖에서 관리하는 보호 청산 (손절/익절/트레일링)
+func (r *Raccoon) Exits() *exits.Manager {
+	return r.exits
+}
+
+// SetExitStore : 보호 청산을 store 에 저장하고, 지난 실행에서 남은 청산을 불러옵니다. Start 전에 호출해야 합니다.
+func (r *Raccoon) SetExitStore(store exits.Store) error {
+	m, err := exits.NewManager(store, r.orderFeedSub.Publish)
+	if err != nil {
+		return err
+	}
+	for _, rs := range r.strategies {
+		m.SetPolicy(rs.name, rs.exits)
+	}
+	r.exits = m
+	return nil
+}
+
+// setupExits : 청산 정책이 있거나 지난 실행의 청산이 남아있는 종목만 1초봉으로 감시
+func (r *Raccoon) setupExits() {
+	watched := make(map[string]bool)
+	for _, rs := range r.strategies {
+		if watched[rs.pair] || (rs.exits.Empty() && len(r.exits.Exits(rs.pair)) == 0) {
+			continue
+		}
+		watched[rs.pair] = true
+		r.dataFeedSub.Subscribe(rs.pair, exitTimeframe, r.exits.OnCandle, false)
+		log.Infof("[Exits] watching %s on %s candles", rs.pair, exitTimeframe)
+	}
+	for _, e := range r.exits.Exits("") {
+		if !watched[e.Pair] {
+			log.Warnf("[Exits] %s %s exit for %s is not watched: no running strategy trades %s", e.Strategy, e.Kind, e.Pair, e.Pair)
+		}
+	}
+}
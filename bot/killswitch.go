@@ -0,0 +1,250 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"raccoon/model"
+	"raccoon/notification"
+	"raccoon/risk"
+	"raccoon/utils/log"
+	"raccoon/webserver"
+)
+
+// openOrdersLimit : 킬 스위치가 종목마다 조회/취소하는 미체결 주문 수
+const openOrdersLimit = 100
+
+// killSwitchStrategy : 킬 스위치가 낸 청산 주문의 Strategy
+const killSwitchStrategy = "kill_switch"
+
+// commandListener : 텔레그램처럼 명령(/halt 등)을 받을 수 있는 Notifier
+type commandListener interface {
+	ListenCommands(handler notification.CommandHandler, stop <-chan struct{})
+}
+
+// Halt : 킬 스위치를 켜서 새 주문을 모두 막고, 거래 종목의 미체결 주문을 모두 취소합니다.
+// flatten 이면 보유 코인도 모두 시장가 매도합니다. 킬 스위치는 Resume 전까지 재시작해도 켜진 채로 유지됩니다.
+// source 는 누가 켰는지 (web, telegram, signal 등) 기록용
+func (r *Raccoon) Halt(source, reason string, flatten bool) error {
+	r.haltMu.Lock()
+	defer r.haltMu.Unlock()
+
+	// 취소/청산 중에 새 주문이 나가지 않도록 먼저 켬
+	if err := r.killSwitch.Engage(source, reason, flatten); err != nil {
+		return err
+	}
+	log.Warnf("[KillSwitch] engaged by %s: %s (flatten=%v)", source, reason, flatten)
+
+	// 실행 알고리즘이 다음 자식 주문을 내지 않도록 먼저 중단
+	if stopped := r.executor.Cancel(""); stopped > 0 {
+		log.Warnf("[KillSwitch] stopped %d execution algorithms", stopped)
+	}
+	canceled, errs := r.cancelOpenOrders()
+	var sold []model.Order
+	if flatten {
+		var flattenErrs []error
+		sold, flattenErrs = r.flatten()
+		errs = append(errs, flattenErrs...)
+	}
+	err := errors.Join(errs...)
+
+	state := r.killSwitch.State()
+	r.webServ.OnKillSwitch(killSwitchEvent(state))
+
+	msg := fmt.Sprintf("거래 중지 (킬 스위치)\n출처: %s\n사유: %s\n미체결 주문 취소: %d건", source, reason, canceled)
+	if flatten {
+		msg += fmt.Sprintf("\n시장가 매도: %d건", len(sold))
+		for _, order := range sold {
+			msg += fmt.Sprintf("\n - %s %.8f", order.Pair, order.Quantity)
+		}
+	}
+	if err != nil {
+		msg += fmt.Sprintf("\n오류: %v", err)
+	}
+	r.notify(msg)
+	return err
+}
+
+// Resume : 킬 스위치를 끄고 새 주문을 다시 허용합니다.
+func (r *Raccoon) Resume(source string) error {
+	r.haltMu.Lock()
+	defer r.haltMu.Unlock()
+
+	if err := r.killSwitch.Release(source); err != nil {
+		return err
+	}
+	log.Infof("[KillSwitch] released by %s", source)
+	r.webServ.OnKillSwitch(killSwitchEvent(r.killSwitch.State()))
+	r.notify(fmt.Sprintf("거래 재개 (킬 스위치 해제)\n출처: %s", source))
+	return nil
+}
+
+// KillSwitch : 켜져 있으면 모든 새 주문을 거절하는 전역 중지 스위치
+func (r *Raccoon) KillSwitch() *risk.KillSwitch {
+	return r.killSwitch
+}
+
+// SetKillSwitch : 상태를 파일에 저장하는 킬 스위치로 바꿉니다. Start 전에 호출해야 합니다.
+func (r *Raccoon) SetKillSwitch(k *risk.KillSwitch) {
+	r.killSwitch = k
+	r.risk.SetKillSwitch(k)
+}
+
+// cancelOpenOrders : 거래 종목과 주문 관리자가 추적 중인 종목의 미체결 주문을 모두 취소
+func (r *Raccoon) cancelOpenOrders() (int, []error) {
+	var pairs []string
+	seen := make(map[string]bool)
+	for _, rs := range r.strategies {
+		if !seen[rs.pair] {
+			seen[rs.pair] = true
+			pairs = append(pairs, rs.pair)
+		}
+	}
+	for _, order := range r.orders.OpenOrders("") {
+		if !seen[order.Pair] {
+			seen[order.Pair] = true
+			pairs = append(pairs, order.Pair)
+		}
+	}
+
+	canceled := 0
+	var errs []error
+	for _, pair := range pairs {
+		orders, err := r.exchange.OpenOrders(pair, openOrdersLimit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("open orders %s: %w", pair, err))
+			continue
+		}
+		for _, order := range orders {
+			if err := r.exchange.Cancel(order, false); err != nil {
+				errs = append(errs, fmt.Errorf("cancel %s %s: %w", pair, order.ExchangeID, err))
+				continue
+			}
+			canceled++
+			log.Infof("[KillSwitch] canceled %s %s %s", pair, order.Side, order.ExchangeID)
+		}
+	}
+	return canceled, errs
+}
+
+// flatten : KRW 외의 보유 코인을 모두 시장가 매도. 최소 주문 금액보다 작은 잔고는 건너뜀
+func (r *Raccoon) flatten() ([]model.Order, []error) {
+	account, err := r.exchange.Account()
+	if err != nil {
+		return nil, []error{fmt.Errorf("account: %w", err)}
+	}
+
+	var sold []model.Order
+	var errs []error
+	for _, b := range account.Balances {
+		if b.Currency == "KRW" || b.Balance <= 0 {
+			continue
+		}
+		pair := "KRW-" + strings.ToUpper(b.Currency)
+		if minTotal := r.exchange.AssetsInfo(pair).MinPrice; minTotal > 0 {
+			if quote, err := r.exchange.LastQuote(pair); err == nil && b.Balance*quote < minTotal {
+				log.Warnf("[KillSwitch] skip %s: %.8f is below the minimum order total", pair, b.Balance)
+				continue
+			}
+		}
+		order, err := r.exchange.CreateOrderMarket(model.SideTypeSell, pair, b.Balance)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sell %s: %w", pair, err))
+			continue
+		}
+		order.Strategy = killSwitchStrategy
+		r.orders.Track(order)
+		sold = append(sold, order)
+		log.Warnf("[KillSwitch] market sell %s %.8f (%s)", pair, b.Balance, order.ExchangeID)
+	}
+	return sold, errs
+}
+
+// startKillSwitch : 웹서버/텔레그램에서 킬 스위치를 쓸 수 있게 하고, 켜진 채로 재시작했으면 남은 미체결 주문을 취소합니다.
+func (r *Raccoon) startKillSwitch() {
+	r.webServ.SetKillSwitch(r)
+	state := r.killSwitch.State()
+	r.webServ.OnKillSwitch(killSwitchEvent(state))
+
+	if listener, ok := r.notifier.(commandListener); ok {
+		r.commandStop = make(chan struct{})
+		go listener.ListenCommands(r.handleCommand, r.commandStop)
+	}
+
+	if !state.Engaged {
+		return
+	}
+	log.Warnf("[KillSwitch] still engaged (by %s at %s: %s) - new orders are rejected until resumed",
+		state.Source, state.Time.Format(time.DateTime), state.Reason)
+	canceled, errs := r.cancelOpenOrders()
+	if err := errors.Join(errs...); err != nil {
+		log.Errorf("[KillSwitch] cancel open orders: %v", err)
+	}
+	r.notify(fmt.Sprintf("킬 스위치가 켜진 상태로 시작했습니다. 새 주문은 거절됩니다.\n출처: %s\n사유: %s\n미체결 주문 취소: %d건",
+		state.Source, state.Reason, canceled))
+}
+
+func (r *Raccoon) stopKillSwitch() {
+	if r.commandStop != nil {
+		close(r.commandStop)
+		r.commandStop = nil
+	}
+}
+
+// handleCommand : 텔레그램 명령 처리. /halt [사유], /flatten [사유], /resume, /status
+func (r *Raccoon) handleCommand(command string, args []string) string {
+	reason := strings.Join(args, " ")
+	if reason == "" {
+		reason = "telegram"
+	}
+	var err error
+	switch command {
+	case "halt":
+		err = r.Halt("telegram", reason, false)
+	case "flatten":
+		err = r.Halt("telegram", reason, true)
+	case "resume":
+		err = r.Resume("telegram")
+	case "status":
+		return r.statusMessage()
+	default:
+		return "명령: /halt [사유] 주문 중지+미체결 취소, /flatten [사유] 중지+전량 시장가 매도, /resume 재개, /status 현황"
+	}
+	if err != nil {
+		return fmt.Sprintf("/%s 실패: %v", command, err)
+	}
+	return "" // 결과는 Halt/Resume 이 알림으로 보냄
+}
+
+func (r *Raccoon) statusMessage() string {
+	state := r.killSwitch.State()
+	status := "거래 중"
+	if state.Engaged {
+		status = fmt.Sprintf("거래 중지 (%s, %s): %s", state.Source, state.Time.Format(time.DateTime), state.Reason)
+	}
+	return fmt.Sprintf("상태: %s\n%s", status, r.strategiesInfo())
+}
+
+func (r *Raccoon) notify(message string) {
+	if r.notifier == nil {
+		return
+	}
+	if err := r.notifier.SendNotification(message); err != nil {
+		log.Errorf("Kill switch notification error: %v", err)
+	}
+}
+
+func killSwitchEvent(state risk.KillSwitchState) webserver.KillSwitchEvent {
+	evt := webserver.KillSwitchEvent{
+		Engaged: state.Engaged,
+		Reason:  state.Reason,
+		Source:  state.Source,
+		Flatten: state.Flatten,
+	}
+	if !state.Time.IsZero() {
+		evt.Time = state.Time.UnixMilli()
+	}
+	return evt
+}
@@ -1,68 +1,285 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"raccoon/allocation"
+	"raccoon/config"
 	"raccoon/consumer"
 	"raccoon/exchange"
+	"raccoon/execution"
+	"raccoon/exits"
 	"raccoon/feed"
 	"raccoon/interfaces"
+	"raccoon/journal"
 	"raccoon/model"
+	"raccoon/notification"
+	"raccoon/oms"
+	"raccoon/portfolio"
+	"raccoon/risk"
+	"raccoon/storage"
 	"raccoon/strategy"
+	"raccoon/utils/collection"
 	"raccoon/utils/log"
 	"raccoon/utils/tools"
 	"raccoon/webserver"
+	"sync"
 	"time"
 )
 
+// historyWindow : 시작할 때 차트에 다시 그리는 지난 체결 기간
+const historyWindow = 7 * 24 * time.Hour
+
+// StrategySpec : 봇에서 함께 돌릴 전략 하나
+type StrategySpec struct {
+	Name   string       // 주문 태그이자 예산 이름 (봇 안에서 유일)
+	Pair   string       // 거래 종목
+	Weight float64      // 자본 배분 비중 (0 이면 1)
+	Exits  exits.Policy // 매수 체결 시 붙일 보호 청산. 비어있으면 붙이지 않음
+
+	// New : 전략 생성. orderFeed 는 이 전략 이름으로 주문을 태그하는 핸들
+	New func(orderFeed *feed.OrderFeedSubscription) interfaces.Strategy
+}
+
+// runningStrategy : 실행 중인 전략과 그 전략 전용 컨트롤러/브로커
+type runningStrategy struct {
+	name       string
+	pair       string
+	strat      interfaces.Strategy
+	controller *strategy.Controller
+	broker     *consumer.OrderFeedConsumerBroker
+	exits      exits.Policy
+}
+
 type Raccoon struct {
-	exchange           interfaces.Exchange         // 예: Upbit
-	dataFeedSub        *feed.DataFeedSubscription  // 실시간 캔들 구독
-	orderFeedSub       *feed.OrderFeedSubscription // 주문 신호 발행/구독
-	strat              interfaces.Strategy         // 실제 트레이딩 전략
-	strategyController *strategy.Controller        // StrategyController
-	webServ            *webserver.WebServer        // 차트 그리기 위한 웹서버
-	notifier           interfaces.Notifier
+	exchange         interfaces.Exchange             // 예: Upbit
+	dataFeedSub      *feed.DataFeedSubscription      // 실시간 캔들 구독
+	orderFeedSub     *feed.OrderFeedSubscription     // 주문 신호 발행/구독
+	executionFeedSub *feed.ExecutionFeedSubscription // 거래소 체결/잔고 확인 (private websocket). 모의투자는 nil
+	executionRouter  *consumer.ExecutionRouter       // 주문 종료 이벤트를 주문을 낸 전략 브로커로만 전달
+	marketFeedSub    *feed.MarketFeedSubscription    // 실시간 호가/체결/현재가 (public websocket). 거래소가 지원하지 않으면 nil
+	paper            *exchange.PaperBroker           // 모의투자 브로커. 실거래는 nil
+	strategies       []*runningStrategy              // 동시에 운용하는 전략 (등록 순서)
+	allocator        *allocation.Allocator           // 전략별 KRW 예산 (같은 잔고 중복 사용 방지)
+	webServ          *webserver.WebServer            // 차트 그리기 위한 웹서버
+	webAddr          string                          // 웹서버 주소. 비어있으면 웹서버 미사용
+	notifier         interfaces.Notifier
+	journal          *journal.Journal // 실거래 주문 저널. nil 이면 기록하지 않음
+	orders           *oms.Manager     // 접수된 주문을 체결/취소까지 추적
+	storage          *storage.Storage // 주문/체결/잔고/신호 저장소. nil 이면 저장하지 않음
+	recorder         *storage.Recorder
+	accountant       *portfolio.Accountant // 전략/종목별 실현·미실현 손익
+	risk             *risk.Manager         // 주문을 거래소에 보내기 전의 리스크 검사 (모든 전략 공통)
+	killSwitch       *risk.KillSwitch      // 켜져 있으면 모든 새 주문 거절
+	haltMu           sync.Mutex            // Halt/Resume 직렬화 (웹서버, 텔레그램, 신호에서 동시에 호출될 수 있음)
+	commandStop      chan struct{}         // 텔레그램 명령 수신 중지
+	exits            *exits.Manager        // 포지션 보호 청산 (전략 밖에서 1초봉으로 발동)
+	executor         *execution.Executor   // TWAP/iceberg/chase 주문 실행 (모든 전략 공통)
 }
 
-func NewRaccoon(apiKey, secretKey string, pairs []string) (*Raccoon, error) {
+// NewRaccoon : 실거래 봇. Upbit 에 실제 주문을 냅니다.
+func NewRaccoon(apiKey, secretKey string, specs []StrategySpec) (*Raccoon, error) {
+	pairs, weights, err := validateSpecs(specs)
+	if err != nil {
+		return nil, err
+	}
+
 	upbit, err := exchange.NewUpbit(apiKey, secretKey, pairs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Upbit exchange: %w", err)
 	}
+	r := newRaccoon(upbit, feed.NewExecutionFeed(upbit), specs, weights)
+	r.marketFeedSub = feed.NewMarketFeed(upbit)
+	return r, nil
+}
 
-	dataFeedSub := feed.NewDataFeed(upbit)
+// NewPaperRaccoon : 모의투자 봇. 실시간 Upbit 캔들로 전략을 돌리되 주문은 initialKRW 가상 잔고에서 체결합니다.
+// 공개 시세만 사용하므로 키가 없어도 됩니다.
+func NewPaperRaccoon(apiKey, secretKey string, specs []StrategySpec, initialKRW float64) (*Raccoon, error) {
+	pairs, weights, err := validateSpecs(specs)
+	if err != nil {
+		return nil, err
+	}
+	if initialKRW <= 0 {
+		return nil, fmt.Errorf("paper initial KRW must be positive: %v", initialKRW)
+	}
 
-	orderFeedSub := feed.NewOrderFeed()
+	upbit, err := exchange.NewUpbit(apiKey, secretKey, pairs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Upbit exchange: %w", err)
+	}
+	paper := exchange.NewPaperBroker(upbit, initialKRW)
+
+	// 가상 주문은 private 스트림에 나타나지 않으므로 REST 응답(PaperBroker)으로 체결 확정
+	r := newRaccoon(paper, nil, specs, weights)
+	r.paper = paper
+	// 호가/체결은 공개 시세이므로 모의투자도 실제 스트림을 받음
+	r.marketFeedSub = feed.NewMarketFeed(upbit)
+	r.webServ.SetPaper(true)
+	return r, nil
+}
+
+// validateSpecs : 전략 목록을 검사하고 거래 종목과 전략별 비중을 모읍니다.
+func validateSpecs(specs []StrategySpec) ([]string, map[string]float64, error) {
+	if len(specs) == 0 {
+		return nil, nil, errors.New("at least one strategy is required")
+	}
+
+	pairs := make([]string, 0, len(specs))
+	weights := make(map[string]float64, len(specs))
+	for _, spec := range specs {
+		if spec.Name == "" || spec.Pair == "" || spec.New == nil {
+			return nil, nil, fmt.Errorf("invalid strategy spec: name=%q pair=%q", spec.Name, spec.Pair)
+		}
+		if _, dup := weights[spec.Name]; dup {
+			return nil, nil, fmt.Errorf("duplicate strategy name: %s", spec.Name)
+		}
+		weights[spec.Name] = spec.Weight
+		if spec.Weight <= 0 {
+			weights[spec.Name] = 1
+		}
+		if err := spec.Exits.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("strategy %s exits: %w", spec.Name, err)
+		}
+		if !collection.Contains(pairs, spec.Pair) {
+			pairs = append(pairs, spec.Pair)
+		}
+	}
+	return pairs, weights, nil
+}
 
-	strat := strategy.NewImprovedPSHStrategy(orderFeedSub)
-	ctrl := strategy.NewStrategyController(pairs[0], strat, upbit)
+func newRaccoon(ex interfaces.Exchange, executionFeedSub *feed.ExecutionFeedSubscription, specs []StrategySpec, weights map[string]float64) *Raccoon {
+	dataFeedSub := feed.NewDataFeed(ex)
+
+	orderFeedSub := feed.NewOrderFeed()
 
 	webServ := webserver.NewWebServer()
 
+	allocator := allocation.NewAllocator(ex, weights)
+
+	orders := oms.NewManager(ex)
+
+	accountant := portfolio.NewAccountant(portfolio.CostAverage, ex)
+
+	// 설정 파일이 없으면 킬 스위치 상태는 메모리에만 보관
+	killSwitch, _ := risk.OpenKillSwitch("")
+	riskManager := risk.NewManager(risk.MinOrderTotal(ex))
+	riskManager.SetKillSwitch(killSwitch)
+
+	// 저장소가 없으면 보호 청산은 메모리에만 보관
+	exitManager, _ := exits.NewManager(nil, orderFeedSub.Publish)
+
+	executor := execution.NewExecutor(ex)
+
+	// 전략마다 인스턴스/컨트롤러/예산을 따로 두고, 데이터/주문 피드는 공유
+	strategies := make([]*runningStrategy, 0, len(specs))
+	for _, spec := range specs {
+		strat := spec.New(orderFeedSub.WithStrategy(spec.Name))
+		ctrl := strategy.NewStrategyController(spec.Pair, strat, allocator.Broker(spec.Name, spec.Pair))
+		ctrl.WebServer = webServ
+		if managed, ok := strategy.OrderManaged(strat); ok {
+			managed.SetOrderManager(orders)
+		}
+		strategies = append(strategies, &runningStrategy{
+			name:       spec.Name,
+			pair:       spec.Pair,
+			strat:      strat,
+			controller: ctrl,
+			exits:      spec.Exits,
+		})
+		exitManager.SetPolicy(spec.Name, spec.Exits)
+	}
+
 	return &Raccoon{
-		exchange:           upbit,
-		dataFeedSub:        dataFeedSub,
-		orderFeedSub:       orderFeedSub,
-		strat:              strat,
-		strategyController: ctrl,
-		webServ:            webServ,
-	}, nil
+		exchange:         ex,
+		dataFeedSub:      dataFeedSub,
+		orderFeedSub:     orderFeedSub,
+		executionFeedSub: executionFeedSub,
+		executionRouter:  consumer.NewExecutionRouter(),
+		strategies:       strategies,
+		allocator:        allocator,
+		webServ:          webServ,
+		webAddr:          config.DefaultWebAddress,
+		orders:           orders,
+		accountant:       accountant,
+		risk:             riskManager,
+		killSwitch:       killSwitch,
+		exits:            exitManager,
+		executor:         executor,
+	}
+}
+
+// Mode : config.ModeLive 또는 config.ModePaper
+func (r *Raccoon) Mode() string {
+	if r.paper != nil {
+		return config.ModePaper
+	}
+	return config.ModeLive
 }
 
 func (r *Raccoon) SetupSubscriptions() {
 
-	pair := r.strategyController.Dataframe.Pair
-	timeframe := r.strat.Timeframe()
-	warmup := r.strat.WarmupPeriod()
+	r.fundAllocator()
 
-	consumerStrategy := consumer.NewDataFeedConsumerStrategy(r.strategyController)
-	r.dataFeedSub.Subscribe(
-		pair,
-		timeframe,
-		consumerStrategy.OnCandle,
-		true, // onCandleClose = true → 완성된 봉만 콜백
-	)
+	if r.recorder != nil {
+		r.orders.Subscribe(r.recorder.OnOrderEvent)
+	}
+
+	if r.executionFeedSub != nil {
+		r.executionFeedSub.SubscribeAsset(r.webServ.OnAsset)
+	}
+
+	// 차트는 종목마다 첫 전략의 timeframe 으로 그림
+	charted := make(map[string]bool)
+	warmups := make(map[string]int) // key=pair_timeframe, 같은 캔들을 쓰는 전략 중 가장 긴 warmup
+	for _, rs := range r.strategies {
+		timeframe := rs.strat.Timeframe()
+		if !charted[rs.pair] {
+			charted[rs.pair] = true
+			r.setupChart(rs.pair, timeframe)
+			r.setupRecorder(rs.pair)
+			if r.paper != nil {
+				// 대기 중인 가상 지정가 주문은 실시간 가격으로 매칭
+				r.dataFeedSub.Subscribe(rs.pair, timeframe, r.paper.OnCandle, false)
+			}
+		}
+		r.setupStrategy(rs)
+
+		key := rs.pair + "_" + timeframe
+		if rs.strat.WarmupPeriod() > warmups[key] {
+			warmups[key] = rs.strat.WarmupPeriod()
+		}
+	}
+
+	r.setupExits()
+
+	// Preload 는 같은 종목/timeframe 의 모든 구독자에게 전달되므로 키마다 한 번만
+	for _, rs := range r.strategies {
+		key := rs.pair + "_" + rs.strat.Timeframe()
+		if warmup, ok := warmups[key]; ok {
+			r.preload(rs.pair, rs.strat.Timeframe(), warmup)
+			delete(warmups, key)
+		}
+	}
+}
+
+// fundAllocator : 현재 KRW 잔고를 전략별 예산으로 나눔
+func (r *Raccoon) fundAllocator() {
+	account, err := r.exchange.Account()
+	if err != nil {
+		log.Errorf("failed to fetch account for allocation: %v", err)
+		return
+	}
+	for _, b := range account.Balances {
+		if b.Currency == "KRW" {
+			r.allocator.Fund(b.Balance)
+			return
+		}
+	}
+}
 
+func (r *Raccoon) setupChart(pair, timeframe string) {
 	r.dataFeedSub.Subscribe(
 		pair,
 		timeframe,
@@ -70,59 +287,222 @@ func (r *Raccoon) SetupSubscriptions() {
 		false,
 	)
 
-	consumerBroker := consumer.NewOrderFeedConsumerBroker(r.exchange)
-	r.orderFeedSub.Subscribe(pair, consumerBroker.OnOrder)
+	if r.executionFeedSub != nil {
+		r.executionFeedSub.Subscribe(pair, r.orders.OnExecution)
+		r.executionFeedSub.Subscribe(pair, r.executionRouter.OnExecution)
+		r.executionFeedSub.Subscribe(pair, func(msg model.UpbitMyOrderMessage) {
+			if model.OrderStatusType(msg.State) == model.OrderStatusTypeTrade {
+				r.webServ.OnOrder(exchange.MyOrderToOrder(msg))
+			}
+		})
+	} else {
+		r.orderFeedSub.Subscribe(pair, r.webServ.OnOrder)
+	}
+
+	if r.marketFeedSub != nil && r.webAddr != "" {
+		r.marketFeedSub.SubscribeBook(pair, r.webServ.OnBook)
+		r.marketFeedSub.SubscribeTrade(pair, r.webServ.OnTrade)
+	}
+}
 
-	r.orderFeedSub.Subscribe(pair, r.webServ.OnOrder)
+// setupRecorder : pair 의 신호를 기록하고, 지난 실행의 체결 내역을 차트에 다시 그립니다.
+func (r *Raccoon) setupRecorder(pair string) {
+	if r.recorder == nil {
+		return
+	}
+	r.orderFeedSub.Subscribe(pair, r.recorder.OnSignal)
 
-	if r.notifier != nil {
-		consumerBroker.AddOrderExecutedCallback(func(order model.Order, err error) {
-			r.notifier.OrderNotifier(order, err)
-		})
+	orders, err := r.storage.Orders(context.Background(), storage.Filter{Pair: pair, From: time.Now().Add(-historyWindow)})
+	if err != nil {
+		log.Errorf("[Storage] failed to load order history: %v", err)
+		return
+	}
+	for _, order := range orders {
+		if order.ExecutedQuantity <= 0 {
+			continue
+		}
+		order.Price, order.Quantity = order.AvgPrice, order.ExecutedQuantity
+		r.webServ.OnOrder(order)
+	}
+}
+
+func (r *Raccoon) setupStrategy(rs *runningStrategy) {
+	consumerStrategy := consumer.NewDataFeedConsumerStrategy(rs.controller)
+	r.dataFeedSub.Subscribe(
+		rs.pair,
+		rs.strat.Timeframe(),
+		consumerStrategy.OnCandle,
+		!rs.controller.HighFrequency(), // HighFrequencyStrategy 는 진행 중인 봉도 받음, 나머지는 완성된 봉만
+	)
+
+	// 주문도 전략 예산 안에서만 나가도록 전략 전용 브로커로 실행
+	rs.broker = consumer.NewOrderFeedConsumerBroker(r.allocator.Broker(rs.name, rs.pair))
+	rs.broker.AddOrderExecutedCallback(r.allocator.OnOrderExecuted)
+	rs.broker.AddOrderExecutedCallback(r.exits.OnOrderExecuted)
+	rs.broker.AddOrderExecutedCallback(func(order model.Order, err error) {
+		r.reportStrategy(rs, order, err)
+	})
+	if r.journal != nil {
+		rs.broker.SetJournal(r.journal)
+	}
+	rs.broker.SetOrderManager(r.orders)
+	rs.broker.SetExecutor(r.executor)
+
+	if rs.controller.MarketData() {
+		if r.marketFeedSub != nil {
+			r.marketFeedSub.SubscribeBook(rs.pair, rs.controller.OnBook)
+			r.marketFeedSub.SubscribeTrade(rs.pair, rs.controller.OnTrade)
+		} else {
+			log.Warnf("[Raccoon] strategy %s wants orderbook/trade data but the exchange has no market stream", rs.name)
+		}
+	}
+	rs.broker.SetAccountant(r.accountant)
+	rs.broker.SetRiskManager(r.risk)
+	r.orderFeedSub.SubscribeAck(rs.pair, rs.name, rs.broker.HandleOrder)
+
+	if r.executionFeedSub != nil {
+		// 체결은 거래소 private 스트림으로 확정. 이벤트는 종목마다 하나인 라우터가 주문을 낸 브로커로만 보냄
+		rs.broker.ConfirmByExchange(true)
+		rs.broker.SetExecutionRouter(r.executionRouter)
 	}
+}
 
-	// -------------------------------------------
-	// 미리 WarmupPeriod만큼의 과거캔들 Preload
-	// -------------------------------------------
+// preload : 미리 WarmupPeriod만큼의 과거캔들 Preload
+func (r *Raccoon) preload(pair, timeframe string, warmup int) {
 	dur, err := tools.ParseTimeframeToDuration(timeframe)
 	if err != nil {
 		log.Warnf("Cannot parse timeframe: %v => skip preload", err)
-	} else {
-		KSTLoc, _ := time.LoadLocation("Asia/Seoul")
-		end := time.Now().In(KSTLoc)
-		start := end.Add(-dur * time.Duration(warmup))
-		log.Infof("[Preload] from=%v to=%v warmup=%d timeframe=%s", start, end, warmup, timeframe)
-
-		candles, err := r.exchange.CandlesByPeriod(pair, timeframe, start, end)
-		if err != nil {
-			log.Errorf("failed to load warmup candles: %v", err)
-		} else {
-			r.dataFeedSub.Preload(pair, timeframe, candles)
-			log.Infof("[Preload] loaded %d warmup candles for %s-%s", len(candles), pair, timeframe)
+		return
+	}
+	KSTLoc, _ := time.LoadLocation("Asia/Seoul")
+	end := time.Now().In(KSTLoc)
+	start := end.Add(-dur * time.Duration(warmup))
+	log.Infof("[Preload] %s from=%v to=%v warmup=%d timeframe=%s", pair, start, end, warmup, timeframe)
+
+	candles, err := r.exchange.CandlesByPeriod(pair, timeframe, start, end)
+	if err != nil {
+		log.Errorf("failed to load warmup candles for %s: %v", pair, err)
+		return
+	}
+	r.dataFeedSub.Preload(pair, timeframe, candles)
+	log.Infof("[Preload] loaded %d warmup candles for %s-%s", len(candles), pair, timeframe)
+}
+
+// reportStrategy : 주문 결과를 알림/웹서버로 보내고, 매도 체결 시 전략 손익도 함께 알림
+func (r *Raccoon) reportStrategy(rs *runningStrategy, order model.Order, err error) {
+	stats := r.allocator.Stats(rs.name)
+	r.webServ.OnStrategy(r.strategyEvent(rs, stats))
+
+	var rejection *risk.Rejection
+	if errors.As(err, &rejection) {
+		r.webServ.OnRejection(webserver.RejectionEvent{
+			Pair:     order.Pair,
+			Strategy: rs.name,
+			Side:     string(order.Side),
+			Check:    rejection.Check,
+			Reason:   rejection.Reason,
+		})
+	}
+
+	if r.notifier == nil {
+		return
+	}
+	r.notifier.OrderNotifier(order, err)
+	if err == nil && order.Side == model.SideTypeSell {
+		if sendErr := r.notifier.SendNotification(formatStats(rs, stats)); sendErr != nil {
+			log.Errorf("Strategy PnL notification error: %v", sendErr)
 		}
 	}
 }
 
+func (r *Raccoon) strategyEvent(rs *runningStrategy, stats allocation.Stats) webserver.StrategyEvent {
+	pnl := r.accountant.Summary(rs.name)
+	return webserver.StrategyEvent{
+		Name:       rs.name,
+		Pair:       rs.pair,
+		Timeframe:  rs.strat.Timeframe(),
+		Funded:     stats.Funded,
+		Equity:     stats.Equity,
+		PnL:        stats.PnL,
+		PnLRate:    stats.PnLRate,
+		Trades:     stats.Trades,
+		Realized:   pnl.Realized,
+		Unrealized: pnl.Unrealized,
+		Fees:       pnl.Fees,
+	}
+}
+
+func (r *Raccoon) strategiesInfo() string {
+	info := ""
+	for _, rs := range r.strategies {
+		info += formatStats(rs, r.allocator.Stats(rs.name))
+	}
+	return info + formatSummary(r.accountant.Summary(""))
+}
+
+// formatSummary : 전체 전략의 실현/미실현 손익
+func formatSummary(s portfolio.Summary) string {
+	return fmt.Sprintf("실현 손익: %.2f KRW (오늘 %.2f KRW)\n미실현 손익: %.2f KRW\n수수료: %.2f KRW\n",
+		s.Realized, s.TodayRealized, s.Unrealized, s.Fees)
+}
+
+func formatStats(rs *runningStrategy, stats allocation.Stats) string {
+	return fmt.Sprintf("전략: %s (%s)\n종목: %s\nTimeframe: %s\n예산: %.2f KRW\n평가금액: %.2f KRW\n손익: %.2f KRW (%.2f%%), 체결 %d건\n",
+		rs.name,
+		rs.strat.GetName(),
+		rs.pair,
+		rs.strat.Timeframe(),
+		stats.Funded,
+		stats.Equity,
+		stats.PnL,
+		stats.PnLRate*100,
+		stats.Trades)
+}
+
 func (r *Raccoon) Start() {
-	log.Infof("Raccoon starting...")
+	log.Infof("Raccoon starting... (mode=%s)", r.Mode())
+
+	r.reconcileJournal()
 
 	r.SetupSubscriptions()
 
-	//TODO private websocket 열어서 매매결과도 받아와야함
 	r.exchange.Start()
 
 	r.dataFeedSub.Start(false)
 
+	if r.marketFeedSub != nil {
+		r.marketFeedSub.Start()
+	}
+
 	r.orderFeedSub.Start()
 
-	r.strategyController.Start()
+	if r.executionFeedSub != nil {
+		r.executionFeedSub.Start()
+	}
+
+	r.orders.Start(oms.DefaultPollInterval)
 
-	go func() {
-		err := r.webServ.Start(":3030")
-		if err != nil {
-			log.Error("Webserver error:", err)
-		}
-	}()
+	r.executor.Start(execution.DefaultInterval)
+
+	r.startKillSwitch()
+
+	if r.recorder != nil {
+		r.recorder.Start(storage.DefaultSnapshotInterval)
+	}
+
+	for _, rs := range r.strategies {
+		rs.controller.Start()
+		r.webServ.OnStrategy(r.strategyEvent(rs, r.allocator.Stats(rs.name)))
+	}
+
+	if r.webAddr != "" {
+		go func() {
+			err := r.webServ.Start(r.webAddr)
+			if err != nil {
+				log.Error("Webserver error:", err)
+			}
+		}()
+	}
 
 	account, err := r.exchange.Account()
 	var accountInfoMsg string
@@ -138,10 +518,7 @@ func (r *Raccoon) Start() {
 	}
 	log.Infof(accountInfoMsg)
 
-	strategyInfo := fmt.Sprintf("종목: %s\nTimeframe: %s\nStrategy: %s\n",
-		r.strategyController.Dataframe.Pair,
-		r.strat.Timeframe(),
-		r.strat.GetName())
+	strategyInfo := r.strategiesInfo()
 
 	if r.notifier != nil {
 		notifyMsg := fmt.Sprintf("Raccoon started successfully.\n%s\n%s", accountInfoMsg, strategyInfo)
@@ -156,10 +533,32 @@ func (r *Raccoon) Start() {
 func (r *Raccoon) Stop() {
 	log.Infof("Raccoon stopping...")
 
+	r.stopKillSwitch()
+
 	r.dataFeedSub.Stop()
 
+	if r.marketFeedSub != nil {
+		r.marketFeedSub.Stop()
+	}
+
 	r.orderFeedSub.Stop()
 
+	if r.executionFeedSub != nil {
+		r.executionFeedSub.Stop()
+	}
+
+	// 거래소에 지정가 자식 주문이 남지 않도록 취소
+	r.executor.Stop()
+
+	r.orders.Stop()
+
+	if r.recorder != nil {
+		if err := r.recorder.Snapshot(); err != nil {
+			log.Warnf("[Storage] %v", err)
+		}
+		r.recorder.Stop()
+	}
+
 	account, err := r.exchange.Account()
 	var accountInfoMsg string
 	if err != nil {
@@ -172,10 +571,24 @@ func (r *Raccoon) Stop() {
 		}
 	}
 
+	strategyInfo := r.strategiesInfo()
+
 	r.exchange.Stop()
 
+	if r.journal != nil {
+		if err := r.journal.Close(); err != nil {
+			log.Errorf("Failed to close journal: %v", err)
+		}
+	}
+
+	if r.storage != nil {
+		if err := r.storage.Close(); err != nil {
+			log.Errorf("Failed to close storage: %v", err)
+		}
+	}
+
 	if r.notifier != nil {
-		notifyMsg := fmt.Sprintf("Raccoon stopped.\n%s", accountInfoMsg)
+		notifyMsg := fmt.Sprintf("Raccoon stopped.\n%s\n%s", accountInfoMsg, strategyInfo)
 		if err := r.notifier.SendNotification(notifyMsg); err != nil {
 			log.Errorf("Stop notification error: %v", err)
 		}
@@ -184,6 +597,101 @@ func (r *Raccoon) Stop() {
 	log.Infof("Raccoon stopped.")
 }
 
+// OrderManager : 주문 상태 조회/이벤트 구독용 주문 관리자
+func (r *Raccoon) OrderManager() *oms.Manager {
+	return r.orders
+}
+
+// MarketFeed : 실시간 호가/체결/현재가 피드. 거래소가 지원하지 않으면 nil
+func (r *Raccoon) MarketFeed() *feed.MarketFeedSubscription {
+	return r.marketFeedSub
+}
+
+// Executor : 실행 중인 TWAP/iceberg/chase 주문 조회/중단용
+func (r *Raccoon) Executor() *execution.Executor {
+	return r.executor
+}
+
+// SetJournal : 실거래 주문을 저널에 기록합니다. Start 전에 호출해야 합니다.
+func (r *Raccoon) SetJournal(j *journal.Journal) {
+	r.journal = j
+}
+
+// Accountant : 전략/종목별 손익 조회용 포트폴리오 회계
+func (r *Raccoon) Accountant() *portfolio.Accountant {
+	return r.accountant
+}
+
+// SetCostMethod : 실현 손익의 매입 원가 계산 방식을 바꿉니다. Start 전에 호출해야 합니다.
+func (r *Raccoon) SetCostMethod(method portfolio.CostMethod) {
+	r.accountant = portfolio.NewAccountant(method, r.exchange)
+}
+
+// RiskManager : 주문 전 리스크 검사
+func (r *Raccoon) RiskManager() *risk.Manager {
+	return r.risk
+}
+
+// SetRiskLimits : 주문 전 리스크 한도를 추가합니다. 0 인 한도는 검사하지 않고, 거래소 최소 주문 금액은 항상 검사합니다.
+// 하루 손실 한도는 현재 포트폴리오 회계를 사용하므로 SetCostMethod 뒤, Start 전에 한 번만 호출해야 합니다.
+func (r *Raccoon) SetRiskLimits(limits config.RiskConfig) {
+	r.allocator.MaxOrderKRW = limits.MaxOrderKRW
+
+	var checks []risk.Check
+	if limits.MaxPositionKRW > 0 {
+		checks = append(checks, risk.MaxPositionValue(limits.MaxPositionKRW, r.exchange))
+	}
+	if limits.MaxOrderEquityRate > 0 {
+		checks = append(checks, risk.MaxOrderEquityRate(limits.MaxOrderEquityRate, r.exchange))
+	}
+	if limits.MaxOrdersPerMinute > 0 {
+		checks = append(checks, risk.MaxOrdersPerMinute(limits.MaxOrdersPerMinute, nil))
+	}
+	if limits.MaxDailyLossKRW > 0 {
+		checks = append(checks, risk.MaxDailyLoss(limits.MaxDailyLossKRW, r.accountant))
+	}
+	r.risk.Add(checks...)
+}
+
+// SetStorage : 주문/체결/잔고 스냅샷/전략 신호를 저장소에 기록합니다. Start 전에 호출해야 합니다.
+func (r *Raccoon) SetStorage(store *storage.Storage) {
+	r.storage = store
+	r.recorder = storage.NewRecorder(store, r.exchange, r.paper != nil)
+}
+
+// reconcileJournal : 지난 실행에서 끝나지 않은 주문을 거래소 주문과 맞춥니다. 주문을 다시 보내지는 않습니다.
+func (r *Raccoon) reconcileJournal() {
+	if r.journal == nil {
+		return
+	}
+	changed, err := r.journal.Reconcile(r.exchange)
+	for _, rec := range changed {
+		log.Infof("[Journal] %s %s %s %s → %s", rec.Identifier, rec.Order.Strategy, rec.Order.Pair, rec.Order.Side, rec.Status)
+	}
+	if err != nil {
+		log.Errorf("[Journal] %v", err)
+	}
+
+	open := r.journal.Open()
+	if len(open) == 0 && err == nil {
+		return
+	}
+	msg := fmt.Sprintf("[Journal] 재시작 전 주문 확인: 변경 %d건, 미종료 %d건", len(changed), len(open))
+	if err != nil {
+		msg += fmt.Sprintf("\n확인 실패: %v", err)
+	}
+	log.Warnf(msg)
+	if r.notifier != nil {
+		if sendErr := r.notifier.SendNotification(msg); sendErr != nil {
+			log.Errorf("Journal notification error: %v", sendErr)
+		}
+	}
+}
+
+// SetNotifier : 모의투자 모드면 모든 알림에 모의투자 표시를 붙입니다.
 func (r *Raccoon) SetNotifier(notifier interfaces.Notifier) {
+	if r.paper != nil && notifier != nil {
+		notifier = notification.NewPaperNotifier(notifier)
+	}
 	r.notifier = notifier
 }
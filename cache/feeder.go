@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"raccoon/model"
+)
+
+var ErrNoCachedCandles = errors.New("cache: no cached candles")
+
+// Feeder : 캐시된 캔들만으로 동작하는 interfaces.DataFeeder.
+// API 키나 네트워크 없이 백테스트 엔진에 과거 데이터를 공급할 때 사용합니다.
+type Feeder struct {
+	store *CandleStore
+}
+
+func NewFeeder(store *CandleStore) *Feeder {
+	return &Feeder{store: store}
+}
+
+func (f *Feeder) Start() {}
+func (f *Feeder) Stop()  {}
+
+func (f *Feeder) AssetsInfo(pair string) model.AssetInfo {
+	return model.AssetInfo{}
+}
+
+// LastQuote : 캐시된 타임프레임 중 가장 짧은 것의 마지막 종가
+func (f *Feeder) LastQuote(pair string) (float64, error) {
+	timeframes, err := f.store.Timeframes(pair)
+	if err != nil {
+		return 0, err
+	}
+	for _, timeframe := range timeframes {
+		candles, err := f.store.Last(pair, timeframe, 1)
+		if err != nil {
+			return 0, err
+		}
+		if len(candles) > 0 {
+			return candles[0].Close, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrNoCachedCandles, pair)
+}
+
+func (f *Feeder) CandlesByLimit(pair, period string, limit int) ([]model.Candle, error) {
+	return f.store.Last(pair, period, limit)
+}
+
+// CandlesByPeriod : 캐시에 없는 구간이 있으면 가진 만큼만 돌려줍니다.
+// 빠진 구간은 CandleStore.Missing 으로 확인할 수 있습니다.
+func (f *Feeder) CandlesByPeriod(pair, period string, start, end time.Time) ([]model.Candle, error) {
+	candles, err := f.store.Load(pair, period, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("%w: %s-%s %v ~ %v", ErrNoCachedCandles, pair, period, start, end)
+	}
+	return candles, nil
+}
+
+// CandlesSubscription : 실시간 스트림은 제공하지 않습니다.
+func (f *Feeder) CandlesSubscription(pair, timeframe string) (chan model.Candle, chan error) {
+	cCandle := make(chan model.Candle)
+	cErr := make(chan error, 1)
+	cErr <- fmt.Errorf("cache: realtime subscription not supported (%s-%s)", pair, timeframe)
+	return cCandle, cErr
+}
@@ -0,0 +1,366 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"raccoon/model"
+	"raccoon/utils/tools"
+)
+
+var ErrUnsupportedTimeframe = errors.New("cache: unsupported timeframe")
+
+// MaxCacheableDuration : 이보다 긴 봉(주/월봉)은 시작 시각이 고정 간격이 아니어서 캐시하지 않습니다.
+const MaxCacheableDuration = 24 * time.Hour
+
+// Range : [Start, End) 반열린 구간. 봉 시작 시각 기준
+type Range struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// CandleStore : pair/timeframe 별로 완성된 캔들을 디스크에 보관합니다.
+//
+//	<dir>/<pair>/<timeframe>.jsonl        캔들 (시간순, 한 줄에 하나)
+//	<dir>/<pair>/<timeframe>.ranges.json  이미 받아온 구간 목록
+//
+// 거래가 없던 구간은 업비트가 봉을 주지 않기 때문에, 봉 유무가 아니라
+// "받아온 구간" 기준으로 다시 받을지 판단합니다.
+type CandleStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewCandleStore(dir string) (*CandleStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: create dir: %w", err)
+	}
+	return &CandleStore{dir: dir}, nil
+}
+
+// Cacheable : 캐시 가능한 타임프레임이면 봉 길이를 반환
+func Cacheable(timeframe string) (time.Duration, bool) {
+	dur, err := tools.ParseTimeframeToDuration(timeframe)
+	if err != nil || dur <= 0 || dur > MaxCacheableDuration {
+		return 0, false
+	}
+	return dur, true
+}
+
+// SlotRange : 닫힌 구간 [start, end]에 시작 시각이 들어가는 봉들을 덮는 반열린 구간
+func SlotRange(start, end time.Time, dur time.Duration) Range {
+	first := start.Truncate(dur)
+	if first.Before(start) {
+		first = first.Add(dur)
+	}
+	return Range{Start: first, End: end.Truncate(dur).Add(dur)}
+}
+
+// Load : [start, end] 사이에 시작한 캔들을 시간순으로 반환
+func (s *CandleStore) Load(pair, timeframe string, start, end time.Time) ([]model.Candle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candles, err := s.readCandles(pair, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	lo := sort.Search(len(candles), func(i int) bool { return !candles[i].Time.Before(start) })
+	hi := sort.Search(len(candles), func(i int) bool { return candles[i].Time.After(end) })
+	if lo >= hi {
+		return nil, nil
+	}
+	return candles[lo:hi], nil
+}
+
+// Last : 저장된 캔들 중 마지막 limit개
+func (s *CandleStore) Last(pair, timeframe string, limit int) ([]model.Candle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candles, err := s.readCandles(pair, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
+// Save : r 구간을 받아왔다고 기록하고 캔들을 병합합니다. 같은 시각의 봉은 새 값으로 덮어씁니다.
+func (s *CandleStore) Save(pair, timeframe string, r Range, candles []model.Candle) error {
+	if _, ok := Cacheable(timeframe); !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedTimeframe, timeframe)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.readCandles(pair, timeframe)
+	if err != nil {
+		return err
+	}
+	byTime := make(map[int64]model.Candle, len(existing)+len(candles))
+	for _, c := range existing {
+		byTime[c.Time.UnixNano()] = c
+	}
+	for _, c := range candles {
+		if c.Time.Before(r.Start) || !c.Time.Before(r.End) {
+			continue
+		}
+		c.Complete = true
+		byTime[c.Time.UnixNano()] = c
+	}
+
+	merged := make([]model.Candle, 0, len(byTime))
+	for _, c := range byTime {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+
+	if err := s.writeCandles(pair, timeframe, merged); err != nil {
+		return err
+	}
+
+	ranges, err := s.readRanges(pair, timeframe)
+	if err != nil {
+		return err
+	}
+	return s.writeRanges(pair, timeframe, mergeRanges(append(ranges, r)))
+}
+
+// Coverage : 이미 받아온 구간 목록 (겹치지 않게 병합된 상태)
+func (s *CandleStore) Coverage(pair, timeframe string) ([]Range, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readRanges(pair, timeframe)
+}
+
+// Timeframes : pair 로 캔들을 저장한 타임프레임 목록 (짧은 봉부터)
+func (s *CandleStore) Timeframes(pair string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, strings.ToUpper(pair)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache: list timeframes: %w", err)
+	}
+
+	durations := make(map[string]time.Duration)
+	var timeframes []string
+	for _, entry := range entries {
+		timeframe, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		dur, ok := Cacheable(timeframe)
+		if !ok {
+			continue
+		}
+		durations[timeframe] = dur
+		timeframes = append(timeframes, timeframe)
+	}
+	sort.Slice(timeframes, func(i, j int) bool {
+		return durations[timeframes[i]] < durations[timeframes[j]]
+	})
+	return timeframes, nil
+}
+
+// Missing : r 중 아직 받아오지 않은 구간들
+func (s *CandleStore) Missing(pair, timeframe string, r Range) ([]Range, error) {
+	covered, err := s.Coverage(pair, timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []Range
+	cursor := r.Start
+	for _, c := range covered {
+		if !c.End.After(cursor) {
+			continue
+		}
+		if !c.Start.Before(r.End) {
+			break
+		}
+		if c.Start.After(cursor) {
+			missing = append(missing, Range{Start: cursor, End: c.Start})
+		}
+		cursor = c.End
+	}
+	if cursor.Before(r.End) {
+		missing = append(missing, Range{Start: cursor, End: r.End})
+	}
+	return missing, nil
+}
+
+// Gaps : 받아온 구간 안에서 봉이 비어 있는 구간들.
+// 거래가 없었던 시간이거나 데이터가 깨진 경우로, 전략이 연속된 봉을 가정한다면 확인이 필요합니다.
+func (s *CandleStore) Gaps(pair, timeframe string, r Range) ([]Range, error) {
+	dur, ok := Cacheable(timeframe)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTimeframe, timeframe)
+	}
+	covered, err := s.Coverage(pair, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	candles, err := s.Load(pair, timeframe, r.Start, r.End.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[int64]bool, len(candles))
+	for _, c := range candles {
+		present[c.Time.UnixNano()] = true
+	}
+
+	var gaps []Range
+	for _, c := range covered {
+		from, to := maxTime(c.Start, r.Start), minTime(c.End, r.End)
+		for t := from; t.Before(to); t = t.Add(dur) {
+			if present[t.UnixNano()] {
+				continue
+			}
+			if n := len(gaps); n > 0 && gaps[n-1].End.Equal(t) {
+				gaps[n-1].End = t.Add(dur)
+			} else {
+				gaps = append(gaps, Range{Start: t, End: t.Add(dur)})
+			}
+		}
+	}
+	return gaps, nil
+}
+
+func (s *CandleStore) path(pair, timeframe, ext string) string {
+	return filepath.Join(s.dir, strings.ToUpper(pair), timeframe+ext)
+}
+
+func (s *CandleStore) readCandles(pair, timeframe string) ([]model.Candle, error) {
+	f, err := os.Open(s.path(pair, timeframe, ".jsonl"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache: open candles: %w", err)
+	}
+	defer f.Close()
+
+	var candles []model.Candle
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var c model.Candle
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("cache: decode candle: %w", err)
+		}
+		candles = append(candles, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cache: read candles: %w", err)
+	}
+	return candles, nil
+}
+
+func (s *CandleStore) writeCandles(pair, timeframe string, candles []model.Candle) error {
+	return writeAtomic(s.path(pair, timeframe, ".jsonl"), func(w *bufio.Writer) error {
+		enc := json.NewEncoder(w)
+		for _, c := range candles {
+			if err := enc.Encode(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *CandleStore) readRanges(pair, timeframe string) ([]Range, error) {
+	data, err := os.ReadFile(s.path(pair, timeframe, ".ranges.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache: read ranges: %w", err)
+	}
+	var ranges []Range
+	if err := json.Unmarshal(data, &ranges); err != nil {
+		return nil, fmt.Errorf("cache: decode ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+func (s *CandleStore) writeRanges(pair, timeframe string, ranges []Range) error {
+	return writeAtomic(s.path(pair, timeframe, ".ranges.json"), func(w *bufio.Writer) error {
+		return json.NewEncoder(w).Encode(ranges)
+	})
+}
+
+// writeAtomic : 임시 파일에 쓴 뒤 rename 해서, 쓰는 도중 죽어도 기존 파일이 깨지지 않게 합니다.
+func writeAtomic(path string, write func(w *bufio.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cache: create dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("cache: create temp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err := write(w); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cache: write: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cache: flush: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cache: close: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func mergeRanges(ranges []Range) []Range {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start.Before(ranges[j].Start) })
+
+	var merged []Range
+	for _, r := range ranges {
+		if !r.End.After(r.Start) {
+			continue
+		}
+		if n := len(merged); n > 0 && !r.Start.After(merged[n-1].End) {
+			if r.End.After(merged[n-1].End) {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
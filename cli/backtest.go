@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"raccoon/backtest"
+	"raccoon/backtest/validate"
+	"raccoon/config"
+	"raccoon/data"
+	"raccoon/model"
+	"raccoon/strategy"
+	"raccoon/utils/log"
+)
+
+func runBacktest(args []string, stdout io.Writer) error {
+	fs, common := newFlagSet("backtest", stdout)
+	strat := addStrategyFlags(fs)
+	period := addPeriodFlags(fs)
+	source := addFeederFlags(fs)
+	krw := fs.Float64("krw", DefaultInitialKRW, "시작 KRW 잔고")
+	out := fs.String("out", "backtest_report", "리포트 파일 접두사 (<out>.json, <out>.html). 비우면 저장하지 않음")
+	validation := fs.Bool("validate", false, "전략 대신 월별 추세 + 지표 신호 검증을 실행 (-timeframe 을 비우면 1d)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *krw <= 0 {
+		return fmt.Errorf("%w: -krw must be positive", ErrUsage)
+	}
+
+	cfg, err := common.loadConfig()
+	if err != nil {
+		return err
+	}
+	sc, err := strat.resolve(cfg)
+	if err != nil {
+		return err
+	}
+	start, end, err := period.parse()
+	if err != nil {
+		return err
+	}
+	if *validation {
+		timeframe := strat.timeframe
+		if timeframe == "" {
+			timeframe = "1d"
+		}
+		return runValidate(common, cfg, source, sc.Pair, timeframe, start, end, *krw, stdout)
+	}
+	feeder, err := source.feeder(common, cfg, sc.Pair, sc.Timeframe)
+	if err != nil {
+		return err
+	}
+
+	engine := backtest.NewEngine(feeder, *krw)
+	instance, err := strategy.New(sc.Type, engine.OrderFeed(), sc.Params)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	engine.AddStrategy(sc.Pair, strategy.WithTimeframe(instance, sc.Timeframe))
+	engine.AddOrderExecutedCallback(func(order model.Order, err error) {
+		if err != nil {
+			return
+		}
+		log.Infof("[%s] Executed %s: %.6f @ %.2f", order.UpdatedAt.Format(time.DateTime), order.Side, order.Quantity, order.Price)
+	})
+
+	log.Infof("[Backtest] %s %s-%s %s ~ %s", sc.Type, sc.Pair, sc.Timeframe, start.Format(time.DateTime), end.Format(time.DateTime))
+	result, err := engine.Run(start, end)
+	if err != nil {
+		return err
+	}
+	report := backtest.NewReport(result)
+
+	if *out != "" {
+		if err := saveReport(report, *out); err != nil {
+			return err
+		}
+	}
+
+	coin, cash, _, _ := engine.Broker().Position(sc.Pair)
+	fmt.Fprintf(stdout, "Strategy: %s (%s) %s-%s params=[%s]\n", sc.Name, sc.Type, sc.Pair, sc.Timeframe, formatParams(sc.Params))
+	fmt.Fprintf(stdout, "Final KRW balance: %.2f\nFinal Coin holdings: %.6f\nOrders: %d (rejected %d)\n",
+		cash, coin, len(result.Orders), result.Rejected)
+	return writeSummary(stdout, []string{sc.Name}, []*backtest.Report{report})
+}
+
+// runValidate : 전략 엔진 없이 지표 신호만으로 매매해 보는 검증 (backtest -validate)
+func runValidate(common *commonFlags, cfg *config.Config, source *feederFlags, pair, timeframe string, start, end time.Time, krw float64, stdout io.Writer) error {
+	feeder, err := source.feeder(common, cfg, pair, timeframe)
+	if err != nil {
+		return err
+	}
+	candles, err := feeder.CandlesByPeriod(pair, timeframe, start, end)
+	if err != nil {
+		return fmt.Errorf("load candles %s-%s: %w", pair, timeframe, err)
+	}
+	if len(candles) == 0 {
+		return fmt.Errorf("no candles for %s-%s between %s and %s", pair, timeframe, start.Format(time.DateTime), end.Format(time.DateTime))
+	}
+	sort.SliceStable(candles, func(i, j int) bool {
+		return candles[i].Time.Before(candles[j].Time)
+	})
+
+	fmt.Fprintf(stdout, "Validate: %s-%s %s ~ %s (%d candles)\n", pair, timeframe, start.Format(time.DateTime), end.Format(time.DateTime), len(candles))
+	validate.Run(stdout, candles, krw)
+	return nil
+}
+
+// saveReport : <prefix>.json 과 <prefix>.html 을 씁니다.
+func saveReport(report *backtest.Report, prefix string) error {
+	raw, err := report.JSON()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(prefix+".json", raw, 0o644); err != nil {
+		return err
+	}
+	if err := writeFile(prefix+".html", report.WriteHTML); err != nil {
+		return err
+	}
+	log.Infof("[Backtest] report saved: %s.json, %s.html", prefix, prefix)
+	return nil
+}
+
+// writeSummary : 리포트들의 주요 지표를 표로 출력합니다.
+func writeSummary(w io.Writer, names []string, reports []*backtest.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "report\tperiod\tfinal equity\treturn\tB&H\tMDD\tsharpe\ttrades\twin rate\t")
+	for i, r := range reports {
+		buyAndHold := "-"
+		if len(r.Benchmarks) > 0 {
+			buyAndHold = fmt.Sprintf("%.2f%%", r.Benchmarks[0].TotalReturn*100)
+		}
+		fmt.Fprintf(tw, "%s\t%s ~ %s\t%.0f\t%.2f%%\t%s\t%.2f%%\t%.2f\t%d\t%.2f%%\t\n",
+			names[i], r.Start.Format(time.DateOnly), r.End.Format(time.DateOnly), r.FinalEquity,
+			r.TotalReturn*100, buyAndHold, r.MaxDrawdown*100, r.Sharpe, r.Trades, r.WinRate*100)
+	}
+	return tw.Flush()
+}
+
+func runFetch(args []string, stdout io.Writer) error {
+	fs, common := newFlagSet("fetch", stdout)
+	pair := fs.String("pair", "", "종목 (예: KRW-XRP). 비우면 설정 파일의 모든 종목")
+	timeframe := fs.String("timeframe", "1m", "캔들 주기")
+	period := addPeriodFlags(fs)
+	out := fs.String("out", "", "받은 캔들을 파일(.csv/.jsonl)로도 저장. 종목이 하나일 때만")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := common.loadConfig()
+	if err != nil {
+		return err
+	}
+	start, end, err := period.parse()
+	if err != nil {
+		return err
+	}
+
+	pairs := []string{"KRW-XRP"}
+	switch {
+	case *pair != "":
+		pairs = []string{*pair}
+	case cfg != nil:
+		pairs = cfg.Pairs()
+	}
+	if *out != "" && len(pairs) != 1 {
+		return fmt.Errorf("%w: -out needs a single -pair", ErrUsage)
+	}
+
+	source := &feederFlags{}
+	for _, p := range pairs {
+		feeder, err := source.feeder(common, cfg, p, *timeframe)
+		if err != nil {
+			return err
+		}
+		candles, err := feeder.CandlesByPeriod(p, *timeframe, start, end)
+		if err != nil {
+			return fmt.Errorf("fetch %s-%s: %w", p, *timeframe, err)
+		}
+		fmt.Fprintf(stdout, "%s-%s: %d candles (%s ~ %s) -> %s\n",
+			p, *timeframe, len(candles), start.Format(time.DateTime), end.Format(time.DateTime), common.cacheDir)
+		if *out != "" {
+			if err := data.SaveFile(*out, candles); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runReport : 저장된 리포트 JSON 들을 요약하고, -html 이 있으면 첫 리포트를 HTML 로 다시 그립니다.
+// JSON 에는 원본 캔들이 없으므로 다시 그린 HTML 에는 Buy&Hold 곡선이 빠집니다.
+func runReport(args []string, stdout io.Writer) error {
+	fs, common := newFlagSet("report", stdout)
+	html := fs.String("html", "", "HTML 출력 경로")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: raccoon report [flags] <report.json>...")
+		fs.PrintDefaults()
+	}
+	if err := parseFlagsWithArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: at least one report json is required", ErrUsage)
+	}
+	if _, err := common.loadConfig(); err != nil {
+		return err
+	}
+
+	reports := make([]*backtest.Report, 0, fs.NArg())
+	for _, path := range fs.Args() {
+		report, err := backtest.LoadReport(path)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+	if *html != "" {
+		if err := writeFile(*html, reports[0].WriteHTML); err != nil {
+			return err
+		}
+	}
+	return writeSummary(stdout, fs.Args(), reports)
+}
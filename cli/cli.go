@@ -0,0 +1,383 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"raccoon/cache"
+	"raccoon/config"
+	"raccoon/data"
+	"raccoon/exchange"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/strategy"
+	"raccoon/utils/log"
+)
+
+var ErrUsage = errors.New("usage error")
+
+// DefaultInitialKRW : 백테스트/최적화 기본 시작 자본
+const DefaultInitialKRW = 100_000_000.0
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer) error
+}
+
+// 서브커맨드 목록 (usage 출력 순서)
+var commands = []command{
+	{"run", "실거래 봇 실행", runLive},
+	{"paper", "가상 잔고로 모의투자 실행", runPaper},
+	{"backtest", "과거 캔들로 전략 백테스트", runBacktest},
+	{"fetch", "과거 캔들을 받아 로컬 캐시에 저장", runFetch},
+	{"optimize", "파라미터 탐색 / walk-forward 분석", runOptimize},
+	{"report", "저장된 백테스트 리포트 요약 및 HTML 변환", runReport},
+	{"strategies", "사용 가능한 전략과 기본 파라미터 출력", runStrategies},
+}
+
+// Run : raccoon <command> [flags] 를 실행하고 프로세스 종료 코드를 반환합니다.
+// 서브커맨드 없이 플래그만 주면 기존처럼 실거래(run)로 동작합니다.
+func Run(args []string, stdout, stderr io.Writer) int {
+	name := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		writeUsage(stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(args, stdout)
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, ErrUsage):
+			fmt.Fprintf(stderr, "raccoon %s: %v\n", name, err)
+			return 2
+		default:
+			fmt.Fprintf(stderr, "raccoon %s: %v\n", name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "raccoon: unknown command %q\n\n", name)
+	writeUsage(stderr)
+	return 2
+}
+
+func writeUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: raccoon <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "공통 플래그: -config, -log-level, -cache-dir. 자세한 내용은 raccoon <command> -h")
+}
+
+// commonFlags : 모든 서브커맨드가 공유하는 플래그
+type commonFlags struct {
+	configPath string
+	logLevel   string
+	cacheDir   string
+}
+
+func newFlagSet(name string, stdout io.Writer) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet("raccoon "+name, flag.ContinueOnError)
+	fs.SetOutput(stdout)
+
+	c := &commonFlags{}
+	fs.StringVar(&c.configPath, "config", "", "설정 파일 경로 (.yaml, .yml, .json)")
+	fs.StringVar(&c.logLevel, "log-level", "", "로그 레벨 (debug, info, warn, error). 비우면 설정 파일 값")
+	fs.StringVar(&c.cacheDir, "cache-dir", ".candles", "과거 캔들 캐시 디렉터리")
+	return fs, c
+}
+
+// parseFlags : 플래그 파싱 에러를 ErrUsage 로 감쌉니다. (-h 는 flag.ErrHelp 그대로)
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := parseFlagsWithArgs(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %v", ErrUsage, fs.Args())
+	}
+	return nil
+}
+
+// parseFlagsWithArgs : parseFlags 와 같지만 플래그 뒤의 위치 인자를 허용
+func parseFlagsWithArgs(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	return nil
+}
+
+// loadConfig : -config 가 있으면 파일을, 없으면 nil 을 반환하고 로그 레벨을 적용합니다.
+func (c *commonFlags) loadConfig() (*config.Config, error) {
+	var cfg *config.Config
+	if c.configPath != "" {
+		loaded, err := config.Load(c.configPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg = loaded
+	}
+
+	level := c.logLevel
+	if level == "" && cfg != nil {
+		level = cfg.Log.Level
+	}
+	if level != "" {
+		parsed, err := log.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("%w: -log-level: %v", ErrUsage, err)
+		}
+		log.SetLevel(parsed)
+	}
+	return cfg, nil
+}
+
+// feederFlags : 과거 캔들을 어디서 읽을지 (Upbit+캐시, 캐시만, 파일)
+type feederFlags struct {
+	dataPath string
+	offline  bool
+}
+
+func addFeederFlags(fs *flag.FlagSet) *feederFlags {
+	f := &feederFlags{}
+	fs.StringVar(&f.dataPath, "data", "", "캔들 파일(.csv/.jsonl). 지정하면 거래소 대신 파일로 실행")
+	fs.BoolVar(&f.offline, "offline", false, "API 호출 없이 캐시된 캔들만 사용")
+	return f
+}
+
+// feeder : 설정에 맞는 DataFeeder 를 만듭니다. 과거 캔들 조회는 API 키 없이도 동작합니다.
+func (f *feederFlags) feeder(common *commonFlags, cfg *config.Config, pair, timeframe string) (interfaces.DataFeeder, error) {
+	if f.dataPath != "" {
+		feeder := data.NewFeeder()
+		if err := feeder.LoadFile(pair, timeframe, f.dataPath, data.Options{}); err != nil {
+			return nil, err
+		}
+		return feeder, nil
+	}
+
+	store, err := cache.NewCandleStore(common.cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("open candle cache: %w", err)
+	}
+	if f.offline {
+		return cache.NewFeeder(store), nil
+	}
+
+	if cfg == nil {
+		cfg = config.Default(nil)
+	}
+	apiKey, secretKey, _ := cfg.Credentials()
+	upbit, err := exchange.NewUpbit(apiKey, secretKey, []string{pair})
+	if err != nil {
+		return nil, err
+	}
+	upbit.SetCandleStore(store)
+	return upbit, nil
+}
+
+// strategyFlags : 백테스트/최적화 대상 전략 선택
+type strategyFlags struct {
+	name      string
+	pair      string
+	timeframe string
+	params    string
+}
+
+func addStrategyFlags(fs *flag.FlagSet) *strategyFlags {
+	s := &strategyFlags{}
+	fs.StringVar(&s.name, "strategy", "", "전략 종류(레지스트리 이름) 또는 설정 파일의 전략 name. 비우면 설정의 첫 전략, 없으면 improved_psh")
+	fs.StringVar(&s.pair, "pair", "", "종목 (예: KRW-XRP). 비우면 설정 값, 없으면 KRW-XRP")
+	fs.StringVar(&s.timeframe, "timeframe", "", "캔들 주기 (예: 15m, 1h). 비우면 설정/전략 기본값")
+	fs.StringVar(&s.params, "params", "", "전략 파라미터 덮어쓰기 (예: trade_fraction=0.3,rsi_period=14)")
+	return s
+}
+
+// resolve : 설정 파일의 전략 선언 위에 플래그 값을 덮어써서 실행할 전략을 결정합니다.
+func (s *strategyFlags) resolve(cfg *config.Config) (config.StrategyConfig, error) {
+	sc := config.StrategyConfig{Type: "improved_psh", Pair: "KRW-XRP"}
+	found := false
+	if cfg != nil {
+		for _, candidate := range cfg.Strategies {
+			if s.name == "" || candidate.Name == s.name {
+				sc, found = candidate, true
+				break
+			}
+		}
+	}
+	if s.name != "" && !found {
+		sc.Type, sc.Name = s.name, ""
+	}
+
+	if s.pair != "" {
+		sc.Pair = strings.ToUpper(strings.TrimSpace(s.pair))
+	}
+	if s.timeframe != "" {
+		sc.Timeframe = s.timeframe
+	}
+	if s.params != "" {
+		overrides, err := parseParams(s.params)
+		if err != nil {
+			return sc, err
+		}
+		merged := make(model.ParamSet, len(sc.Params)+len(overrides))
+		for k, v := range sc.Params {
+			merged[k] = v
+		}
+		for k, v := range overrides {
+			merged[k] = v
+		}
+		sc.Params = merged
+	}
+
+	if _, ok := strategy.Lookup(sc.Type); !ok {
+		return sc, fmt.Errorf("%w: -strategy: %w %q (available: %s)",
+			ErrUsage, strategy.ErrUnknownStrategy, sc.Type, strings.Join(strategy.Names(), ", "))
+	}
+	if sc.Timeframe == "" {
+		def, _ := strategy.Lookup(sc.Type)
+		sc.Timeframe = def.Timeframe
+	}
+	if sc.Name == "" {
+		sc.Name = sc.Type + "-" + sc.Pair
+	}
+	return sc, nil
+}
+
+// periodFlags : -start/-end 기간
+type periodFlags struct {
+	start string
+	end   string
+}
+
+func addPeriodFlags(fs *flag.FlagSet) *periodFlags {
+	p := &periodFlags{}
+	fs.StringVar(&p.start, "start", "", "시작 시각 KST (YYYY-MM-DD 또는 'YYYY-MM-DD HH:MM'). 필수")
+	fs.StringVar(&p.end, "end", "", "종료 시각 KST. 비우면 현재")
+	return p
+}
+
+func (p *periodFlags) parse() (time.Time, time.Time, error) {
+	if p.start == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: -start is required", ErrUsage)
+	}
+	start, err := parseTime(p.start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: -start: %v", ErrUsage, err)
+	}
+	end := time.Now()
+	if p.end != "" {
+		if end, err = parseTime(p.end); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: -end: %v", ErrUsage, err)
+		}
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: -end must be after -start", ErrUsage)
+	}
+	return start, end, nil
+}
+
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// parseTime : 타임존이 없으면 KST 로 해석합니다.
+func parseTime(value string) (time.Time, error) {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		loc = time.FixedZone("KST", 9*60*60)
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(value), loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use YYYY-MM-DD or 'YYYY-MM-DD HH:MM')", value)
+}
+
+// parseParams : "a=1,b=2.5" 형식의 파라미터 목록
+func parseParams(value string) (model.ParamSet, error) {
+	params := make(model.ParamSet)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: -params: %q is not name=value", ErrUsage, item)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: -params: %s: %v", ErrUsage, k, err)
+		}
+		params[strings.TrimSpace(k)] = f
+	}
+	return params, nil
+}
+
+// parseDuration : time.ParseDuration 에 일(d) 단위를 더한 것 (예: 90d, 36h)
+func parseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(value)
+}
+
+func formatParams(params model.ParamSet) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%v", name, params[name]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func runStrategies(args []string, stdout io.Writer) error {
+	fs, common := newFlagSet("strategies", stdout)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if _, err := common.loadConfig(); err != nil {
+		return err
+	}
+	return strategy.WriteRegistry(stdout)
+}
+
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/optimize"
+	"raccoon/strategy"
+)
+
+func runOptimize(args []string, stdout io.Writer) error {
+	fs, common := newFlagSet("optimize", stdout)
+	strat := addStrategyFlags(fs)
+	period := addPeriodFlags(fs)
+	source := addFeederFlags(fs)
+	krw := fs.Float64("krw", DefaultInitialKRW, "시작 KRW 잔고")
+	metric := fs.String("metric", string(optimize.MetricSharpe), "정렬 기준 (total_return, annualized_return, sharpe, sortino, calmar, profit_factor, win_rate, max_drawdown)")
+	trials := fs.Int("trials", 0, "랜덤 탐색 시행 수. 0 이면 그리드 전체 탐색")
+	seed := fs.Int64("seed", 1, "랜덤 탐색 seed")
+	workers := fs.Int("workers", 0, "동시 실행 백테스트 수. 0 이면 CPU 수")
+	top := fs.Int("top", 10, "출력할 상위 조합 수")
+	walkForward := fs.String("walk-forward", "", "walk-forward 모드 (rolling, anchored). 비우면 전체 기간 한 번 탐색")
+	inSample := fs.String("is", "90d", "walk-forward in-sample 길이 (예: 90d, 36h)")
+	outOfSample := fs.String("oos", "30d", "walk-forward out-of-sample 길이")
+	step := fs.String("step", "", "walk-forward 이동 간격. 비우면 -oos 와 동일")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := common.loadConfig()
+	if err != nil {
+		return err
+	}
+	sc, err := strat.resolve(cfg)
+	if err != nil {
+		return err
+	}
+	start, end, err := period.parse()
+	if err != nil {
+		return err
+	}
+	base, err := optimize.FactoryByName(sc.Type)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	feeder, err := source.feeder(common, cfg, sc.Pair, sc.Timeframe)
+	if err != nil {
+		return err
+	}
+
+	timeframe := sc.Timeframe
+	factory := func(orderFeed *feed.OrderFeedSubscription) interfaces.TunableStrategy {
+		return strategy.WithTimeframe(base(orderFeed), timeframe).(interfaces.TunableStrategy)
+	}
+	optimizer := optimize.NewOptimizer(factory, optimize.Settings{
+		Pair:       sc.Pair,
+		Start:      start,
+		End:        end,
+		InitialKRW: *krw,
+		Feeder:     feeder,
+		Metric:     optimize.Metric(*metric),
+		Workers:    *workers,
+		TopN:       *top,
+		Fixed:      sc.Params, // -params/설정 파일 값은 탐색하지 않고 고정
+	})
+	fmt.Fprintf(stdout, "Optimizing %s %s-%s %s ~ %s fixed=[%s]\n\n", sc.Type, sc.Pair, timeframe,
+		start.Format(time.DateTime), end.Format(time.DateTime), formatParams(sc.Params))
+
+	if *walkForward != "" {
+		wf := optimize.WalkForwardSettings{
+			Mode:         optimize.WalkForwardMode(*walkForward),
+			RandomTrials: *trials,
+			Seed:         *seed,
+		}
+		if wf.InSample, err = parseDuration(*inSample); err != nil {
+			return fmt.Errorf("%w: -is: %v", ErrUsage, err)
+		}
+		if wf.OutOfSample, err = parseDuration(*outOfSample); err != nil {
+			return fmt.Errorf("%w: -oos: %v", ErrUsage, err)
+		}
+		if *step != "" {
+			if wf.Step, err = parseDuration(*step); err != nil {
+				return fmt.Errorf("%w: -step: %v", ErrUsage, err)
+			}
+		}
+		result, err := optimizer.WalkForward(wf)
+		if err != nil {
+			return err
+		}
+		return result.WriteTable(stdout)
+	}
+
+	var result *optimize.Result
+	if *trials > 0 {
+		result, err = optimizer.RandomSearch(*trials, *seed)
+	} else {
+		result, err = optimizer.GridSearch()
+	}
+	if err != nil {
+		return err
+	}
+	return result.WriteTable(stdout)
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"raccoon/bot"
+	"raccoon/config"
+	"raccoon/utils/log"
+)
+
+// runLive : 설정의 mode 로 실행 (기본 실거래). 설정 파일이 없으면 RACCOON_PAIRS(기본 KRW-XRP) 종목마다 improved_psh
+func runLive(args []string, stdout io.Writer) error {
+	fs, common := newFlagSet("run", stdout)
+	listStrategies := fs.Bool("strategies", false, "사용 가능한 전략과 기본 파라미터를 출력하고 종료 (raccoon strategies 와 동일)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *listStrategies {
+		return runStrategies(nil, stdout)
+	}
+
+	cfg, err := liveConfig(common)
+	if err != nil {
+		return err
+	}
+	return runBot(cfg)
+}
+
+// runPaper : 설정의 mode 와 상관없이 모의투자로 실행
+func runPaper(args []string, stdout io.Writer) error {
+	fs, common := newFlagSet("paper", stdout)
+	krw := fs.Float64("krw", 0, fmt.Sprintf("가상 시작 KRW 잔고. 비우면 설정의 paper.initial_krw (기본 %.0f)", config.DefaultPaperKRW))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *krw < 0 {
+		return fmt.Errorf("%w: -krw must be positive", ErrUsage)
+	}
+
+	cfg, err := liveConfig(common)
+	if err != nil {
+		return err
+	}
+	cfg.Mode = config.ModePaper
+	if *krw > 0 {
+		cfg.Paper.InitialKRW = *krw
+	}
+	return runBot(cfg)
+}
+
+func runBot(cfg *config.Config) error {
+	raccoon, err := bot.NewRaccoonFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	raccoon.Start()
+	waitForSignal(raccoon)
+	raccoon.Stop()
+	time.Sleep(1 * time.Second)
+	log.Infof("Shutdown complete.")
+	return nil
+}
+
+// liveConfig : run/paper 공통 설정 로드
+func liveConfig(common *commonFlags) (*config.Config, error) {
+	cfg, err := common.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		return cfg, nil
+	}
+
+	pairs := []string{"KRW-XRP"}
+	if env := os.Getenv("RACCOON_PAIRS"); env != "" {
+		pairs = strings.Split(env, ",") // 예: KRW-XRP,KRW-BTC
+	}
+	cfg = config.Default(pairs)
+	cfg.Notifier.Type = config.NotifierTelegram
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if common.logLevel == "" {
+		level, _ := log.ParseLevel(cfg.Log.Level)
+		log.SetLevel(level)
+	}
+	return cfg, nil
+}
+
+// waitForSignal : SIGINT/SIGTERM 까지 대기 (Graceful Stop)
+// 그 사이 킬 스위치 신호(killSwitchSignals)를 받으면 거래를 중지하고 계속 대기합니다.
+func waitForSignal(raccoon *bot.Raccoon) {
+	signals := []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	for sig := range killSwitchSignals {
+		signals = append(signals, sig)
+	}
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, signals...)
+	defer signal.Stop(sigChan)
+
+	for sig := range sigChan {
+		flatten, ok := killSwitchSignals[sig]
+		if !ok {
+			log.Infof("Received %v, shutting down gracefully...", sig)
+			return
+		}
+		log.Warnf("Received %v, halting trading (flatten=%v)", sig, flatten)
+		if err := raccoon.Halt("signal", sig.String(), flatten); err != nil {
+			log.Errorf("Kill switch failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package cli
+
+import (
+	"os"
+	"syscall"
+)
+
+// killSwitchSignals : 킬 스위치를 켜는 신호 → 보유 코인 시장가 매도 여부
+//   - SIGUSR1 : 새 주문 중지 + 미체결 주문 취소
+//   - SIGUSR2 : SIGUSR1 + 보유 코인 전량 시장가 매도
+var killSwitchSignals = map[os.Signal]bool{
+	syscall.SIGUSR1: false,
+	syscall.SIGUSR2: true,
+}
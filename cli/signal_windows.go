@@ -0,0 +1,6 @@
+package cli
+
+import "os"
+
+// killSwitchSignals : Windows 에는 SIGUSR1/SIGUSR2 가 없으므로 웹서버/텔레그램으로만 킬 스위치를 켤 수 있습니다.
+var killSwitchSignals = map[os.Signal]bool{}
@@ -0,0 +1,354 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"raccoon/model"
+	"raccoon/portfolio"
+	"raccoon/utils/log"
+	"raccoon/utils/tools"
+)
+
+const (
+	DefaultAccessKeyEnv   = "UPBIT_ACCESS_KEY"
+	DefaultSecretKeyEnv   = "UPBIT_SECRET_KEY"
+	DefaultWebAddress     = ":3030"
+	DefaultLogLevel       = "info"
+	DefaultPaperKRW       = 10_000_000.0
+	DefaultJournalPath    = "raccoon.journal"
+	DefaultStoragePath    = "raccoon.db"
+	DefaultKillSwitchPath = "raccoon.killswitch"
+
+	ModeLive  = "live"
+	ModePaper = "paper"
+
+	ExchangeUpbit    = "upbit"
+	NotifierTelegram = "telegram"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid config")
+
+	pairPattern = regexp.MustCompile(`^[A-Z]{3,4}-[A-Z0-9]{1,10}$`)
+)
+
+// Config : 봇 실행 설정. YAML(.yaml/.yml) 또는 JSON(.json) 파일로 선언합니다.
+type Config struct {
+	Mode       string           `yaml:"mode" json:"mode"` // live(기본) 또는 paper
+	Paper      PaperConfig      `yaml:"paper" json:"paper"`
+	Exchange   ExchangeConfig   `yaml:"exchange" json:"exchange"`
+	Strategies []StrategyConfig `yaml:"strategies" json:"strategies"`
+	Notifier   NotifierConfig   `yaml:"notifier" json:"notifier"`
+	WebServer  WebServerConfig  `yaml:"webserver" json:"webserver"`
+	Log        LogConfig        `yaml:"log" json:"log"`
+	Risk       RiskConfig       `yaml:"risk" json:"risk"`
+	Journal    JournalConfig    `yaml:"journal" json:"journal"`
+	Storage    StorageConfig    `yaml:"storage" json:"storage"`
+	Portfolio  PortfolioConfig  `yaml:"portfolio" json:"portfolio"`
+	KillSwitch KillSwitchConfig `yaml:"kill_switch" json:"kill_switch"`
+}
+
+// PaperConfig : 모의투자 가상 잔고
+type PaperConfig struct {
+	InitialKRW float64 `yaml:"initial_krw" json:"initial_krw"`
+}
+
+// ExchangeConfig : 키 값 대신 키가 들어있는 환경변수 이름을 적습니다.
+type ExchangeConfig struct {
+	Name         string `yaml:"name" json:"name"`
+	AccessKeyEnv string `yaml:"access_key_env" json:"access_key_env"`
+	SecretKeyEnv string `yaml:"secret_key_env" json:"secret_key_env"`
+}
+
+type StrategyConfig struct {
+	Name      string         `yaml:"name" json:"name"`           // 주문 태그/예산 이름. 비우면 type-pair
+	Type      string         `yaml:"type" json:"type"`           // 전략 종류 (예: improved_psh)
+	Pair      string         `yaml:"pair" json:"pair"`           // 예: KRW-XRP
+	Timeframe string         `yaml:"timeframe" json:"timeframe"` // 비우면 전략 기본값
+	Weight    float64        `yaml:"weight" json:"weight"`       // 자본 배분 비중. 비우면 1
+	Params    model.ParamSet `yaml:"params" json:"params"`       // 전략 파라미터 (이름 → 값)
+	Exits     ExitsConfig    `yaml:"exits" json:"exits"`         // 매수 체결 시 붙일 보호 청산
+}
+
+// ExitsConfig : 진입가 대비 비율. 0 이면 붙이지 않고, 여러 개면 먼저 닿는 쪽이 나머지를 취소합니다 (OCO).
+// 전략의 봉 마감과 관계없이 1초봉으로 확인합니다.
+type ExitsConfig struct {
+	StopLoss     float64 `yaml:"stop_loss" json:"stop_loss"`         // 예: 0.03 → 진입가 -3% 에 손절
+	TakeProfit   float64 `yaml:"take_profit" json:"take_profit"`     // 예: 0.05 → 진입가 +5% 에 익절
+	TrailingStop float64 `yaml:"trailing_stop" json:"trailing_stop"` // 예: 0.02 → 최고가 아래 진입가의 2% 에 따라가는 손절
+}
+
+// NotifierConfig : type 이 비어있으면 알림을 사용하지 않습니다.
+type NotifierConfig struct {
+	Type      string `yaml:"type" json:"type"`
+	TokenEnv  string `yaml:"token_env" json:"token_env"`
+	ChatIDEnv string `yaml:"chat_id_env" json:"chat_id_env"`
+}
+
+type WebServerConfig struct {
+	Address  string `yaml:"address" json:"address"`
+	Disabled bool   `yaml:"disabled" json:"disabled"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" json:"level"`
+}
+
+// RiskConfig : 0 이면 제한 없음
+type RiskConfig struct {
+	MaxOrderKRW        float64 `yaml:"max_order_krw" json:"max_order_krw"`                 // 주문 1건 최대 금액
+	MaxPositionKRW     float64 `yaml:"max_position_krw" json:"max_position_krw"`           // 종목당 최대 보유 평가금액
+	MaxOrderEquityRate float64 `yaml:"max_order_equity_rate" json:"max_order_equity_rate"` // 주문 1건이 총자산에서 차지할 수 있는 최대 비율
+	MaxOrdersPerMinute int     `yaml:"max_orders_per_minute" json:"max_orders_per_minute"`
+	MaxDailyLossKRW    float64 `yaml:"max_daily_loss_krw" json:"max_daily_loss_krw"` // 하루 실현손실 한도
+}
+
+// JournalConfig : 실거래 주문 의도/결과를 기록하는 저널 파일. 재시작 시 거래소 주문과 맞춰 중복 주문을 막습니다.
+// 모의투자는 가상 주문이 재시작하면 사라지므로 사용하지 않습니다.
+type JournalConfig struct {
+	Path     string `yaml:"path" json:"path"`
+	Disabled bool   `yaml:"disabled" json:"disabled"`
+}
+
+// StorageConfig : 주문/체결/잔고 스냅샷/전략 신호를 기록하는 SQLite 파일. 재시작해도 내역이 남습니다.
+type StorageConfig struct {
+	Path     string `yaml:"path" json:"path"`
+	Disabled bool   `yaml:"disabled" json:"disabled"`
+}
+
+// KillSwitchConfig : 킬 스위치 상태 파일. 켜진 채로 재시작해도 주문이 막힌 상태로 시작합니다.
+type KillSwitchConfig struct {
+	Path string `yaml:"path" json:"path"`
+}
+
+// PortfolioConfig : 손익 계산 방식. cost_method 는 average(이동평균, 기본) 또는 fifo
+type PortfolioConfig struct {
+	CostMethod string `yaml:"cost_method" json:"cost_method"`
+}
+
+// Load : 파일을 읽어 기본값을 채우고 검증합니다.
+func Load(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(cfg)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		err = dec.Decode(cfg)
+	default:
+		return nil, fmt.Errorf("%w: unsupported config extension %q (use .yaml, .yml or .json)", ErrInvalidConfig, filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Default : 설정 파일 없이 pairs 만으로 실행할 때의 설정 (종목마다 improved_psh)
+func Default(pairs []string) *Config {
+	cfg := &Config{}
+	for _, pair := range pairs {
+		cfg.Strategies = append(cfg.Strategies, StrategyConfig{Type: "improved_psh", Pair: pair})
+	}
+	cfg.SetDefaults()
+	return cfg
+}
+
+// SetDefaults : 비어있는 값을 기본값으로 채웁니다.
+func (c *Config) SetDefaults() {
+	if c.Mode == "" {
+		c.Mode = ModeLive
+	}
+	if c.Paper.InitialKRW == 0 {
+		c.Paper.InitialKRW = DefaultPaperKRW
+	}
+	if c.Exchange.Name == "" {
+		c.Exchange.Name = ExchangeUpbit
+	}
+	if c.Exchange.AccessKeyEnv == "" {
+		c.Exchange.AccessKeyEnv = DefaultAccessKeyEnv
+	}
+	if c.Exchange.SecretKeyEnv == "" {
+		c.Exchange.SecretKeyEnv = DefaultSecretKeyEnv
+	}
+	if c.Notifier.Type == NotifierTelegram {
+		if c.Notifier.TokenEnv == "" {
+			c.Notifier.TokenEnv = "TELEGRAM_BOT_TOKEN"
+		}
+		if c.Notifier.ChatIDEnv == "" {
+			c.Notifier.ChatIDEnv = "TELEGRAM_CHAT_ID"
+		}
+	}
+	if c.WebServer.Address == "" {
+		c.WebServer.Address = DefaultWebAddress
+	}
+	if c.Log.Level == "" {
+		c.Log.Level = DefaultLogLevel
+	}
+	if c.Journal.Path == "" {
+		c.Journal.Path = DefaultJournalPath
+	}
+	if c.Storage.Path == "" {
+		c.Storage.Path = DefaultStoragePath
+	}
+	if c.KillSwitch.Path == "" {
+		c.KillSwitch.Path = DefaultKillSwitchPath
+	}
+	if c.Portfolio.CostMethod == "" {
+		c.Portfolio.CostMethod = string(portfolio.CostAverage)
+	}
+	for i := range c.Strategies {
+		s := &c.Strategies[i]
+		s.Pair = strings.ToUpper(strings.TrimSpace(s.Pair))
+		if s.Name == "" && s.Type != "" && s.Pair != "" {
+			s.Name = s.Type + "-" + s.Pair
+		}
+		if s.Weight == 0 {
+			s.Weight = 1
+		}
+	}
+}
+
+// Validate : 잘못된 항목을 모두 모아 한 번에 알려줍니다.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	switch c.Mode {
+	case ModeLive:
+	case ModePaper:
+		if c.Paper.InitialKRW < 0 {
+			fail("paper.initial_krw", "must be positive (got %v)", c.Paper.InitialKRW)
+		}
+	default:
+		fail("mode", "unsupported mode %q (supported: %s, %s)", c.Mode, ModeLive, ModePaper)
+	}
+
+	if c.Exchange.Name != ExchangeUpbit {
+		fail("exchange.name", "unsupported exchange %q (supported: %s)", c.Exchange.Name, ExchangeUpbit)
+	}
+
+	if len(c.Strategies) == 0 {
+		fail("strategies", "at least one strategy is required")
+	}
+	names := make(map[string]int)
+	for i, s := range c.Strategies {
+		field := fmt.Sprintf("strategies[%d]", i)
+		if s.Type == "" {
+			fail(field+".type", "is required")
+		}
+		if !pairPattern.MatchString(s.Pair) {
+			fail(field+".pair", "%q is not a market code like KRW-XRP", s.Pair)
+		}
+		if s.Timeframe != "" {
+			if _, err := tools.ParseTimeframeToDuration(s.Timeframe); err != nil {
+				fail(field+".timeframe", "%v", err)
+			}
+		}
+		if s.Weight < 0 {
+			fail(field+".weight", "must not be negative (got %v)", s.Weight)
+		}
+		if s.Exits.StopLoss < 0 || s.Exits.StopLoss >= 1 {
+			fail(field+".exits.stop_loss", "must be between 0 and 1 (got %v)", s.Exits.StopLoss)
+		}
+		if s.Exits.TakeProfit < 0 {
+			fail(field+".exits.take_profit", "must not be negative (got %v)", s.Exits.TakeProfit)
+		}
+		if s.Exits.TrailingStop < 0 || s.Exits.TrailingStop >= 1 {
+			fail(field+".exits.trailing_stop", "must be between 0 and 1 (got %v)", s.Exits.TrailingStop)
+		}
+		if prev, dup := names[s.Name]; dup && s.Name != "" {
+			fail(field+".name", "%q is already used by strategies[%d]", s.Name, prev)
+		}
+		names[s.Name] = i
+	}
+
+	switch c.Notifier.Type {
+	case "":
+	case NotifierTelegram:
+		if c.Notifier.TokenEnv == "" || c.Notifier.ChatIDEnv == "" {
+			fail("notifier", "token_env and chat_id_env are required for telegram")
+		}
+	default:
+		fail("notifier.type", "unsupported notifier %q (supported: %s)", c.Notifier.Type, NotifierTelegram)
+	}
+
+	if _, err := log.ParseLevel(c.Log.Level); err != nil {
+		fail("log.level", "%v", err)
+	}
+
+	if _, err := portfolio.ParseCostMethod(c.Portfolio.CostMethod); err != nil {
+		fail("portfolio.cost_method", "%v", err)
+	}
+
+	r := c.Risk
+	if r.MaxOrderKRW < 0 || r.MaxPositionKRW < 0 || r.MaxDailyLossKRW < 0 || r.MaxOrdersPerMinute < 0 {
+		fail("risk", "limits must not be negative")
+	}
+	if r.MaxOrderEquityRate < 0 || r.MaxOrderEquityRate > 1 {
+		fail("risk.max_order_equity_rate", "must be between 0 and 1 (got %v)", r.MaxOrderEquityRate)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w:\n%w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Credentials : 거래소 키를 환경변수에서 읽습니다. 실거래에는 둘 다 필요합니다. (모의투자는 없어도 됨)
+func (c *Config) Credentials() (accessKey, secretKey string, err error) {
+	accessKey = os.Getenv(c.Exchange.AccessKeyEnv)
+	secretKey = os.Getenv(c.Exchange.SecretKeyEnv)
+	if accessKey == "" || secretKey == "" {
+		return accessKey, secretKey, fmt.Errorf("%w: environment variables %s and %s must be set",
+			ErrInvalidConfig, c.Exchange.AccessKeyEnv, c.Exchange.SecretKeyEnv)
+	}
+	return accessKey, secretKey, nil
+}
+
+// TelegramCredentials : 알림용 토큰/채팅 ID. 설정되지 않았으면 ok=false
+func (c *Config) TelegramCredentials() (token, chatID string, ok bool) {
+	if c.Notifier.Type != NotifierTelegram {
+		return "", "", false
+	}
+	token = os.Getenv(c.Notifier.TokenEnv)
+	chatID = os.Getenv(c.Notifier.ChatIDEnv)
+	return token, chatID, token != "" && chatID != ""
+}
+
+// Pairs : 전략들이 거래하는 종목 (중복 제거, 선언 순서)
+func (c *Config) Pairs() []string {
+	seen := make(map[string]bool)
+	pairs := make([]string, 0, len(c.Strategies))
+	for _, s := range c.Strategies {
+		if !seen[s.Pair] {
+			seen[s.Pair] = true
+			pairs = append(pairs, s.Pair)
+		}
+	}
+	return pairs
+}
@@ -15,6 +15,12 @@ func NewDataFeedConsumerStrategy(controller *strategy.Controller) *DataFeedConsu
 	}
 }
 
+// OnCandle : 완성된 봉은 OnCandle, 진행 중인 봉은 OnPartialCandle 로 전달
+// (진행 중인 봉은 onCandleClose=false 로 구독한 HighFrequencyStrategy 컨트롤러에만 들어옴)
 func (c *DataFeedConsumerStrategy) OnCandle(candle model.Candle) {
+	if !candle.Complete {
+		c.strategyController.OnPartialCandle(candle)
+		return
+	}
 	c.strategyController.OnCandle(candle)
 }
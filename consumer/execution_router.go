@@ -0,0 +1,88 @@
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	"raccoon/model"
+)
+
+const (
+	// REST 응답보다 먼저 도착한 종료 이벤트를 보관하는 최대 개수와 기간.
+	// 수동 주문처럼 주인이 없는 이벤트가 쌓여도 가장 오래된 것부터 버리므로 방금 도착한 이벤트는 남음
+	maxUnmatchedExecutions = 100
+	unmatchedExecutionTTL  = time.Minute
+)
+
+type unmatchedExecution struct {
+	msg model.UpbitMyOrderMessage
+	at  time.Time
+}
+
+// ExecutionRouter : private 스트림의 myOrder 종료(done/cancel) 이벤트를 주문을 낸 브로커에만 전달합니다.
+// 같은 종목을 여러 전략이 거래해도 각 브로커는 자기 주문의 이벤트만 받습니다.
+// 종목마다 한 번만 구독하고, 브로커는 SetExecutionRouter 로 공유합니다.
+type ExecutionRouter struct {
+	mu        sync.Mutex
+	owners    map[string]*OrderFeedConsumerBroker // 주문 uuid → 주문을 낸 브로커
+	unmatched map[string]unmatchedExecution       // 주인이 아직 없는 종료 이벤트 (REST 응답보다 먼저 도착했거나 수동 주문)
+}
+
+func NewExecutionRouter() *ExecutionRouter {
+	return &ExecutionRouter{
+		owners:    make(map[string]*OrderFeedConsumerBroker),
+		unmatched: make(map[string]unmatchedExecution),
+	}
+}
+
+// OnExecution : 종료 이벤트를 주문을 낸 브로커로 보냅니다. 주인이 아직 없으면 잠시 보관합니다.
+func (r *ExecutionRouter) OnExecution(msg model.UpbitMyOrderMessage) {
+	status := model.OrderStatusType(msg.State)
+	if status != model.OrderStatusTypeDone && status != model.OrderStatusTypeCanceled {
+		return
+	}
+
+	r.mu.Lock()
+	owner, ok := r.owners[msg.UUID]
+	if !ok {
+		r.park(msg)
+		r.mu.Unlock()
+		return
+	}
+	delete(r.owners, msg.UUID)
+	r.mu.Unlock()
+
+	owner.confirm(msg)
+}
+
+// claim : uuid 주문의 이벤트를 owner 로 보내도록 등록합니다. 이미 도착한 종료 이벤트가 있으면 반환합니다.
+func (r *ExecutionRouter) claim(uuid string, owner *OrderFeedConsumerBroker) (model.UpbitMyOrderMessage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if early, ok := r.unmatched[uuid]; ok {
+		delete(r.unmatched, uuid)
+		return early.msg, true
+	}
+	r.owners[uuid] = owner
+	return model.UpbitMyOrderMessage{}, false
+}
+
+// park : 기간이 지난 이벤트를 먼저 버리고, 그래도 가득 차 있으면 가장 오래된 이벤트를 버린 뒤 보관
+func (r *ExecutionRouter) park(msg model.UpbitMyOrderMessage) {
+	now := time.Now()
+	for uuid, u := range r.unmatched {
+		if now.Sub(u.at) > unmatchedExecutionTTL {
+			delete(r.unmatched, uuid)
+		}
+	}
+	for len(r.unmatched) >= maxUnmatchedExecutions {
+		var oldest string
+		for uuid, u := range r.unmatched {
+			if oldest == "" || u.at.Before(r.unmatched[oldest].at) {
+				oldest = uuid
+			}
+		}
+		delete(r.unmatched, oldest)
+	}
+	r.unmatched[msg.UUID] = unmatchedExecution{msg: msg, at: now}
+}
@@ -3,22 +3,48 @@ package consumer
 
 import (
 	"fmt"
+	"raccoon/exchange"
+	"raccoon/execution"
 	"raccoon/interfaces"
+	"raccoon/journal"
 	"raccoon/model"
+	"raccoon/oms"
+	"raccoon/portfolio"
+	"raccoon/risk"
 	"raccoon/utils/log"
+	"sync"
 )
 
 type OrderExecutedCallback func(order model.Order, err error)
 
 type OrderFeedConsumerBroker struct {
-	broker    interfaces.Exchange
+	broker    interfaces.Broker
 	callbacks []OrderExecutedCallback
+
+	// confirmByExchange=true 이면 REST 응답만으로 체결을 가정하지 않고,
+	// private 스트림(myOrder)에서 done/cancel 을 받은 시점에 콜백을 호출
+	confirmByExchange bool
+	mu                sync.Mutex
+	submitted         map[string]string // 이 브로커가 제출한 주문 uuid → 주문을 낸 전략
+	router            *ExecutionRouter  // 종료 이벤트를 이 브로커의 주문에 대해서만 전달
+
+	journal *journal.Journal // 설정 시 주문 의도/결과를 기록 (재시작 후 중복 주문 방지)
+	orders  *oms.Manager     // 설정 시 접수된 주문을 체결/취소까지 추적
+
+	accountant *portfolio.Accountant // 설정 시 체결된 주문의 손익을 계산해 콜백 전에 Profit/ProfitValue 를 채움
+	risk       *risk.Manager         // 설정 시 거래소에 보내기 전에 주문을 검사 (거절 사유는 콜백 에러로 전달)
+
+	executor *execution.Executor // 설정 시 Execution 이 있는 주문을 TWAP/iceberg/chase 로 나눠 실행
+	children map[string]bool     // 실행 알고리즘이 낸 자식 주문 uuid (결과는 Executor 가 직접 확인)
 }
 
-func NewOrderFeedConsumerBroker(exchange interfaces.Exchange) *OrderFeedConsumerBroker {
+func NewOrderFeedConsumerBroker(broker interfaces.Broker) *OrderFeedConsumerBroker {
 	return &OrderFeedConsumerBroker{
-		broker:    exchange,
+		broker:    broker,
 		callbacks: make([]OrderExecutedCallback, 0),
+		submitted: make(map[string]string),
+		router:    NewExecutionRouter(),
+		children:  make(map[string]bool),
 	}
 }
 
@@ -26,35 +52,294 @@ func (o *OrderFeedConsumerBroker) AddOrderExecutedCallback(cb OrderExecutedCallb
 	o.callbacks = append(o.callbacks, cb)
 }
 
+// SetJournal : 주문 전에 의도를, 주문 후에 거래소 결과를 저널에 기록합니다.
+func (o *OrderFeedConsumerBroker) SetJournal(j *journal.Journal) {
+	o.journal = j
+}
+
+// SetOrderManager : 거래소가 접수한 주문을 주문 관리자에 등록합니다.
+func (o *OrderFeedConsumerBroker) SetOrderManager(m *oms.Manager) {
+	o.orders = m
+}
+
+// SetAccountant : 체결된 주문을 포트폴리오 회계에 반영합니다. 콜백은 손익이 채워진 주문을 받습니다.
+func (o *OrderFeedConsumerBroker) SetAccountant(a *portfolio.Accountant) {
+	o.accountant = a
+}
+
+// SetRiskManager : 주문을 거래소에 보내기 전에 리스크 검사를 거칩니다. 거절된 주문은 *risk.Rejection 에러로 콜백됩니다.
+func (o *OrderFeedConsumerBroker) SetRiskManager(m *risk.Manager) {
+	o.risk = m
+}
+
+// SetExecutor : Execution 이 지정된 주문을 executor 로 나눠 실행합니다. 자식 주문마다 리스크 검사를 거치고 콜백됩니다.
+func (o *OrderFeedConsumerBroker) SetExecutor(e *execution.Executor) {
+	o.executor = e
+}
+
+// SetExecutionRouter : 같은 private 스트림을 여러 브로커가 나눠 쓸 때 공유하는 라우터. 기본은 이 브로커 전용 라우터
+func (o *OrderFeedConsumerBroker) SetExecutionRouter(r *ExecutionRouter) {
+	o.router = r
+}
+
+// ConfirmByExchange : 체결 확인을 거래소 private 스트림(OnExecution)에 맡깁니다.
+func (o *OrderFeedConsumerBroker) ConfirmByExchange(confirm bool) {
+	o.confirmByExchange = confirm
+}
+
 func (o *OrderFeedConsumerBroker) OnOrder(order model.Order) {
+	_ = o.HandleOrder(order)
+}
+
+// HandleOrder : 주문을 거래소에 제출하고, 제출하지 못하면 에러를 반환합니다 (feed.SubscribeAck 의 reject).
+// 에러는 OrderExecutedCallback 으로도 전달됩니다.
+func (o *OrderFeedConsumerBroker) HandleOrder(order model.Order) error {
 	log.Infof("[OrderFeedConsumerBroker] Received order - Pair: %s, Side: %s, Type: %s, Quantity: %.2f",
 		order.Pair, order.Side, order.Type, order.Quantity)
 
-	var executedOrder model.Order
+	var quantity float64
 	var err error
 
 	switch order.Side {
 	case model.SideTypeBuy:
 		if order.Type == model.OrderTypePrice {
-			executedOrder, err = o.broker.CreateOrderMarket(model.SideTypeBuy, order.Pair, order.Price)
+			quantity = order.Price
 		} else {
 			log.Warnf("[OrderFeedConsumerBroker] Unsupported buy order type: %v", order.Type)
 			err = fmt.Errorf("unsupported buy order type: %v", order.Type)
 		}
 	case model.SideTypeSell:
 		if order.Type == model.OrderTypeMarket {
-			executedOrder, err = o.broker.CreateOrderMarket(model.SideTypeSell, order.Pair, order.Quantity)
+			quantity = order.Quantity
 		} else {
 			log.Warnf("[OrderFeedConsumerBroker] Unsupported sell order type: %v", order.Type)
 			err = fmt.Errorf("unsupported sell order type: %v", order.Type)
 		}
+	default:
+		err = fmt.Errorf("unsupported order side: %v", order.Side)
 	}
 
-	for _, cb := range o.callbacks {
-		if err != nil {
-			cb(order, err)
-		} else {
-			cb(executedOrder, nil)
+	if err == nil && o.risk != nil {
+		err = o.risk.Validate(order)
+	}
+	if err != nil {
+		o.notify(order, err)
+		return err
+	}
+
+	if order.Execution != nil {
+		return o.submitExecution(order)
+	}
+
+	executedOrder, err := o.createOrderMarket(order, quantity)
+	if err != nil {
+		o.notify(order, err)
+		return err
+	}
+	if o.risk != nil {
+		o.risk.Submitted(executedOrder)
+	}
+	executedOrder.Strategy = order.Strategy
+	executedOrder.FeedID = order.FeedID
+	if o.orders != nil {
+		o.orders.Track(executedOrder)
+	}
+
+	if !o.confirmByExchange {
+		o.notify(executedOrder, nil)
+		return nil
+	}
+
+	// 체결 결과는 OnExecution 에서 확정
+	o.mu.Lock()
+	o.submitted[executedOrder.ExchangeID] = order.Strategy
+	o.mu.Unlock()
+
+	log.Infof("[OrderFeedConsumerBroker] Order accepted - uuid=%s, waiting for exchange confirmation", executedOrder.ExchangeID)
+	if early, ok := o.router.claim(executedOrder.ExchangeID, o); ok {
+		// REST 응답보다 이벤트가 먼저 도착한 경우
+		o.confirm(early)
+	}
+	return nil
+}
+
+// OnExecution : private 스트림의 myOrder 이벤트를 받아 이 브로커가 낸 주문이 종료(done/cancel)되면 콜백을 호출합니다.
+// 여러 브로커가 스트림을 나눠 쓰면 브로커마다 구독하지 말고 공유 ExecutionRouter 의 OnExecution 을 구독합니다.
+func (o *OrderFeedConsumerBroker) OnExecution(msg model.UpbitMyOrderMessage) {
+	o.router.OnExecution(msg)
+}
+
+// confirm : 라우터가 넘겨준 이 브로커 주문의 종료 이벤트
+func (o *OrderFeedConsumerBroker) confirm(msg model.UpbitMyOrderMessage) {
+	status := model.OrderStatusType(msg.State)
+
+	o.mu.Lock()
+	if o.children[msg.UUID] {
+		// 자식 주문은 Executor 가 조회로 확정
+		o.mu.Unlock()
+		return
+	}
+	strategyName, ok := o.submitted[msg.UUID]
+	if !ok {
+		o.mu.Unlock()
+		return
+	}
+	delete(o.submitted, msg.UUID)
+	o.mu.Unlock()
+
+	order := exchange.MyOrderToOrder(msg)
+	order.Strategy = strategyName
+	if o.journal != nil {
+		if err := o.journal.Completed(order); err != nil {
+			log.Errorf("[OrderFeedConsumerBroker] journal: %v", err)
+		}
+	}
+	if status == model.OrderStatusTypeCanceled && msg.ExecutedVolume == 0 {
+		o.notify(order, fmt.Errorf("order canceled without execution: %s", msg.UUID))
+		return
+	}
+	o.notify(order, nil)
+}
+
+// submitExecution : 부모 주문을 Executor 에 넘깁니다. 콜백은 자식 주문이 끝날 때마다 호출됩니다.
+func (o *OrderFeedConsumerBroker) submitExecution(order model.Order) error {
+	if o.executor == nil {
+		err := fmt.Errorf("execution %s requested but no executor is configured", order.Execution.Algo)
+		o.notify(order, err)
+		return err
+	}
+	err := o.executor.Submit(order, execution.Route{
+		Broker: o.broker,
+		Place:  o.placeChild,
+		Done:   o.childDone,
+	})
+	if err != nil {
+		o.notify(order, err)
+		return err
+	}
+	return nil
+}
+
+// placeChild : 자식 주문을 리스크 검사 후 제출합니다. 실패하면 콜백으로 알립니다.
+func (o *OrderFeedConsumerBroker) placeChild(child model.Order) (model.Order, error) {
+	var err error
+	if o.risk != nil {
+		err = o.risk.Validate(child)
+	}
+	var placed model.Order
+	if err == nil {
+		placed, err = o.createChildOrder(child)
+	}
+	if err != nil {
+		o.notify(child, err)
+		return model.Order{}, err
+	}
+	if o.risk != nil {
+		o.risk.Submitted(placed)
+	}
+	placed.Strategy = child.Strategy
+	placed.FeedID = child.FeedID
+	if o.orders != nil {
+		o.orders.Track(placed)
+	}
+	if o.confirmByExchange {
+		o.mu.Lock()
+		o.children[placed.ExchangeID] = true
+		o.mu.Unlock()
+		// 먼저 도착한 종료 이벤트가 있어도 자식 주문은 Executor 가 조회로 확정하므로 버림
+		o.router.claim(placed.ExchangeID, o)
+	}
+	return placed, nil
+}
+
+// childDone : 끝난 자식 주문 (Price/Quantity 는 평균 체결가/체결 수량)
+func (o *OrderFeedConsumerBroker) childDone(child model.Order, err error) {
+	o.mu.Lock()
+	delete(o.children, child.ExchangeID)
+	o.mu.Unlock()
+	if o.journal != nil {
+		if err := o.journal.Completed(child); err != nil {
+			log.Errorf("[OrderFeedConsumerBroker] journal: %v", err)
+		}
+	}
+	if o.orders != nil {
+		o.orders.Track(child)
+	}
+	o.notify(child, err)
+}
+
+// createOrderMarket : 시장가 주문 (매수 quantity 는 KRW 금액)
+func (o *OrderFeedConsumerBroker) createOrderMarket(order model.Order, quantity float64) (model.Order, error) {
+	return o.create(order, func(identifier string) (model.Order, error) {
+		if identified, ok := o.broker.(interfaces.IdentifiedBroker); ok && identifier != "" {
+			return identified.CreateOrderMarketIdentified(identifier, order.Side, order.Pair, quantity)
+		}
+		return o.broker.CreateOrderMarket(order.Side, order.Pair, quantity)
+	})
+}
+
+// createChildOrder : 실행 알고리즘의 자식 주문 (시장가, 지정가, 최유리 IOC)
+func (o *OrderFeedConsumerBroker) createChildOrder(child model.Order) (model.Order, error) {
+	switch child.Type {
+	case model.OrderTypePrice:
+		return o.createOrderMarket(child, child.Price)
+	case model.OrderTypeMarket:
+		return o.createOrderMarket(child, child.Quantity)
+	case model.OrderTypeLimit:
+		return o.create(child, func(identifier string) (model.Order, error) {
+			if identified, ok := o.broker.(interfaces.IdentifiedBroker); ok && identifier != "" {
+				return identified.CreateOrderLimitIdentified(identifier, child.Side, child.Pair, child.Quantity, child.Price)
+			}
+			return o.broker.CreateOrderLimit(child.Side, child.Pair, child.Quantity, child.Price)
+		})
+	case model.OrderTypeBest:
+		amount := child.Quantity
+		if child.Side == model.SideTypeBuy {
+			amount = child.Price
 		}
+		return o.create(child, func(identifier string) (model.Order, error) {
+			if identified, ok := o.broker.(interfaces.IdentifiedBroker); ok && identifier != "" {
+				return identified.CreateOrderBestIdentified(identifier, child.Side, child.Pair, amount, model.TimeInForceIOC)
+			}
+			return o.broker.CreateOrderBest(child.Side, child.Pair, amount, model.TimeInForceIOC)
+		})
+	}
+	return model.Order{}, fmt.Errorf("unsupported child order type: %v", child.Type)
+}
+
+// create : 저널이 있으면 의도를 먼저 기록하고 그 identifier 로 주문합니다.
+// 주문 요청이 에러로 끝나도 거래소가 접수했을 수 있으므로 identifier 로 한 번 더 확인합니다.
+func (o *OrderFeedConsumerBroker) create(order model.Order, send func(identifier string) (model.Order, error)) (model.Order, error) {
+	if o.journal == nil {
+		return send("")
+	}
+
+	identifier, err := o.journal.Intent(order)
+	if err != nil {
+		// 기록하지 못한 주문은 보내지 않음
+		return model.Order{}, fmt.Errorf("journal intent: %w", err)
+	}
+
+	executed, err := send(identifier)
+	if err != nil {
+		if found, ok := o.journal.Resolve(o.broker, identifier, err); ok {
+			log.Warnf("[OrderFeedConsumerBroker] order %s was accepted despite error: %v", identifier, err)
+			return found, nil
+		}
+		return model.Order{}, err
+	}
+
+	if err := o.journal.Submitted(identifier, executed); err != nil {
+		log.Errorf("[OrderFeedConsumerBroker] journal: %v", err)
+	}
+	return executed, nil
+}
+
+func (o *OrderFeedConsumerBroker) notify(order model.Order, err error) {
+	if err == nil && o.accountant != nil {
+		order = o.accountant.Apply(order)
+	}
+	for _, cb := range o.callbacks {
+		cb(order, err)
 	}
 }
@@ -0,0 +1,262 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"raccoon/model"
+	"raccoon/utils/tools"
+)
+
+var (
+	ErrMissingColumn = errors.New("data: missing required column")
+	ErrInvalidTime   = errors.New("data: invalid time")
+)
+
+// 자주 쓰이는 컬럼 이름 별칭. 여기에 없는 숫자 컬럼은 Metadata 로 들어갑니다.
+var columnAliases = map[string]string{
+	"time": "time", "timestamp": "time", "date": "time", "datetime": "time", "open_time": "time",
+	"pair": "pair", "symbol": "pair", "market": "pair", "code": "pair",
+	"open": "open", "o": "open",
+	"high": "high", "h": "high",
+	"low": "low", "l": "low",
+	"close": "close", "c": "close",
+	"volume": "volume", "v": "volume", "vol": "volume",
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+type Options struct {
+	Pair       string         // 파일에 pair 컬럼이 없을 때 사용할 pair
+	Location   *time.Location // 타임존 정보가 없는 시각을 해석할 위치 (기본 KST)
+	TimeLayout string         // 지정 시 이 포맷만 사용
+}
+
+func (o Options) location() *time.Location {
+	if o.Location != nil {
+		return o.Location
+	}
+	if loc, err := time.LoadLocation("Asia/Seoul"); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// ReadCSV : 헤더가 있는 CSV에서 캔들을 읽습니다.
+// time/open/high/low/close 는 필수, volume/pair 는 선택이며 나머지 숫자 컬럼은 Metadata 로 매핑됩니다.
+func ReadCSV(r io.Reader, opts Options) ([]model.Candle, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("data: read header: %w", err)
+	}
+
+	index := make(map[string]int)
+	extra := make(map[int]string)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := columnAliases[key]; ok {
+			if _, dup := index[field]; !dup {
+				index[field] = i
+				continue
+			}
+		}
+		extra[i] = strings.TrimSpace(name)
+	}
+	for _, field := range []string{"time", "open", "high", "low", "close"} {
+		if _, ok := index[field]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, field)
+		}
+	}
+
+	loc := opts.location()
+	var candles []model.Candle
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("data: line %d: %w", line, err)
+		}
+
+		candle := model.Candle{Pair: opts.Pair, Complete: true}
+		if i, ok := index["pair"]; ok && record[i] != "" {
+			candle.Pair = record[i]
+		}
+		if candle.Time, err = parseTime(record[index["time"]], opts.TimeLayout, loc); err != nil {
+			return nil, fmt.Errorf("data: line %d: %w", line, err)
+		}
+		candle.UpdatedAt = candle.Time
+
+		for field, dst := range map[string]*float64{
+			"open": &candle.Open, "high": &candle.High, "low": &candle.Low,
+			"close": &candle.Close, "volume": &candle.Volume,
+		} {
+			i, ok := index[field]
+			if !ok {
+				continue
+			}
+			if *dst, err = strconv.ParseFloat(strings.TrimSpace(record[i]), 64); err != nil {
+				return nil, fmt.Errorf("data: line %d: %s: %w", line, field, err)
+			}
+		}
+
+		for i, name := range extra {
+			v, err := strconv.ParseFloat(strings.TrimSpace(record[i]), 64)
+			if err != nil {
+				continue // 숫자가 아닌 컬럼은 무시
+			}
+			if candle.Metadata == nil {
+				candle.Metadata = make(map[string]float64)
+			}
+			candle.Metadata[name] = v
+		}
+		candles = append(candles, candle)
+	}
+
+	sortCandles(candles)
+	return candles, nil
+}
+
+// WriteCSV : time(RFC3339), pair, OHLCV, 그리고 Metadata 키(정렬)를 컬럼으로 씁니다.
+func WriteCSV(w io.Writer, candles []model.Candle) error {
+	keys := metadataKeys(candles)
+
+	writer := csv.NewWriter(w)
+	header := append([]string{"time", "pair", "open", "high", "low", "close", "volume"}, keys...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, c := range candles {
+		record := []string{
+			c.Time.Format(time.RFC3339),
+			c.Pair,
+			formatFloat(c.Open),
+			formatFloat(c.High),
+			formatFloat(c.Low),
+			formatFloat(c.Close),
+			formatFloat(c.Volume),
+		}
+		for _, k := range keys {
+			if v, ok := c.Metadata[k]; ok {
+				record = append(record, formatFloat(v))
+			} else {
+				record = append(record, "")
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteDataframeCSV : 전략이 계산한 지표(Metadata)까지 포함해 Dataframe 을 CSV로 내보냅니다.
+func WriteDataframeCSV(w io.Writer, df *model.Dataframe) error {
+	return WriteCSV(w, DataframeToCandles(df))
+}
+
+// DataframeToCandles : tools.DfToCandles 결과에 길이가 맞는 Metadata 시리즈를 캔들 Metadata 로 붙입니다.
+func DataframeToCandles(df *model.Dataframe) []model.Candle {
+	candles := tools.DfToCandles(df)
+	for k, series := range df.Metadata {
+		if len(series) != len(candles) {
+			continue
+		}
+		for i := range candles {
+			if candles[i].Metadata == nil {
+				candles[i].Metadata = make(map[string]float64)
+			}
+			candles[i].Metadata[k] = series[i]
+		}
+	}
+	return candles
+}
+
+func LoadCSV(path string, opts Options) ([]model.Candle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCSV(f, opts)
+}
+
+func SaveCSV(path string, candles []model.Candle) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteCSV(f, candles); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// parseTime : 유닉스 초/밀리초 또는 timeLayouts 중 하나
+func parseTime(value, layout string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if layout != "" {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidTime, value)
+		}
+		return t, nil
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.UnixMilli(n).In(loc), nil
+		}
+		return time.Unix(n, 0).In(loc), nil
+	}
+	for _, l := range timeLayouts {
+		if t, err := time.ParseInLocation(l, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidTime, value)
+}
+
+func metadataKeys(candles []model.Candle) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, c := range candles {
+		for k := range c.Metadata {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortCandles(candles []model.Candle) {
+	sort.SliceStable(candles, func(i, j int) bool {
+		return candles[i].Time.Before(candles[j].Time)
+	})
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,138 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"raccoon/model"
+	"raccoon/utils/tools"
+)
+
+var ErrNoData = errors.New("data: no candles loaded")
+
+// Feeder : 파일에서 읽은 캔들을 메모리에 들고 있는 interfaces.DataFeeder.
+// 다른 거래소 데이터나 공유받은 데이터셋으로 백테스트할 때 사용합니다.
+type Feeder struct {
+	mu      sync.RWMutex
+	candles map[string][]model.Candle // key=PAIR_timeframe
+}
+
+func NewFeeder() *Feeder {
+	return &Feeder{candles: make(map[string][]model.Candle)}
+}
+
+// Add : pair/timeframe 에 캔들을 추가합니다. 같은 시각의 봉은 나중 것으로 덮어씁니다.
+func (f *Feeder) Add(pair, timeframe string, candles []model.Candle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := feederKey(pair, timeframe)
+	byTime := make(map[int64]model.Candle)
+	for _, c := range append(f.candles[key], candles...) {
+		if c.Pair == "" {
+			c.Pair = pair
+		}
+		c.Complete = true
+		byTime[c.Time.UnixNano()] = c
+	}
+
+	merged := make([]model.Candle, 0, len(byTime))
+	for _, c := range byTime {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	f.candles[key] = merged
+}
+
+// LoadFile : 파일(.csv/.jsonl)을 읽어 pair/timeframe 에 추가
+func (f *Feeder) LoadFile(pair, timeframe, path string, opts Options) error {
+	if opts.Pair == "" {
+		opts.Pair = pair
+	}
+	candles, err := LoadFile(path, opts)
+	if err != nil {
+		return err
+	}
+	f.Add(pair, timeframe, candles)
+	return nil
+}
+
+func (f *Feeder) Start() {}
+func (f *Feeder) Stop()  {}
+
+func (f *Feeder) AssetsInfo(pair string) model.AssetInfo {
+	return model.AssetInfo{}
+}
+
+// LastQuote : 불러온 타임프레임 중 가장 짧은 것의 마지막 종가
+func (f *Feeder) LastQuote(pair string) (float64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var last []model.Candle
+	var shortest time.Duration
+	prefix := strings.ToUpper(pair) + "_"
+	for key, candles := range f.candles {
+		timeframe, ok := strings.CutPrefix(key, prefix)
+		if !ok || len(candles) == 0 {
+			continue
+		}
+		dur, err := tools.ParseTimeframeToDuration(timeframe)
+		if err != nil {
+			continue
+		}
+		if last == nil || dur < shortest {
+			last, shortest = candles, dur
+		}
+	}
+	if last == nil {
+		return 0, fmt.Errorf("%w: %s", ErrNoData, pair)
+	}
+	return last[len(last)-1].Close, nil
+}
+
+func (f *Feeder) CandlesByLimit(pair, period string, limit int) ([]model.Candle, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	candles := f.candles[feederKey(pair, period)]
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("%w: %s-%s", ErrNoData, pair, period)
+	}
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return append([]model.Candle(nil), candles...), nil
+}
+
+func (f *Feeder) CandlesByPeriod(pair, period string, start, end time.Time) ([]model.Candle, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	candles := f.candles[feederKey(pair, period)]
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("%w: %s-%s", ErrNoData, pair, period)
+	}
+	lo := sort.Search(len(candles), func(i int) bool { return !candles[i].Time.Before(start) })
+	hi := sort.Search(len(candles), func(i int) bool { return candles[i].Time.After(end) })
+	if lo >= hi {
+		return nil, nil
+	}
+	return append([]model.Candle(nil), candles[lo:hi]...), nil
+}
+
+// CandlesSubscription : 실시간 스트림은 제공하지 않습니다.
+func (f *Feeder) CandlesSubscription(pair, timeframe string) (chan model.Candle, chan error) {
+	cCandle := make(chan model.Candle)
+	cErr := make(chan error, 1)
+	cErr <- fmt.Errorf("data: realtime subscription not supported (%s-%s)", pair, timeframe)
+	return cCandle, cErr
+}
+
+func feederKey(pair, timeframe string) string {
+	return strings.ToUpper(pair) + "_" + timeframe
+}
@@ -0,0 +1,230 @@
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"raccoon/model"
+)
+
+// ReadJSONL : 한 줄에 캔들 하나인 JSON Lines 를 읽습니다.
+// model.Candle 의 필드명(및 CSV와 같은 별칭)을 인식하고, 나머지 숫자 필드는 Metadata 로 매핑됩니다.
+func ReadJSONL(r io.Reader, opts Options) ([]model.Candle, error) {
+	loc := opts.location()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var candles []model.Candle
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text), &fields); err != nil {
+			return nil, fmt.Errorf("data: line %d: %w", line, err)
+		}
+		candle, err := candleFromFields(fields, opts, loc)
+		if err != nil {
+			return nil, fmt.Errorf("data: line %d: %w", line, err)
+		}
+		candles = append(candles, candle)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sortCandles(candles)
+	return candles, nil
+}
+
+// WriteJSONL : model.Candle 의 JSON 형식 그대로 한 줄씩 씁니다.
+// JSON 으로 표현할 수 없는 NaN/Inf 지표 값(워밍업 구간 등)은 Metadata 에서 빠집니다.
+func WriteJSONL(w io.Writer, candles []model.Candle) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, c := range candles {
+		c.Metadata = finiteMetadata(c.Metadata)
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func WriteDataframeJSONL(w io.Writer, df *model.Dataframe) error {
+	return WriteJSONL(w, DataframeToCandles(df))
+}
+
+func LoadJSONL(path string, opts Options) ([]model.Candle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadJSONL(f, opts)
+}
+
+func SaveJSONL(path string, candles []model.Candle) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteJSONL(f, candles); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadFile : 확장자(.csv, .jsonl, .ndjson)로 형식을 골라 읽습니다.
+func LoadFile(path string, opts Options) ([]model.Candle, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return LoadCSV(path, opts)
+	case ".jsonl", ".ndjson":
+		return LoadJSONL(path, opts)
+	default:
+		return nil, fmt.Errorf("data: unsupported file type: %s", path)
+	}
+}
+
+// SaveFile : 확장자(.csv, .jsonl, .ndjson)로 형식을 골라 씁니다.
+func SaveFile(path string, candles []model.Candle) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return SaveCSV(path, candles)
+	case ".jsonl", ".ndjson":
+		return SaveJSONL(path, candles)
+	default:
+		return fmt.Errorf("data: unsupported file type: %s", path)
+	}
+}
+
+func candleFromFields(fields map[string]json.RawMessage, opts Options, loc *time.Location) (model.Candle, error) {
+	candle := model.Candle{Pair: opts.Pair, Complete: true}
+	seen := make(map[string]bool)
+
+	for name, raw := range fields {
+		key := strings.ToLower(name)
+		field, known := columnAliases[key]
+		switch {
+		case key == "metadata":
+			var meta map[string]float64
+			if err := json.Unmarshal(raw, &meta); err != nil {
+				return candle, fmt.Errorf("metadata: %w", err)
+			}
+			for k, v := range meta {
+				setMetadata(&candle, k, v)
+			}
+		case key == "updatedat" || key == "complete":
+			// 저장된 model.Candle 을 다시 읽는 경우. 완성된 봉으로 취급
+		case known && !seen[field]:
+			seen[field] = true
+			if err := setField(&candle, field, raw, opts, loc); err != nil {
+				return candle, err
+			}
+		default:
+			if v, ok := rawNumber(raw); ok {
+				setMetadata(&candle, name, v)
+			}
+		}
+	}
+
+	for _, field := range []string{"time", "open", "high", "low", "close"} {
+		if !seen[field] {
+			return candle, fmt.Errorf("%w: %s", ErrMissingColumn, field)
+		}
+	}
+	candle.UpdatedAt = candle.Time
+	return candle, nil
+}
+
+func setField(candle *model.Candle, field string, raw json.RawMessage, opts Options, loc *time.Location) error {
+	if field == "pair" {
+		var pair string
+		if err := json.Unmarshal(raw, &pair); err != nil {
+			return fmt.Errorf("pair: %w", err)
+		}
+		if pair != "" {
+			candle.Pair = pair
+		}
+		return nil
+	}
+	if field == "time" {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw) // 숫자 타임스탬프
+		}
+		t, err := parseTime(value, opts.TimeLayout, loc)
+		if err != nil {
+			return err
+		}
+		candle.Time = t
+		return nil
+	}
+
+	v, ok := rawNumber(raw)
+	if !ok {
+		return fmt.Errorf("%s: not a number: %s", field, raw)
+	}
+	switch field {
+	case "open":
+		candle.Open = v
+	case "high":
+		candle.High = v
+	case "low":
+		candle.Low = v
+	case "close":
+		candle.Close = v
+	case "volume":
+		candle.Volume = v
+	}
+	return nil
+}
+
+// rawNumber : 숫자 또는 숫자 문자열
+func rawNumber(raw json.RawMessage) (float64, bool) {
+	var v float64
+	if err := json.Unmarshal(raw, &v); err == nil {
+		return v, true
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+func setMetadata(candle *model.Candle, key string, v float64) {
+	if candle.Metadata == nil {
+		candle.Metadata = make(map[string]float64)
+	}
+	candle.Metadata[key] = v
+}
+
+func finiteMetadata(meta map[string]float64) map[string]float64 {
+	for _, v := range meta {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			out := make(map[string]float64, len(meta))
+			for k, v := range meta {
+				if !math.IsNaN(v) && !math.IsInf(v, 0) {
+					out[k] = v
+				}
+			}
+			return out
+		}
+	}
+	return meta
+}
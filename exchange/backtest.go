@@ -1,46 +1,583 @@
 package exchange
 
-import "raccoon/model"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
 
-type BacktestBroker struct {
-	Pair        string
-	KRW         float64
-	Coin        float64
+	"raccoon/model"
+	"raccoon/utils/collection"
+	"raccoon/utils/tools"
+)
+
+const (
+	backtestQuoteCurrency = "KRW"
+	backtestMinTotal      = 5000.0
+)
+
+var (
+	ErrBacktestInsufficientFunds = errors.New("backtest: insufficient funds")
+	ErrBacktestMinTotal          = errors.New("backtest: order total below minimum")
+	ErrBacktestNoPrice           = errors.New("backtest: no price for pair")
+	ErrBacktestOrderNotFound     = fmt.Errorf("backtest: %w", ErrOrderNotFound)
+	ErrBacktestInvalidOrder      = errors.New("backtest: invalid order")
+)
+
+type backtestBalance struct {
+	Balance     float64
+	Locked      float64
 	AvgBuyPrice float64
 }
 
+type backtestOrder struct {
+	order       model.Order
+	tif         model.TimeInForceType
+	lockedQuote float64 // 지정가 매수 시 묶인 KRW (수수료 포함)
+	lockedBase  float64 // 지정가 매도 시 묶인 코인 수량
+	fee         float64 // 실제 지불한 수수료
+}
+
+// BacktestBroker : 캔들 스트림을 기준으로 주문을 체결시키는 interfaces.Broker 구현체
+//   - 시장가/최유리 주문은 마지막 캔들의 종가로 즉시 체결
+//   - 지정가 주문은 미체결로 남아 이후 캔들의 고가/저가가 지정가에 닿으면 체결
+//   - 수수료, 호가 단위(validatePrice), 최소 주문금액, 묶인 잔고(locked)는 Upbit과 동일하게 처리
+type BacktestBroker struct {
+	Pair     string
+	FeeRate  float64
+	MinTotal float64
+
+	mu          sync.Mutex
+	balances    map[string]*backtestBalance // key=currency
+	lastCandle  map[string]model.Candle     // key=pair
+	orders      map[string]*backtestOrder   // key=ExchangeID
+	identifiers map[string]string           // identifier → ExchangeID
+	history     []*backtestOrder            // 생성 순서
+	seq         int64
+	now         time.Time
+	clock       tools.Clock // 설정 시 주문 시각을 이 시계 기준으로 기록
+	idPrefix    string      // 주문 ExchangeID 접두사
+}
+
 func NewBackTestBroker(pair string, initialKRW float64) *BacktestBroker {
 	return &BacktestBroker{
-		Pair:        pair,
-		KRW:         initialKRW,
-		Coin:        0.0,
-		AvgBuyPrice: 0.0,
+		Pair:     pair,
+		FeeRate:  upbitDiscountFeeRate,
+		MinTotal: backtestMinTotal,
+		balances: map[string]*backtestBalance{
+			backtestQuoteCurrency: {Balance: initialKRW},
+		},
+		lastCandle:  make(map[string]model.Candle),
+		orders:      make(map[string]*backtestOrder),
+		identifiers: make(map[string]string),
+		idPrefix:    "backtest",
 	}
 }
 
-func (b *BacktestBroker) Position(pair string) (asset, quote, avgBuyPrice float64, err error) {
-	return b.Coin, b.KRW, b.AvgBuyPrice, nil
+// SetClock : 주문 생성/체결 시각을 기록할 시계를 지정합니다. (백테스트 엔진의 시뮬레이션 시계)
+func (b *BacktestBroker) SetClock(clock tools.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = clock
+}
+
+// OnCandle : 새 캔들로 시세를 갱신하고, 대기 중인 지정가 주문을 매칭합니다.
+// 전략이 같은 캔들을 보기 전에 호출해야 "이전 봉에서 낸 주문"이 이번 봉에서 체결됩니다.
+func (b *BacktestBroker) OnCandle(candle model.Candle) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pair := strings.ToUpper(candle.Pair)
+	b.lastCandle[pair] = candle
+	if candle.Time.After(b.now) {
+		b.now = candle.Time
+	}
+
+	for _, bo := range b.history {
+		o := &bo.order
+		if o.Status != model.OrderStatusTypeWait || o.Pair != pair {
+			continue
+		}
+		switch o.Side {
+		case model.SideTypeBuy:
+			if candle.Low <= o.Price {
+				// 시가가 지정가보다 낮게 출발했다면 시가에 체결
+				b.fill(bo, math.Min(o.Price, candle.Open), o.Quantity)
+			}
+		case model.SideTypeSell:
+			if candle.High >= o.Price {
+				b.fill(bo, math.Max(o.Price, candle.Open), o.Quantity)
+			}
+		}
+	}
 }
 
 func (b *BacktestBroker) Account() (model.Asset, error) {
-	return model.Asset{}, nil
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	currencies := make([]string, 0, len(b.balances))
+	for c := range b.balances {
+		currencies = append(currencies, c)
+	}
+	collection.Sort(currencies, func(x, y string) bool {
+		if x == backtestQuoteCurrency || y == backtestQuoteCurrency {
+			return x == backtestQuoteCurrency
+		}
+		return x < y
+	})
+
+	balances := make([]model.Balance, 0, len(currencies))
+	for _, c := range currencies {
+		bal := b.balances[c]
+		if c != backtestQuoteCurrency && bal.Balance+bal.Locked <= 0 {
+			continue
+		}
+		balances = append(balances, model.Balance{
+			Currency:     c,
+			Balance:      bal.Balance,
+			Locked:       bal.Locked,
+			AvgBuyPrice:  bal.AvgBuyPrice,
+			UnitCurrency: backtestQuoteCurrency,
+		})
+	}
+	return model.Asset{Balances: balances}, nil
+}
+
+func (b *BacktestBroker) Position(pair string) (asset, quote, avgBuyPrice float64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	base, quoteAsset := SplitAssetQuote(strings.ToUpper(pair))
+	baseBal := b.balance(base)
+	quoteBal := b.balance(quoteAsset)
+	return baseBal.Balance + baseBal.Locked, quoteBal.Balance + quoteBal.Locked, baseBal.AvgBuyPrice, nil
 }
+
 func (b *BacktestBroker) OrderChance(pair string) (*model.OrderChance, error) {
-	return &model.OrderChance{}, nil
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pair = strings.ToUpper(pair)
+	base, quoteAsset := SplitAssetQuote(pair)
+	baseBal := b.balance(base)
+	quoteBal := b.balance(quoteAsset)
+	fee := floatToString(b.FeeRate)
+	minTotal := floatToString(b.MinTotal)
+
+	return &model.OrderChance{
+		BidFee:      fee,
+		AskFee:      fee,
+		MakerBidFee: fee,
+		MakerAskFee: fee,
+		Market: model.Market{
+			ID:         pair,
+			Name:       pair,
+			OrderTypes: []string{string(model.OrderTypeLimit)},
+			OrderSides: []string{string(model.SideTypeSell), string(model.SideTypeBuy)},
+			BidTypes:   []string{string(model.OrderTypeLimit), string(model.OrderTypePrice), string(model.OrderTypeBest)},
+			AskTypes:   []string{string(model.OrderTypeLimit), string(model.OrderTypeMarket), string(model.OrderTypeBest)},
+			Bid:        model.OrderMin{Currency: quoteAsset, MinTotal: minTotal},
+			Ask:        model.OrderMin{Currency: base, MinTotal: minTotal},
+			State:      "active",
+		},
+		BidAccount: model.OrderAccount{
+			Currency:     quoteAsset,
+			Balance:      floatToString(quoteBal.Balance),
+			Locked:       floatToString(quoteBal.Locked),
+			UnitCurrency: backtestQuoteCurrency,
+		},
+		AskAccount: model.OrderAccount{
+			Currency:     base,
+			Balance:      floatToString(baseBal.Balance),
+			Locked:       floatToString(baseBal.Locked),
+			AvgBuyPrice:  floatToString(baseBal.AvgBuyPrice),
+			UnitCurrency: backtestQuoteCurrency,
+		},
+	}, nil
 }
+
 func (b *BacktestBroker) Order(pair string, uuidOrIdentifier string, isIdentifier bool) (model.Order, error) {
-	return model.Order{}, nil
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if isIdentifier {
+		uuidOrIdentifier = b.identifiers[uuidOrIdentifier]
+	}
+	bo, ok := b.orders[uuidOrIdentifier]
+	if !ok {
+		return model.Order{}, fmt.Errorf("%w: %s", ErrBacktestOrderNotFound, uuidOrIdentifier)
+	}
+	return bo.order, nil
 }
+
 func (b *BacktestBroker) OpenOrders(pair string, limit int) ([]model.Order, error) {
-	return []model.Order{}, nil
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pair = strings.ToUpper(pair)
+	result := make([]model.Order, 0)
+	// order_by=desc : 최신 주문부터
+	for i := len(b.history) - 1; i >= 0; i-- {
+		o := b.history[i].order
+		if o.Pair != pair || o.Status != model.OrderStatusTypeWait {
+			continue
+		}
+		result = append(result, o)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
 }
+
 func (b *BacktestBroker) CreateOrderLimit(side model.SideType, pair string, quantity, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
-	return model.Order{}, nil
+	return b.CreateOrderLimitIdentified("", side, pair, quantity, limit, tif...)
 }
+
+// CreateOrderLimitIdentified : identifier 로도 조회/취소할 수 있는 지정가 주문
+func (b *BacktestBroker) CreateOrderLimitIdentified(identifier string, side model.SideType, pair string, quantity, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pair = strings.ToUpper(pair)
+	if err := b.checkIdentifier(identifier); err != nil {
+		return model.Order{}, err
+	}
+	if quantity <= 0 || limit <= 0 {
+		return model.Order{}, fmt.Errorf("%w: quantity=%f, limit=%f", ErrBacktestInvalidOrder, quantity, limit)
+	}
+	current, err := b.lastPrice(pair)
+	if err != nil {
+		return model.Order{}, err
+	}
+
+	price := validatePrice(limit, pair)
+	total := price * quantity
+	if total < b.MinTotal {
+		return model.Order{}, fmt.Errorf("%w: total=%.2f, min=%.2f", ErrBacktestMinTotal, total, b.MinTotal)
+	}
+
+	base, quoteAsset := SplitAssetQuote(pair)
+	bo := b.newOrder(identifier, side, model.OrderTypeLimit, pair, price, quantity)
+	if len(tif) == 1 {
+		bo.tif = tif[0]
+	}
+
+	// 주문 금액(또는 수량) 잠금
+	switch side {
+	case model.SideTypeBuy:
+		need := total * (1 + b.FeeRate)
+		quoteBal := b.balance(quoteAsset)
+		if quoteBal.Balance < need {
+			return model.Order{}, fmt.Errorf("%w: need=%.2f, available=%.2f", ErrBacktestInsufficientFunds, need, quoteBal.Balance)
+		}
+		quoteBal.Balance -= need
+		quoteBal.Locked += need
+		bo.lockedQuote = need
+	case model.SideTypeSell:
+		baseBal := b.balance(base)
+		if baseBal.Balance < quantity {
+			return model.Order{}, fmt.Errorf("%w: need=%.8f, available=%.8f", ErrBacktestInsufficientFunds, quantity, baseBal.Balance)
+		}
+		baseBal.Balance -= quantity
+		baseBal.Locked += quantity
+		bo.lockedBase = quantity
+	default:
+		return model.Order{}, fmt.Errorf("%w: side=%s", ErrBacktestInvalidOrder, side)
+	}
+	b.register(bo)
+
+	// 현재가 기준으로 즉시 체결 가능한 지정가는 taker로 현재가에 체결
+	marketable := (side == model.SideTypeBuy && price >= current) ||
+		(side == model.SideTypeSell && price <= current)
+	if marketable {
+		b.fill(bo, current, quantity)
+	} else if bo.tif == model.TimeInForceIOC || bo.tif == model.TimeInForceFOK {
+		b.cancel(bo)
+	}
+	return bo.order, nil
+}
+
 func (b *BacktestBroker) CreateOrderMarket(side model.SideType, pair string, quantity float64) (model.Order, error) {
-	return model.Order{}, nil
+	return b.CreateOrderMarketIdentified("", side, pair, quantity)
 }
+
+// CreateOrderMarketIdentified : identifier 로도 조회할 수 있는 시장가 주문
+func (b *BacktestBroker) CreateOrderMarketIdentified(identifier string, side model.SideType, pair string, quantity float64) (model.Order, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pair = strings.ToUpper(pair)
+	if err := b.checkIdentifier(identifier); err != nil {
+		return model.Order{}, err
+	}
+	if side == model.SideTypeBuy {
+		// Upbit.CreateOrderMarket 과 동일하게 수수료를 제외한 금액으로 주문
+		funds := validatePrice(quantity/(1+b.FeeRate), pair)
+		return b.executeNow(identifier, side, model.OrderTypePrice, pair, funds)
+	}
+	return b.executeNow(identifier, side, model.OrderTypeMarket, pair, quantity)
+}
+
 func (b *BacktestBroker) CreateOrderBest(side model.SideType, pair string, quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
-	return model.Order{}, nil
+	return b.CreateOrderBestIdentified("", side, pair, quantity, tif...)
+}
+
+// CreateOrderBestIdentified : identifier 로도 조회할 수 있는 최유리 주문
+func (b *BacktestBroker) CreateOrderBestIdentified(identifier string, side model.SideType, pair string, quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
+	if len(tif) != 1 {
+		return model.Order{}, fmt.Errorf("tif must be exist and exactly one parameter")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkIdentifier(identifier); err != nil {
+		return model.Order{}, err
+	}
+	// 캔들 데이터에는 호가 잔량이 없으므로 IOC/FOK 모두 현재가에 전량 체결된다고 가정
+	return b.executeNow(identifier, side, model.OrderTypeBest, strings.ToUpper(pair), quantity)
+}
+
+func (b *BacktestBroker) Cancel(order model.Order, isIdentifier bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := order.ExchangeID
+	if isIdentifier {
+		id = b.identifiers[order.ExchangeID]
+	}
+	bo, ok := b.orders[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrBacktestOrderNotFound, order.ExchangeID)
+	}
+	if bo.order.Status != model.OrderStatusTypeWait {
+		return fmt.Errorf("%w: order %s is %s", ErrBacktestInvalidOrder, order.ExchangeID, bo.order.Status)
+	}
+	b.cancel(bo)
+	return nil
+}
+
+// LastQuote : 마지막으로 받은 봉의 종가
+func (b *BacktestBroker) LastQuote(pair string) (float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastPrice(pair)
+}
+
+// Orders : 지금까지 생성된 모든 주문(체결/취소/대기)을 생성 순서대로 반환합니다.
+func (b *BacktestBroker) Orders() []model.Order {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return collection.Map(b.history, func(bo *backtestOrder) model.Order {
+		return bo.order
+	})
+}
+
+// Equity : KRW 잔고 + 보유 코인의 평가금액 (마지막 종가 기준)
+func (b *BacktestBroker) Equity() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var equity float64
+	for currency, bal := range b.balances {
+		amount := bal.Balance + bal.Locked
+		if currency == backtestQuoteCurrency {
+			equity += amount
+			continue
+		}
+		if c, ok := b.lastCandle[backtestQuoteCurrency+"-"+currency]; ok {
+			equity += amount * c.Close
+		}
+	}
+	return equity
+}
+
+// Cash : KRW 잔고 (묶인 금액 포함)
+func (b *BacktestBroker) Cash() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bal := b.balance(backtestQuoteCurrency)
+	return bal.Balance + bal.Locked
+}
+
+// executeNow : 시장가/최유리 주문을 현재가로 즉시 체결합니다.
+// 매수(price/best)는 quantity가 KRW 금액, 매도(market/best)는 quantity가 코인 수량입니다.
+func (b *BacktestBroker) executeNow(identifier string, side model.SideType, orderType model.OrderType, pair string, quantity float64) (model.Order, error) {
+	if quantity <= 0 {
+		return model.Order{}, fmt.Errorf("%w: quantity=%f", ErrBacktestInvalidOrder, quantity)
+	}
+	current, err := b.lastPrice(pair)
+	if err != nil {
+		return model.Order{}, err
+	}
+
+	base, quoteAsset := SplitAssetQuote(pair)
+	var volume float64
+	switch side {
+	case model.SideTypeBuy:
+		if quantity < b.MinTotal {
+			return model.Order{}, fmt.Errorf("%w: total=%.2f, min=%.2f", ErrBacktestMinTotal, quantity, b.MinTotal)
+		}
+		need := quantity * (1 + b.FeeRate)
+		if b.balance(quoteAsset).Balance < need {
+			return model.Order{}, fmt.Errorf("%w: need=%.2f, available=%.2f", ErrBacktestInsufficientFunds, need, b.balance(quoteAsset).Balance)
+		}
+		volume = quantity / current
+	case model.SideTypeSell:
+		if total := quantity * current; total < b.MinTotal {
+			return model.Order{}, fmt.Errorf("%w: total=%.2f, min=%.2f", ErrBacktestMinTotal, total, b.MinTotal)
+		}
+		if b.balance(base).Balance < quantity {
+			return model.Order{}, fmt.Errorf("%w: need=%.8f, available=%.8f", ErrBacktestInsufficientFunds, quantity, b.balance(base).Balance)
+		}
+		volume = quantity
+	default:
+		return model.Order{}, fmt.Errorf("%w: side=%s", ErrBacktestInvalidOrder, side)
+	}
+
+	bo := b.newOrder(identifier, side, orderType, pair, current, volume)
+	b.register(bo)
+	b.fill(bo, current, volume)
+	return bo.order, nil
+}
+
+// fill : 주문을 price에 volume만큼 전량 체결시키고 잔고를 갱신합니다.
+func (b *BacktestBroker) fill(bo *backtestOrder, price, volume float64) {
+	base, quoteAsset := SplitAssetQuote(bo.order.Pair)
+	baseBal := b.balance(base)
+	quoteBal := b.balance(quoteAsset)
+
+	funds := price * volume
+	fee := funds * b.FeeRate
+
+	switch bo.order.Side {
+	case model.SideTypeBuy:
+		if bo.lockedQuote > 0 {
+			// 지정가 매수: 묶어둔 금액에서 차감하고 남은 금액은 돌려줌
+			quoteBal.Locked -= bo.lockedQuote
+			quoteBal.Balance += bo.lockedQuote - (funds + fee)
+			bo.lockedQuote = 0
+		} else {
+			quoteBal.Balance -= funds + fee
+		}
+		holding := baseBal.Balance + baseBal.Locked
+		baseBal.AvgBuyPrice = (baseBal.AvgBuyPrice*holding + funds) / (holding + volume)
+		baseBal.Balance += volume
+	case model.SideTypeSell:
+		if bo.lockedBase > 0 {
+			baseBal.Locked -= bo.lockedBase
+			baseBal.Balance += bo.lockedBase - volume
+			bo.lockedBase = 0
+		} else {
+			baseBal.Balance -= volume
+		}
+		if baseBal.Balance+baseBal.Locked <= 0 {
+			baseBal.AvgBuyPrice = 0
+		}
+		quoteBal.Balance += funds - fee
+	}
+
+	now := b.currentTime()
+	bo.fee += fee
+	bo.order.Price = price
+	bo.order.Quantity = volume
+	bo.order.Status = model.OrderStatusTypeDone
+	bo.order.UpdatedAt = now
+	bo.order.Fills = append(bo.order.Fills, model.Fill{
+		TradeID:  fmt.Sprintf("%s-%d", bo.order.ExchangeID, len(bo.order.Fills)+1),
+		Price:    price,
+		Quantity: volume,
+		Funds:    funds,
+		Fee:      fee,
+		Time:     now,
+	})
+	bo.order.ExecutedQuantity = volume
+	bo.order.AvgPrice = price
+	bo.order.PaidFee = bo.fee
+}
+
+func (b *BacktestBroker) cancel(bo *backtestOrder) {
+	base, quoteAsset := SplitAssetQuote(bo.order.Pair)
+	if bo.lockedQuote > 0 {
+		quoteBal := b.balance(quoteAsset)
+		quoteBal.Locked -= bo.lockedQuote
+		quoteBal.Balance += bo.lockedQuote
+		bo.lockedQuote = 0
+	}
+	if bo.lockedBase > 0 {
+		baseBal := b.balance(base)
+		baseBal.Locked -= bo.lockedBase
+		baseBal.Balance += bo.lockedBase
+		bo.lockedBase = 0
+	}
+	bo.order.Status = model.OrderStatusTypeCanceled
+	bo.order.UpdatedAt = b.currentTime()
+}
+
+func (b *BacktestBroker) newOrder(identifier string, side model.SideType, orderType model.OrderType, pair string, price, quantity float64) *backtestOrder {
+	b.seq++
+	now := b.currentTime()
+	return &backtestOrder{
+		order: model.Order{
+			ID:         b.seq,
+			ExchangeID: fmt.Sprintf("%s-%d", b.idPrefix, b.seq),
+			Identifier: identifier,
+			Pair:       pair,
+			Side:       side,
+			Type:       orderType,
+			Status:     model.OrderStatusTypeWait,
+			Price:      price,
+			Quantity:   quantity,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Candle:     b.lastCandle[pair],
+		},
+	}
+}
+
+func (b *BacktestBroker) register(bo *backtestOrder) {
+	b.orders[bo.order.ExchangeID] = bo
+	if bo.order.Identifier != "" {
+		b.identifiers[bo.order.Identifier] = bo.order.ExchangeID
+	}
+	b.history = append(b.history, bo)
+}
+
+// checkIdentifier : Upbit 과 같이 이미 사용한 identifier 로는 다시 주문할 수 없음
+func (b *BacktestBroker) checkIdentifier(identifier string) error {
+	if _, dup := b.identifiers[identifier]; dup && identifier != "" {
+		return fmt.Errorf("%w: identifier %s already used", ErrBacktestInvalidOrder, identifier)
+	}
+	return nil
+}
+
+func (b *BacktestBroker) balance(currency string) *backtestBalance {
+	currency = strings.ToUpper(currency)
+	bal, ok := b.balances[currency]
+	if !ok {
+		bal = &backtestBalance{}
+		b.balances[currency] = bal
+	}
+	return bal
+}
+
+func (b *BacktestBroker) currentTime() time.Time {
+	if b.clock != nil {
+		return b.clock.Now()
+	}
+	return b.now
+}
+
+func (b *BacktestBroker) lastPrice(pair string) (float64, error) {
+	c, ok := b.lastCandle[pair]
+	if !ok || c.Close <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrBacktestNoPrice, pair)
+	}
+	return c.Close, nil
 }
-func (b *BacktestBroker) Cancel(order model.Order, isIdentifier bool) error { return nil }
@@ -0,0 +1,140 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/tools"
+)
+
+// PaperBroker : 실시간 시세로 가상 잔고에서 주문을 체결시키는 interfaces.Exchange 구현체 (모의투자)
+//   - 캔들 조회/실시간 구독/현재가는 감싼 DataFeeder(보통 Upbit)에 그대로 위임
+//   - 주문은 직전에 조회한 현재가(LastQuote)로 BacktestBroker 와 같은 규칙(수수료, 호가 단위, 최소 주문금액)으로 체결
+//   - 지정가 주문은 OnCandle 로 들어오는 실시간 가격이 지정가에 닿으면 체결
+//   - 반환하는 주문은 모두 Paper=true
+type PaperBroker struct {
+	interfaces.DataFeeder
+	sim *BacktestBroker
+}
+
+func NewPaperBroker(feeder interfaces.DataFeeder, initialKRW float64) *PaperBroker {
+	sim := NewBackTestBroker("", initialKRW)
+	sim.idPrefix = "paper"
+	sim.SetClock(tools.RealClock{})
+	return &PaperBroker{DataFeeder: feeder, sim: sim}
+}
+
+// OnCandle : 실시간 캔들(미완성 포함)의 현재가로 시세를 갱신하고 대기 중인 지정가 주문을 매칭합니다.
+// 미완성 봉의 고가/저가는 주문 전에 지나간 가격일 수 있으므로 종가(현재가)만 사용합니다.
+func (p *PaperBroker) OnCandle(candle model.Candle) {
+	p.sim.OnCandle(quoteCandle(candle.Pair, candle.Close, time.Now()))
+}
+
+func (p *PaperBroker) Account() (model.Asset, error) {
+	return p.sim.Account()
+}
+
+func (p *PaperBroker) Position(pair string) (asset, quote, avgBuyPrice float64, err error) {
+	return p.sim.Position(pair)
+}
+
+func (p *PaperBroker) OrderChance(pair string) (*model.OrderChance, error) {
+	return p.sim.OrderChance(pair)
+}
+
+func (p *PaperBroker) Order(pair string, uuidOrIdentifier string, isIdentifier bool) (model.Order, error) {
+	return paperOrder(p.sim.Order(pair, uuidOrIdentifier, isIdentifier))
+}
+
+func (p *PaperBroker) OpenOrders(pair string, limit int) ([]model.Order, error) {
+	orders, err := p.sim.OpenOrders(pair, limit)
+	for i := range orders {
+		orders[i].Paper = true
+	}
+	return orders, err
+}
+
+func (p *PaperBroker) CreateOrderLimit(side model.SideType, pair string, quantity, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
+	if err := p.refresh(pair); err != nil {
+		return model.Order{}, err
+	}
+	return paperOrder(p.sim.CreateOrderLimit(side, pair, quantity, limit, tif...))
+}
+
+func (p *PaperBroker) CreateOrderMarket(side model.SideType, pair string, quantity float64) (model.Order, error) {
+	if err := p.refresh(pair); err != nil {
+		return model.Order{}, err
+	}
+	return paperOrder(p.sim.CreateOrderMarket(side, pair, quantity))
+}
+
+func (p *PaperBroker) CreateOrderLimitIdentified(identifier string, side model.SideType, pair string, quantity, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
+	if err := p.refresh(pair); err != nil {
+		return model.Order{}, err
+	}
+	return paperOrder(p.sim.CreateOrderLimitIdentified(identifier, side, pair, quantity, limit, tif...))
+}
+
+func (p *PaperBroker) CreateOrderMarketIdentified(identifier string, side model.SideType, pair string, quantity float64) (model.Order, error) {
+	if err := p.refresh(pair); err != nil {
+		return model.Order{}, err
+	}
+	return paperOrder(p.sim.CreateOrderMarketIdentified(identifier, side, pair, quantity))
+}
+
+func (p *PaperBroker) CreateOrderBestIdentified(identifier string, side model.SideType, pair string, quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
+	if err := p.refresh(pair); err != nil {
+		return model.Order{}, err
+	}
+	return paperOrder(p.sim.CreateOrderBestIdentified(identifier, side, pair, quantity, tif...))
+}
+
+func (p *PaperBroker) CreateOrderBest(side model.SideType, pair string, quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
+	if err := p.refresh(pair); err != nil {
+		return model.Order{}, err
+	}
+	return paperOrder(p.sim.CreateOrderBest(side, pair, quantity, tif...))
+}
+
+func (p *PaperBroker) Cancel(order model.Order, isIdentifier bool) error {
+	return p.sim.Cancel(order, isIdentifier)
+}
+
+// Orders : 지금까지 낸 가상 주문 (생성 순서)
+func (p *PaperBroker) Orders() []model.Order {
+	orders := p.sim.Orders()
+	for i := range orders {
+		orders[i].Paper = true
+	}
+	return orders
+}
+
+// Equity : 가상 KRW 잔고 + 보유 코인 평가금액 (마지막으로 본 현재가 기준)
+func (p *PaperBroker) Equity() float64 {
+	return p.sim.Equity()
+}
+
+// refresh : 주문 직전에 현재가를 조회해 시뮬레이션 시세로 반영합니다.
+func (p *PaperBroker) refresh(pair string) error {
+	pair = strings.ToUpper(pair)
+	quote, err := p.DataFeeder.LastQuote(pair)
+	if err != nil {
+		return fmt.Errorf("paper: last quote %s: %w", pair, err)
+	}
+	p.sim.OnCandle(quoteCandle(pair, quote, time.Now()))
+	return nil
+}
+
+func quoteCandle(pair string, price float64, t time.Time) model.Candle {
+	return model.Candle{Pair: strings.ToUpper(pair), Time: t, Open: price, High: price, Low: price, Close: price}
+}
+
+func paperOrder(order model.Order, err error) (model.Order, error) {
+	if err == nil {
+		order.Paper = true
+	}
+	return order, err
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"raccoon/utils/collection"
 	"raccoon/utils/resty"
 	"raccoon/utils/tools"
@@ -14,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"raccoon/cache"
 	"raccoon/model"
 	"raccoon/utils/auth"
 	"raccoon/utils/log"
@@ -30,10 +32,26 @@ const (
 	Candle1s       = "candle.1s"
 
 	CandlePageLimit = 200
+
+	upbitBaseFeeRate     = 0.00139
+	upbitDiscountFeeRate = 0.0005
 )
 
 var KSTLocation, _ = time.LoadLocation("Asia/Seoul")
 
+// ErrOrderNotFound : uuid/identifier 에 해당하는 주문이 거래소에 없음 (주문이 접수되지 않았음)
+var ErrOrderNotFound = errors.New("order not found")
+
+// upbitAPIError : 2xx 가 아닌 REST 응답
+type upbitAPIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *upbitAPIError) Error() string {
+	return fmt.Sprintf("API 응답 오류: %d, %s", e.StatusCode, e.Body)
+}
+
 type Upbit struct {
 	ctx        context.Context
 	cancelFunc context.CancelFunc
@@ -49,9 +67,20 @@ type Upbit struct {
 	wsRunning bool
 	wsMtx     sync.Mutex
 
+	// Private WebSocket (myOrder, myAsset)
+	privateConn    *websocket.Conn
+	privateRunning bool
+	privateMtx     sync.Mutex
+	myOrderCh      chan model.UpbitMyOrderMessage
+	myAssetCh      chan model.UpbitMyAssetMessage
+	privateErrCh   chan error
+
 	assetsInfo map[string]model.AssetInfo
 
 	aggregatorMap map[string]*CandleAggregator
+	streams       map[string]publicStream // 공개 시세 스트림 (orderbook, trade, ticker). 캔들과 같은 연결로 받음
+
+	candleStore *cache.CandleStore // 설정 시 CandlesByPeriod 가 디스크 캐시를 먼저 사용
 }
 
 type CandleAggregator struct {
@@ -77,6 +106,10 @@ func NewUpbit(apiKey, secretKey string, pairs []string) (*Upbit, error) {
 		secretKey:     secretKey,
 		assetsInfo:    make(map[string]model.AssetInfo),
 		aggregatorMap: make(map[string]*CandleAggregator),
+		streams:       newPublicStreams(),
+		myOrderCh:     make(chan model.UpbitMyOrderMessage, 100),
+		myAssetCh:     make(chan model.UpbitMyAssetMessage, 100),
+		privateErrCh:  make(chan error, 10),
 	}
 	log.Info("[SETUP] Using Upbit exchange with pre-fetched pairs")
 	for _, pair := range pairs {
@@ -173,6 +206,10 @@ func (u *Upbit) Order(pair string, uuidOrIdentifier string, isIdentifier bool) (
 
 	body, err := u.requestUpbitGET(u.ctx, "/v1/order", params)
 	if err != nil {
+		var apiErr *upbitAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return model.Order{}, fmt.Errorf("%w: %s (%v)", ErrOrderNotFound, uuidOrIdentifier, err)
+		}
 		return model.Order{}, err
 	}
 	var orderResp model.OrderResponse
@@ -208,6 +245,12 @@ func (u *Upbit) ClosedOrders(pair string, limit int) ([]model.Order, error) {
 
 func (u *Upbit) CreateOrderLimit(side model.SideType, pair string,
 	quantity float64, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
+	return u.CreateOrderLimitIdentified("", side, pair, quantity, limit, tif...)
+}
+
+// CreateOrderLimitIdentified : identifier 를 붙인 지정가 주문. identifier 가 비어있으면 CreateOrderLimit 과 같음
+func (u *Upbit) CreateOrderLimitIdentified(identifier string, side model.SideType, pair string,
+	quantity float64, limit float64, tif ...model.TimeInForceType) (model.Order, error) {
 
 	validPrice := validatePrice(limit, pair)
 
@@ -221,6 +264,7 @@ func (u *Upbit) CreateOrderLimit(side model.SideType, pair string,
 	if len(tif) == 1 {
 		params["time_in_force"] = string(tif[0])
 	}
+	setIdentifier(params, identifier)
 	body, err := u.requestUpbitPOST(u.ctx, "/v1/orders", params)
 	if err != nil {
 		return model.Order{}, err
@@ -233,6 +277,11 @@ func (u *Upbit) CreateOrderLimit(side model.SideType, pair string,
 }
 
 func (u *Upbit) CreateOrderMarket(side model.SideType, pair string, quantity float64) (model.Order, error) {
+	return u.CreateOrderMarketIdentified("", side, pair, quantity)
+}
+
+// CreateOrderMarketIdentified : identifier 를 붙인 시장가 주문. identifier 가 비어있으면 CreateOrderMarket 과 같음
+func (u *Upbit) CreateOrderMarketIdentified(identifier string, side model.SideType, pair string, quantity float64) (model.Order, error) {
 	// Upbit 시장가 매수 => ord_type="price", side="bid", quantity = price=금액, volume=""
 	// Upbit 시장가 매도 => ord_type="market", side="ask", quantity = volume=수량, price=""
 	if side == model.SideTypeBuy {
@@ -251,6 +300,7 @@ func (u *Upbit) CreateOrderMarket(side model.SideType, pair string, quantity flo
 			"ord_type": string(model.OrderTypePrice),
 			"price":    floatToString(validPrice),
 		}
+		setIdentifier(params, identifier)
 		body, err := u.requestUpbitPOST(u.ctx, "/v1/orders", params)
 		if err != nil {
 			return model.Order{}, err
@@ -267,6 +317,7 @@ func (u *Upbit) CreateOrderMarket(side model.SideType, pair string, quantity flo
 			"ord_type": string(model.OrderTypeMarket),
 			"volume":   floatToString(quantity),
 		}
+		setIdentifier(params, identifier)
 		body, err := u.requestUpbitPOST(u.ctx, "/v1/orders", params)
 		if err != nil {
 			return model.Order{}, err
@@ -280,6 +331,12 @@ func (u *Upbit) CreateOrderMarket(side model.SideType, pair string, quantity flo
 }
 
 func (u *Upbit) CreateOrderBest(side model.SideType, pair string, quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
+	return u.CreateOrderBestIdentified("", side, pair, quantity, tif...)
+}
+
+// CreateOrderBestIdentified : identifier 를 붙인 최유리 주문. identifier 가 비어있으면 CreateOrderBest 와 같음
+func (u *Upbit) CreateOrderBestIdentified(identifier string, side model.SideType, pair string,
+	quantity float64, tif ...model.TimeInForceType) (model.Order, error) {
 	// ioc =>
 	//		매수 -> tif=ioc, side=bid, ord_type=best, quantity=price
 	// 		매도 -> tif=ioc, side=ask, ord_type=best, quantity=volume
@@ -301,6 +358,7 @@ func (u *Upbit) CreateOrderBest(side model.SideType, pair string, quantity float
 	} else {
 		params["volume"] = floatToString(quantity)
 	}
+	setIdentifier(params, identifier)
 
 	body, err := u.requestUpbitPOST(u.ctx, "/v1/orders", params)
 	if err != nil {
@@ -360,6 +418,26 @@ func (u *Upbit) LastQuote(pair string) (float64, error) {
 	return res[0].TradePrice, nil
 }
 
+// BestQuote : 최우선 매수/매도 호가 (실행 알고리즘의 지정가 기준)
+func (u *Upbit) BestQuote(pair string) (float64, float64, error) {
+	// GET /v1/orderbook?markets=KRW-BTC
+	params := map[string]interface{}{}
+	params["markets"] = pair
+	body, err := u.requestUpbitGET(u.ctx, "/v1/orderbook", params)
+	if err != nil {
+		return 0, 0, err
+	}
+	var res []model.UpbitOrderbookMessage
+	if err := json.Unmarshal(body, &res); err != nil {
+		return 0, 0, err
+	}
+	if len(res) < 1 || len(res[0].OrderbookUnits) < 1 {
+		return 0, 0, fmt.Errorf("no orderbook data for %s", pair)
+	}
+	best := res[0].OrderbookUnits[0]
+	return best.BidPrice, best.AskPrice, nil
+}
+
 func (u *Upbit) CandlesByLimit(pair, period string, limit int) ([]model.Candle, error) {
 	if limit > CandlePageLimit {
 		return nil, fmt.Errorf("candles limit exceeds 200")
@@ -411,14 +489,84 @@ func (u *Upbit) CandlesByLimit(pair, period string, limit int) ([]model.Candle,
 	return candles, nil
 }
 
+// SetCandleStore : 과거 캔들 조회 시 사용할 디스크 캐시를 지정합니다.
+func (u *Upbit) SetCandleStore(store *cache.CandleStore) {
+	u.candleStore = store
+}
+
+// CandlesByPeriod : [start, end] 구간의 캔들을 조회합니다.
+// 캔들 캐시가 설정되어 있으면 완성된 봉은 캐시에서 읽고, 아직 받지 않은 구간만 API로 받아 저장합니다.
 func (u *Upbit) CandlesByPeriod(pair, period string, start, end time.Time) ([]model.Candle, error) {
+	dur, ok := cache.Cacheable(period)
+	if u.candleStore == nil || !ok {
+		return u.fetchCandlesByPeriod(pair, period, start, end)
+	}
+
+	requested := cache.SlotRange(start.In(KSTLocation), end.In(KSTLocation), dur)
+	// 아직 끝나지 않은 봉은 캐시하지 않음
+	completed := time.Now().In(KSTLocation).Truncate(dur)
+	cached := requested
+	if cached.End.After(completed) {
+		cached.End = completed
+	}
+
+	if cached.End.After(cached.Start) {
+		missing, err := u.candleStore.Missing(pair, period, cached)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range missing {
+			log.Infof("[Upbit] candle cache miss %s-%s %v ~ %v", pair, period, r.Start.In(KSTLocation), r.End.In(KSTLocation))
+			candles, err := u.fetchCandlesByPeriod(pair, period, r.Start, r.End)
+			if err != nil {
+				return nil, err
+			}
+			if err := u.candleStore.Save(pair, period, r, candles); err != nil {
+				return nil, err
+			}
+		}
+		if gaps, err := u.candleStore.Gaps(pair, period, cached); err == nil && len(gaps) > 0 {
+			log.Warnf("[Upbit] %s-%s has %d gap(s) without candles in %v ~ %v",
+				pair, period, len(gaps), cached.Start.In(KSTLocation), cached.End.In(KSTLocation))
+		}
+	}
+
+	loadEnd := end
+	if !cached.End.After(end) {
+		loadEnd = cached.End.Add(-time.Nanosecond)
+	}
+	result, err := u.candleStore.Load(pair, period, start, loadEnd)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		result[i].Time = result[i].Time.In(KSTLocation)
+		result[i].UpdatedAt = result[i].UpdatedAt.In(KSTLocation)
+	}
+
+	// 진행 중인 구간은 캐시를 거치지 않고 바로 조회
+	if requested.End.After(completed) && !end.Before(completed) {
+		liveStart := start
+		if completed.After(start) {
+			liveStart = completed
+		}
+		live, err := u.fetchCandlesByPeriod(pair, period, liveStart, end)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, live...)
+	}
+	return result, nil
+}
+
+func (u *Upbit) fetchCandlesByPeriod(pair, period string, start, end time.Time) ([]model.Candle, error) {
 	endpoint, err := tools.MapPeriodToCandleEndpoint(period)
 	if err != nil {
 		return nil, err
 	}
 
 	var allCandles []model.Candle
-	toTime := end
+	toTime := end.In(KSTLocation) // to 파라미터는 KST 로 보냄
 
 	for {
 		toStr := toTime.Format("2006-01-02T15:04:05") + "+09:00"
@@ -489,23 +637,14 @@ func (u *Upbit) CandlesSubscription(pair, period string) (chan model.Candle, cha
 		return agg.candleCh, agg.errCh
 	}
 
-	dur, err := tools.ParseTimeframeToDuration(period)
+	agg, err := NewCandleAggregator(pair, period)
 	if err != nil {
 		cch := make(chan model.Candle)
 		ech := make(chan error, 1)
-		ech <- fmt.Errorf("invalid timeframe: %s", period)
+		ech <- err
 		close(ech)
 		return cch, ech
 	}
-
-	agg := &CandleAggregator{
-		pair:     pair,
-		period:   period,
-		duration: dur,
-		buffer:   make(map[time.Time]model.Candle),
-		candleCh: make(chan model.Candle),
-		errCh:    make(chan error),
-	}
 	u.aggregatorMap[key] = agg
 
 	now := time.Now().In(KSTLocation)
@@ -556,12 +695,24 @@ func (u *Upbit) Stop() {
 	}
 	u.wsMtx.Unlock()
 
+	u.privateMtx.Lock()
+	if u.privateConn != nil {
+		u.privateConn.Close()
+	}
+	u.privateMtx.Unlock()
+
 	u.wg.Wait()
 
 	for _, agg := range u.aggregatorMap {
 		close(agg.candleCh)
 		close(agg.errCh)
 	}
+	for _, stream := range u.streams {
+		stream.close()
+	}
+	close(u.myOrderCh)
+	close(u.myAssetCh)
+	close(u.privateErrCh)
 	log.Info("[Upbit] stopped")
 }
 
@@ -580,6 +731,7 @@ func (u *Upbit) runWebsocket() {
 	defer func() {
 		u.wsMtx.Lock()
 		u.wsRunning = false
+		u.wsConn = nil
 		u.wsMtx.Unlock()
 		u.wg.Done()
 	}()
@@ -593,7 +745,6 @@ connect:
 		log.Errorf("Upbit ws dial fail: %v", err)
 		return
 	}
-	u.wsConn = conn
 	log.Info("[UpbitWS] connected")
 
 	conn.SetPongHandler(func(appData string) error {
@@ -630,30 +781,12 @@ connect:
 	// 초기 read deadline 설정
 	conn.SetReadDeadline(time.Now().Add(2 * time.Minute))
 
-	pairsSet := make(map[string]bool)
-	for k := range u.aggregatorMap {
-		// k = "KRW-BTC_1m" => "KRW-BTC"
-		splits := strings.Split(k, "_")
-		if len(splits) < 2 {
-			continue
-		}
-		p := splits[0]
-		pairsSet[p] = true
-	}
-	var codes []string
-	for p := range pairsSet {
-		codes = append(codes, strings.ToUpper(p))
-	}
-
-	subMsg := []interface{}{
-		map[string]string{"ticket": RandomWsUuid},
-		map[string]interface{}{
-			"type":  Candle1s,
-			"codes": codes,
-		},
-		map[string]string{"format": "DEFAULT"},
-	}
-	if e := conn.WriteJSON(subMsg); e != nil {
+	// 연결 중에 구독이 바뀌어도 빠지지 않도록 연결 등록과 구독 요청을 같이 처리
+	u.wsMtx.Lock()
+	u.wsConn = conn
+	e := conn.WriteJSON(u.subscriptionMessage())
+	u.wsMtx.Unlock()
+	if e != nil {
 		u.broadcastErr(e)
 		log.Errorf("[UpbitWS] write sub fail: %v", e)
 		conn.Close()
@@ -687,7 +820,7 @@ connect:
 				}
 			}
 			conn.SetReadDeadline(time.Now().Add(2 * time.Minute))
-			u.handleCandle1s(msg)
+			u.handlePublicMessage(msg)
 		}
 	}
 }
@@ -748,11 +881,36 @@ func (u *Upbit) handleCandle1s(msg []byte) {
 	}
 }
 
+// NewCandleAggregator : 웹소켓 1초봉을 period 봉으로 합치는 집계기
+func NewCandleAggregator(pair, period string) (*CandleAggregator, error) {
+	dur, err := tools.ParseTimeframeToDuration(period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeframe: %s", period)
+	}
+	return &CandleAggregator{
+		pair:     pair,
+		period:   period,
+		duration: dur,
+		buffer:   make(map[time.Time]model.Candle),
+		candleCh: make(chan model.Candle),
+		errCh:    make(chan error),
+	}, nil
+}
+
+// Push : 1초봉 하나를 넣고 진행 중인 봉과, 구간이 마감되었으면 완성된 봉을 반환합니다.
+func (agg *CandleAggregator) Push(c model.Candle) (partial model.Candle, final model.Candle, isFinal bool) {
+	return agg.push1sCandle(c)
+}
+
+// Buffered : 합산하려고 들고 있는 1초봉 수
+func (agg *CandleAggregator) Buffered() int {
+	return len(agg.buffer)
+}
+
 func (agg *CandleAggregator) push1sCandle(c model.Candle) (partial model.Candle, final model.Candle, isFinal bool) {
 	// 1) duration=0 => "1s" timeframe
+	//    합산할 구간이 없으므로 buffer 에 쌓지 않고 1초봉 그대로 완성
 	if agg.duration == 0 {
-		agg.buffer[c.Time] = c
-		// 1초봉 그대로 완성
 		return c, model.Candle{}, true
 	}
 
@@ -782,7 +940,7 @@ func (agg *CandleAggregator) push1sCandle(c model.Candle) (partial model.Candle,
 		final.Complete = true
 		isFinal = true
 
-		// (b) buffer에서 이전 구간 데이터 제거
+		// (b) buffer에서 이전 구간(과 그 전에 늦게 들어온) 데이터 제거
 		agg.removeOldSeconds(finalStart)
 
 		// (c) 다음 정각으로 이동
@@ -841,14 +999,12 @@ func (agg *CandleAggregator) buildPartialCandle(startKey, now time.Time) model.C
 	return out
 }
 
-// removeOldSeconds : remove all second in the old interval
+// removeOldSeconds : remove all seconds before the end of the old interval (late seconds included)
 func (agg *CandleAggregator) removeOldSeconds(minKey time.Time) {
-	start := minKey
 	end := minKey.Add(agg.duration)
 
 	for sec := range agg.buffer {
-
-		if (sec.After(start) || sec.Equal(start)) && sec.Before(end) {
+		if sec.Before(end) {
 			delete(agg.buffer, sec)
 		}
 	}
@@ -941,17 +1097,44 @@ func convertOrderToModelOrder(o model.OrderResponse, pair string) model.Order {
 			createdTime = time.Now()
 		}
 	}
-	return model.Order{
-		ExchangeID: o.UUID,
-		Pair:       pair,
-		Side:       model.SideType(o.Side),
-		Type:       model.OrderType(o.OrdType),
-		Status:     model.OrderStatusType(o.State),
-		Price:      priceF,
-		Quantity:   volF,
-		CreatedAt:  createdTime,
-		UpdatedAt:  time.Now(),
-	}
+	executedF, _ := strconv.ParseFloat(o.ExecutedVolume, 64)
+	paidFeeF, _ := strconv.ParseFloat(o.PaidFee, 64)
+
+	var fills []model.Fill
+	var funds float64
+	for _, t := range o.Trades {
+		price, _ := strconv.ParseFloat(t.Price, 64)
+		volume, _ := strconv.ParseFloat(t.Volume, 64)
+		tradeFunds, _ := strconv.ParseFloat(t.Funds, 64)
+		fills = append(fills, model.Fill{
+			TradeID:  t.UUID,
+			Price:    price,
+			Quantity: volume,
+			Funds:    tradeFunds,
+			Time:     t.CreatedAt,
+		})
+		funds += tradeFunds
+	}
+
+	order := model.Order{
+		ExchangeID:       o.UUID,
+		Identifier:       o.Identifier,
+		Pair:             pair,
+		Side:             model.SideType(o.Side),
+		Type:             model.OrderType(o.OrdType),
+		Status:           model.OrderStatusType(o.State),
+		Price:            priceF,
+		Quantity:         volF,
+		ExecutedQuantity: executedF,
+		PaidFee:          paidFeeF,
+		Fills:            fills,
+		CreatedAt:        createdTime,
+		UpdatedAt:        time.Now(),
+	}
+	if executedF > 0 && funds > 0 {
+		order.AvgPrice = funds / executedF
+	}
+	return order
 }
 
 func convertMultiOrdersToModelOrders(orders []model.OrdersResponse, pair string) []model.Order {
@@ -969,17 +1152,27 @@ func convertMultiOrdersToModelOrders(orders []model.OrdersResponse, pair string)
 				createdTime = time.Now()
 			}
 		}
-		return model.Order{
-			ExchangeID: o.UUID,
-			Pair:       pair,
-			Side:       model.SideType(o.Side),
-			Type:       model.OrderType(o.OrdType),
-			Status:     model.OrderStatusType(o.State),
-			Price:      priceF,
-			Quantity:   volF,
-			CreatedAt:  createdTime,
-			UpdatedAt:  time.Now(),
+		executedF, _ := strconv.ParseFloat(o.ExecutedVolume, 64)
+		fundsF, _ := strconv.ParseFloat(o.ExecutedFunds, 64)
+		paidFeeF, _ := strconv.ParseFloat(o.PaidFee, 64)
+		order := model.Order{
+			ExchangeID:       o.UUID,
+			Identifier:       o.Identifier,
+			Pair:             pair,
+			Side:             model.SideType(o.Side),
+			Type:             model.OrderType(o.OrdType),
+			Status:           model.OrderStatusType(o.State),
+			Price:            priceF,
+			Quantity:         volF,
+			ExecutedQuantity: executedF,
+			PaidFee:          paidFeeF,
+			CreatedAt:        createdTime,
+			UpdatedAt:        time.Now(),
+		}
+		if executedF > 0 {
+			order.AvgPrice = fundsF / executedF
 		}
+		return order
 	})
 	return results
 }
@@ -1008,7 +1201,7 @@ func (u *Upbit) requestUpbitGET(ctx context.Context, path string, params map[str
 		return nil, fmt.Errorf("API 호출 실패: %w", err)
 	}
 	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return nil, fmt.Errorf("API 응답 오류: %d, %s", resp.StatusCode(), resp.String())
+		return nil, &upbitAPIError{StatusCode: resp.StatusCode(), Body: resp.String()}
 	}
 
 	return resp.Body(), nil
@@ -1034,7 +1227,7 @@ func (u *Upbit) requestUpbitPOST(ctx context.Context, path string, params map[st
 		return nil, fmt.Errorf("API 호출 실패: %w", err)
 	}
 	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return nil, fmt.Errorf("API 응답 오류: %d, %s", resp.StatusCode(), resp.String())
+		return nil, &upbitAPIError{StatusCode: resp.StatusCode(), Body: resp.String()}
 	}
 
 	return resp.Body(), nil
@@ -1064,12 +1257,19 @@ func (u *Upbit) requestUpbitDELETE(ctx context.Context, path string, params map[
 		return nil, fmt.Errorf("API 호출 실패: %w", err)
 	}
 	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return nil, fmt.Errorf("API 응답 오류: %d, %s", resp.StatusCode(), resp.String())
+		return nil, &upbitAPIError{StatusCode: resp.StatusCode(), Body: resp.String()}
 	}
 
 	return resp.Body(), nil
 }
 
+// setIdentifier : 클라이언트 주문 식별자. 재시작 후 Order(pair, identifier, true) 로 조회할 때 사용
+func setIdentifier(params map[string]interface{}, identifier string) {
+	if identifier != "" {
+		params["identifier"] = identifier
+	}
+}
+
 // floatToString
 func floatToString(f float64) string {
 	return strconv.FormatFloat(f, 'f', -1, 64)
@@ -1163,8 +1363,11 @@ func validatePrice(price float64, pair string) float64 {
 		unit = 0.00000001
 	}
 
-	// 내림 처리: price - (price % unit)
-	return price - math.Mod(price, unit)
+	// 내림 처리: floor(price / unit) * unit
+	// math.Mod(950, 0.1) 처럼 부동소수점 오차로 한 단위가 더 깎이는 것을 막기 위해 epsilon을 더하고,
+	// 결과도 소수점 8자리로 반올림
+	steps := math.Floor(price/unit + 1e-9)
+	return math.Round(steps*unit*1e8) / 1e8
 }
 
 // isSpecialPair는 주문 가격 단위가 1원으로 적용되어야 하는 원화 마켓 종목인지 확인합니다.
@@ -1198,12 +1401,11 @@ func isSpecialPair(pair string) bool {
 }
 
 func (u *Upbit) getFeeRateForOrder(pair string, side model.SideType, discountEvent bool) (float64, error) {
-	baseFeeRate := 0.00139
 	if side == model.SideTypeBuy {
 		if discountEvent {
-			return 0.0005, nil
+			return upbitDiscountFeeRate, nil
 		}
-		return baseFeeRate, nil
+		return upbitBaseFeeRate, nil
 	}
 	// 매도 주문은 보통 체결 금액에서 수수료가 차감되므로, 주문 전 계산에는 별도 조정이 필요없습니다.
 	return 0, nil
@@ -0,0 +1,191 @@
+package exchange
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+const (
+	Orderbook = "orderbook"
+	Trade     = "trade"
+	Ticker    = "ticker"
+
+	// 공개 시세 구독 채널 크기. 소비자가 느려 가득 차면 새 메시지는 버림 (캔들 수신이 막히지 않도록)
+	marketBufferSize = 100
+)
+
+// publicStream : 공개 웹소켓에서 type 하나(orderbook/trade/ticker)의 메시지를 종목별 구독 채널로 보냄
+type publicStream interface {
+	codes() []string
+	handle(msg []byte)
+	close()
+}
+
+// marketStream : T 타입 메시지의 종목별 구독 채널
+type marketStream[T any] struct {
+	name string
+	code func(msg T) string
+
+	mu      sync.RWMutex
+	subs    map[string][]chan T // key=pair
+	dropped int
+}
+
+func newMarketStream[T any](name string, code func(msg T) string) *marketStream[T] {
+	return &marketStream[T]{name: name, code: code, subs: make(map[string][]chan T)}
+}
+
+func (s *marketStream[T]) subscribe(pair string) chan T {
+	ch := make(chan T, marketBufferSize)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.ToUpper(pair)
+	s.subs[key] = append(s.subs[key], ch)
+	return ch
+}
+
+func (s *marketStream[T]) codes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	codes := make([]string, 0, len(s.subs))
+	for code := range s.subs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func (s *marketStream[T]) handle(msg []byte) {
+	var raw T
+	if e := json.Unmarshal(msg, &raw); e != nil {
+		log.Warnf("[UpbitWS] %s parse fail: %v", s.name, e)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.subs[strings.ToUpper(s.code(raw))] {
+		select {
+		case ch <- raw:
+		default:
+			s.dropped++
+			if s.dropped%marketBufferSize == 1 {
+				log.Warnf("[UpbitWS] %s consumer is slow, dropped %d messages", s.name, s.dropped)
+			}
+		}
+	}
+}
+
+func (s *marketStream[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, chs := range s.subs {
+		for _, ch := range chs {
+			close(ch)
+		}
+	}
+	s.subs = make(map[string][]chan T)
+}
+
+func newPublicStreams() map[string]publicStream {
+	return map[string]publicStream{
+		Orderbook: newMarketStream(Orderbook, func(m model.UpbitOrderbookMessage) string { return m.Code }),
+		Trade:     newMarketStream(Trade, func(m model.UpbitTradeMessage) string { return m.Code }),
+		Ticker:    newMarketStream(Ticker, func(m model.UpbitTickerMessage) string { return m.Code }),
+	}
+}
+
+// OrderbookSubscription : pair 의 실시간 호가 스트림. 캔들과 같은 공개 웹소켓 연결을 공유합니다.
+func (u *Upbit) OrderbookSubscription(pair string) chan model.UpbitOrderbookMessage {
+	ch := u.streams[Orderbook].(*marketStream[model.UpbitOrderbookMessage]).subscribe(pair)
+	u.marketSubscribed()
+	return ch
+}
+
+// TradeSubscription : pair 의 실시간 체결 스트림
+func (u *Upbit) TradeSubscription(pair string) chan model.UpbitTradeMessage {
+	ch := u.streams[Trade].(*marketStream[model.UpbitTradeMessage]).subscribe(pair)
+	u.marketSubscribed()
+	return ch
+}
+
+// TickerSubscription : pair 의 실시간 현재가 스트림
+func (u *Upbit) TickerSubscription(pair string) chan model.UpbitTickerMessage {
+	ch := u.streams[Ticker].(*marketStream[model.UpbitTickerMessage]).subscribe(pair)
+	u.marketSubscribed()
+	return ch
+}
+
+// marketSubscribed : 연결 중이면 바뀐 구독으로 다시 요청하고, 아니면 연결을 엽니다.
+func (u *Upbit) marketSubscribed() {
+	u.wsMtx.Lock()
+	running := u.wsRunning
+	u.wsMtx.Unlock()
+	if !running {
+		go u.wsRunIfNeeded()
+		return
+	}
+	u.resubscribe()
+}
+
+// resubscribe : 업비트는 같은 연결의 새 구독 요청이 이전 요청을 대체하므로 전체 구독을 다시 보냄
+func (u *Upbit) resubscribe() {
+	u.wsMtx.Lock()
+	defer u.wsMtx.Unlock()
+	if u.wsConn == nil {
+		return // 연결되면 전체 구독을 보냄
+	}
+	if e := u.wsConn.WriteJSON(u.subscriptionMessage()); e != nil {
+		log.Warnf("[UpbitWS] resubscribe fail: %v", e)
+	}
+}
+
+// subscriptionMessage : 1초봉과 공개 시세 스트림을 하나의 연결로 받는 구독 요청
+func (u *Upbit) subscriptionMessage() []interface{} {
+	pairsSet := make(map[string]bool)
+	for k := range u.aggregatorMap {
+		// k = "KRW-BTC_1m" => "KRW-BTC"
+		splits := strings.Split(k, "_")
+		if len(splits) < 2 {
+			continue
+		}
+		pairsSet[strings.ToUpper(splits[0])] = true
+	}
+	var codes []string
+	for p := range pairsSet {
+		codes = append(codes, p)
+	}
+	sort.Strings(codes)
+
+	msg := []interface{}{map[string]string{"ticket": RandomWsUuid}}
+	var streams []interface{}
+	for _, name := range []string{Orderbook, Trade, Ticker} {
+		if streamCodes := u.streams[name].codes(); len(streamCodes) > 0 {
+			streams = append(streams, map[string]interface{}{"type": name, "codes": streamCodes})
+		}
+	}
+	if len(codes) > 0 || len(streams) == 0 {
+		msg = append(msg, map[string]interface{}{"type": Candle1s, "codes": codes})
+	}
+	msg = append(msg, streams...)
+	return append(msg, map[string]string{"format": "DEFAULT"})
+}
+
+// handlePublicMessage : 공개 웹소켓 메시지를 type 별로 전달합니다.
+func (u *Upbit) handlePublicMessage(msg []byte) {
+	var base model.WSCandleBase
+	if e := json.Unmarshal(msg, &base); e != nil {
+		log.Warnf("ws base parse fail: %v", e)
+		return
+	}
+	if stream, ok := u.streams[base.Type]; ok && base.Error.Name == "" {
+		stream.handle(msg)
+		return
+	}
+	// candle.1s 와 에러 메시지
+	u.handleCandle1s(msg)
+}
@@ -0,0 +1,229 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"raccoon/model"
+	"raccoon/utils/auth"
+	"raccoon/utils/log"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	MyOrder = "myOrder"
+	MyAsset = "myAsset"
+)
+
+// PrivateSubscription : private 웹소켓(myOrder, myAsset) 스트림 채널을 반환합니다.
+// 최초 호출 시 JWT 인증된 연결을 열고, 이후에는 같은 채널을 공유합니다.
+func (u *Upbit) PrivateSubscription() (chan model.UpbitMyOrderMessage, chan model.UpbitMyAssetMessage, chan error) {
+	go u.privateRunIfNeeded()
+	return u.myOrderCh, u.myAssetCh, u.privateErrCh
+}
+
+func (u *Upbit) privateRunIfNeeded() {
+	u.privateMtx.Lock()
+	defer u.privateMtx.Unlock()
+	if u.privateRunning {
+		return
+	}
+	u.privateRunning = true
+	u.wg.Add(1)
+
+	go u.runPrivateWebsocket()
+}
+
+func (u *Upbit) runPrivateWebsocket() {
+	defer func() {
+		u.privateMtx.Lock()
+		u.privateRunning = false
+		u.privateMtx.Unlock()
+		u.wg.Done()
+	}()
+
+	var retries int
+	for {
+		err := u.readPrivateWebsocket()
+		if u.ctx.Err() != nil {
+			log.Info("[UpbitPrivateWS] context done => close ws")
+			return
+		}
+		log.Errorf("[UpbitPrivateWS] read err: %v", err)
+		if retries >= maxWSRetries {
+			u.broadcastPrivateErr(fmt.Errorf("private ws fail after %d retries: %w", maxWSRetries, err))
+			return
+		}
+		retries++
+		log.Warnf("[UpbitPrivateWS] retrying... attempt=%d", retries)
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// readPrivateWebsocket : 연결 후 read loop를 돌다가 에러가 나면 반환합니다.
+// 인증 토큰은 연결할 때마다 새로 발급합니다 (nonce 재사용 불가).
+func (u *Upbit) readPrivateWebsocket() error {
+	token, err := auth.GenerateJWT(u.apiKey, u.secretKey, nil)
+	if err != nil {
+		return fmt.Errorf("jwt generate fail: %w", err)
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+
+	conn, _, err := websocket.DefaultDialer.Dial(upbitPrivateWS, header)
+	if err != nil {
+		return fmt.Errorf("dial fail: %w", err)
+	}
+	defer conn.Close()
+
+	u.privateMtx.Lock()
+	u.privateConn = conn
+	u.privateMtx.Unlock()
+	log.Info("[UpbitPrivateWS] connected")
+
+	conn.SetPongHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Minute))
+		return nil
+	})
+
+	keepaliveCtx, keepaliveCancel := context.WithCancel(u.ctx)
+	defer keepaliveCancel()
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-keepaliveCtx.Done():
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(
+					websocket.PingMessage,
+					[]byte("ping"),
+					time.Now().Add(5*time.Second),
+				); err != nil {
+					log.Warnf("[UpbitPrivateWS] ping error: %v", err)
+					return
+				}
+			}
+		}
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Minute))
+
+	subMsg := []interface{}{
+		map[string]string{"ticket": RandomWsUuid},
+		map[string]string{"type": MyOrder},
+		map[string]string{"type": MyAsset},
+		map[string]string{"format": "DEFAULT"},
+	}
+	if e := conn.WriteJSON(subMsg); e != nil {
+		return fmt.Errorf("write sub fail: %w", e)
+	}
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * time.Minute))
+		u.handlePrivateMessage(msg)
+	}
+}
+
+func (u *Upbit) handlePrivateMessage(msg []byte) {
+	var base model.WSCandleBase
+	if e := json.Unmarshal(msg, &base); e != nil {
+		log.Warnf("[UpbitPrivateWS] base parse fail: %v", e)
+		return
+	}
+	if base.Error.Name != "" {
+		errMsg := fmt.Errorf("[UpbitPrivateWS] error: %s - %s", base.Error.Name, base.Error.Message)
+		log.Errorf(errMsg.Error())
+		u.broadcastPrivateErr(errMsg)
+		return
+	}
+
+	switch base.Type {
+	case MyOrder:
+		var raw model.UpbitMyOrderMessage
+		if e := json.Unmarshal(msg, &raw); e != nil {
+			log.Warnf("[UpbitPrivateWS] myOrder parse fail: %v", e)
+			return
+		}
+		select {
+		case u.myOrderCh <- raw:
+		case <-u.ctx.Done():
+		}
+	case MyAsset:
+		var raw model.UpbitMyAssetMessage
+		if e := json.Unmarshal(msg, &raw); e != nil {
+			log.Warnf("[UpbitPrivateWS] myAsset parse fail: %v", e)
+			return
+		}
+		select {
+		case u.myAssetCh <- raw:
+		case <-u.ctx.Done():
+		}
+	}
+}
+
+func (u *Upbit) broadcastPrivateErr(err error) {
+	select {
+	case u.privateErrCh <- err:
+	default:
+	}
+}
+
+// MyOrderToOrder : myOrder 메시지를 model.Order로 변환합니다.
+// state=trade 인 경우 price/volume은 해당 체결의 가격/수량이고,
+// 그 외에는 평균 체결가와 누적 체결량을 사용합니다.
+func MyOrderToOrder(m model.UpbitMyOrderMessage) model.Order {
+	price, quantity := m.Price, m.Volume
+	if model.OrderStatusType(m.State) != model.OrderStatusTypeTrade {
+		if m.AvgPrice > 0 {
+			price = m.AvgPrice
+		}
+		if m.ExecutedVolume > 0 {
+			quantity = m.ExecutedVolume
+		}
+	}
+
+	updatedAt := time.UnixMilli(m.Timestamp).In(KSTLocation)
+	if m.TradeTimestamp > 0 {
+		updatedAt = time.UnixMilli(m.TradeTimestamp).In(KSTLocation)
+	}
+
+	order := model.Order{
+		ExchangeID:       m.UUID,
+		Identifier:       m.Identifier,
+		Pair:             m.Code,
+		Side:             model.SideType(strings.ToLower(m.AskBid)),
+		Type:             model.OrderType(m.OrderType),
+		Status:           model.OrderStatusType(m.State),
+		Price:            price,
+		Quantity:         quantity,
+		ExecutedQuantity: m.ExecutedVolume,
+		AvgPrice:         m.AvgPrice,
+		PaidFee:          m.PaidFee,
+		CreatedAt:        time.UnixMilli(m.OrderTimestamp).In(KSTLocation),
+		UpdatedAt:        updatedAt,
+	}
+	if order.Status == model.OrderStatusTypeTrade && m.TradeUUID != "" {
+		// trade 메시지는 이번 체결 한 건 (price/volume 이 체결가/체결량)
+		order.Fills = []model.Fill{{
+			TradeID:  m.TradeUUID,
+			Price:    m.Price,
+			Quantity: m.Volume,
+			Funds:    m.Price * m.Volume,
+			Fee:      m.TradeFee,
+			Maker:    m.IsMaker != nil && *m.IsMaker,
+			Time:     updatedAt,
+		}}
+	}
+	return order
+}
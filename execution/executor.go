@@ -0,0 +1,522 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+	"raccoon/utils/tools"
+)
+
+const (
+	// DefaultInterval : 자식 주문 상태 확인/다음 조각 주문 주기
+	DefaultInterval = time.Second
+	// DefaultFeeRate : 지정가 매수 수량 계산에 쓰는 수수료율 (업비트 KRW 마켓)
+	DefaultFeeRate = 0.0005
+
+	// 남은 금액/수량이 이보다 작으면 다 실행한 것으로 봄
+	epsilon = 1e-9
+)
+
+var ErrInvalidExecution = errors.New("execution: invalid parameters")
+
+// Quoter : 지정가를 정할 시세. BestQuote 도 제공하면(Upbit) 최우선 호가를, 아니면 현재가를 사용합니다.
+type Quoter interface {
+	LastQuote(pair string) (float64, error)
+}
+
+// bookQuoter : 최우선 매수/매도 호가를 제공하는 거래소
+type bookQuoter interface {
+	BestQuote(pair string) (bid, ask float64, err error)
+}
+
+// Route : 부모 주문의 자식 주문을 내고 결과를 받는 곳 (consumer.OrderFeedConsumerBroker)
+type Route struct {
+	Broker interfaces.Broker                            // 자식 주문 상태 조회/취소
+	Place  func(child model.Order) (model.Order, error) // 자식 주문 제출 (리스크 검사/저널 포함). 실패는 Place 가 알림
+	Done   func(child model.Order, err error)           // 제출된 자식 주문이 끝났을 때. Price/Quantity 는 평균 체결가/체결 수량
+}
+
+// Progress : 실행 중인 부모 주문 현황
+type Progress struct {
+	Parent    model.Order
+	Started   time.Time
+	Children  int     // 낸 자식 주문 수
+	Executed  float64 // 체결 수량
+	AvgPrice  float64 // 평균 체결가
+	Remaining float64 // 남은 금액(매수, KRW) 또는 수량(매도)
+}
+
+// Executor : TWAP/iceberg/chase 로 부모 주문을 자식 주문으로 나눠 실행합니다.
+//   - Submit 은 등록만 하고, Step(Start 의 주기 또는 백테스트 엔진)이 시계 기준으로 자식 주문을 내고 결과를 확인
+//   - 자식 주문은 실제 거래소 주문이므로 Route.Done 으로 하나씩 예산/손익/알림에 반영됨
+//   - 여러 전략이 Route 만 달리해서 하나의 Executor 를 같이 씀
+type Executor struct {
+	FeeRate float64
+
+	quotes Quoter
+	clock  tools.Clock
+
+	mu   sync.Mutex
+	jobs []*job
+
+	step   sync.Mutex // Step 직렬화 (주기 실행과 백테스트/테스트 호출)
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// job : 실행 중인 부모 주문 하나
+type job struct {
+	parent    model.Order
+	params    model.Execution
+	route     Route
+	started   time.Time
+	remaining float64 // 매수: KRW, 매도: 수량
+	executed  float64
+	funds     float64
+	children  int
+	child     *model.Order // 체결을 기다리는 자식 주문
+	placedAt  time.Time
+	nextAt    time.Time // twap: 다음 조각 시각
+	canceling bool      // 자식 주문 취소 요청 후 종료를 기다리는 중
+	fallback  bool      // chase: 최유리 주문으로 전환
+	stopping  bool      // 체결 대기 중인 자식 주문이 끝나면 종료
+	done      bool
+}
+
+func NewExecutor(quotes Quoter) *Executor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Executor{
+		FeeRate: DefaultFeeRate,
+		quotes:  quotes,
+		clock:   tools.RealClock{},
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// SetClock : 조각 간격/제한 시간을 계산할 시계 (백테스트는 시뮬레이션 시계)
+func (e *Executor) SetClock(clock tools.Clock) {
+	e.clock = clock
+}
+
+// Validate : 부모 주문의 실행 방식이 올바른지
+func Validate(params model.Execution) error {
+	switch params.Algo {
+	case model.ExecutionTWAP:
+		if params.Slices < 1 || params.Duration <= 0 {
+			return fmt.Errorf("%w: twap needs slices >= 1 and duration > 0 (got %d, %v)", ErrInvalidExecution, params.Slices, params.Duration)
+		}
+	case model.ExecutionIceberg:
+		if params.Slices < 1 || params.Limit < 0 || params.Timeout < 0 {
+			return fmt.Errorf("%w: iceberg needs slices >= 1 (got %d), limit >= 0 and timeout >= 0", ErrInvalidExecution, params.Slices)
+		}
+	case model.ExecutionChase:
+		if params.Interval <= 0 || params.Timeout < 0 {
+			return fmt.Errorf("%w: chase needs interval > 0 (got %v) and timeout >= 0", ErrInvalidExecution, params.Interval)
+		}
+	default:
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidExecution, params.Algo)
+	}
+	return nil
+}
+
+// Submit : parent 를 parent.Execution 방식으로 실행하도록 등록합니다. 자식 주문은 다음 Step 부터 나갑니다.
+// 매수 parent 는 Price 가 KRW 금액(시장가 매수), 매도 parent 는 Quantity 가 수량(시장가 매도)이어야 합니다.
+func (e *Executor) Submit(parent model.Order, route Route) error {
+	if parent.Execution == nil {
+		return fmt.Errorf("%w: order has no execution", ErrInvalidExecution)
+	}
+	params := *parent.Execution
+	if err := Validate(params); err != nil {
+		return err
+	}
+	j := &job{parent: parent, params: params, route: route, started: e.clock.Now()}
+	switch {
+	case parent.Side == model.SideTypeBuy && parent.Type == model.OrderTypePrice && parent.Price > 0:
+		j.remaining = parent.Price
+	case parent.Side == model.SideTypeSell && parent.Type == model.OrderTypeMarket && parent.Quantity > 0:
+		j.remaining = parent.Quantity
+	default:
+		return fmt.Errorf("%w: %s %s order cannot be split", ErrInvalidExecution, parent.Side, parent.Type)
+	}
+	j.parent.Pair = strings.ToUpper(parent.Pair)
+	j.nextAt = j.started
+
+	e.mu.Lock()
+	e.jobs = append(e.jobs, j)
+	e.mu.Unlock()
+	log.Infof("[Execution] %s %s %s %.8f accepted - Strategy: %s", params.Algo, j.parent.Pair, parent.Side, j.remaining, parent.Strategy)
+	return nil
+}
+
+// Start : interval 마다 Step 합니다. interval 이 0 이하면 DefaultInterval
+func (e *Executor) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-ticker.C:
+				e.Step()
+			}
+		}
+	}()
+}
+
+// Stop : 주기 실행을 멈추고, 거래소에 남지 않도록 실행 중인 지정가 자식 주문을 취소합니다.
+func (e *Executor) Stop() {
+	e.cancel()
+	e.Cancel("")
+}
+
+// Cancel : pair 의 부모 주문 실행을 중단하고 체결 대기 중인 자식 주문을 취소합니다. pair 가 비어있으면 전체.
+// 중단한 부모 주문 수를 반환합니다.
+func (e *Executor) Cancel(pair string) int {
+	e.step.Lock()
+	defer e.step.Unlock()
+
+	canceled := 0
+	for _, j := range e.active() {
+		if pair != "" && j.parent.Pair != strings.ToUpper(pair) {
+			continue
+		}
+		canceled++
+		j.stopping = true
+		if j.child != nil && !j.canceling {
+			if err := j.route.Broker.Cancel(*j.child, false); err != nil {
+				log.Warnf("[Execution] cancel %s %s: %v", j.parent.Pair, j.child.ExchangeID, err)
+			}
+			j.canceling = true
+			e.settle(j)
+		}
+		// 취소한 자식 주문이 아직 끝나지 않았으면 결과를 받을 때까지 Step 에서 확인
+		if j.child == nil {
+			e.finish(j, "canceled")
+		}
+	}
+	e.prune()
+	return canceled
+}
+
+// Active : 실행 중인 부모 주문 (등록 순서)
+func (e *Executor) Active() []Progress {
+	e.step.Lock()
+	defer e.step.Unlock()
+	jobs := e.active()
+	progress := make([]Progress, 0, len(jobs))
+	for _, j := range jobs {
+		p := Progress{Parent: j.parent, Started: j.started, Children: j.children, Executed: j.executed, Remaining: j.remaining}
+		if j.executed > 0 {
+			p.AvgPrice = j.funds / j.executed
+		}
+		progress = append(progress, p)
+	}
+	return progress
+}
+
+// Step : 실행 중인 부모 주문마다 자식 주문 결과를 확인하고, 때가 되면 다음 자식 주문을 냅니다.
+func (e *Executor) Step() {
+	e.step.Lock()
+	defer e.step.Unlock()
+
+	now := e.clock.Now()
+	for _, j := range e.active() {
+		e.advance(j, now)
+		// 방금 낸 자식 주문이 바로 끝나 더 할 일이 없으면 다음 Step 을 기다리지 않고 종료
+		if !j.done && j.child == nil {
+			if j.remaining <= e.dust(j) {
+				e.finish(j, "done")
+			} else if j.stopping {
+				e.finish(j, "stopped")
+			}
+		}
+	}
+	e.prune()
+}
+
+func (e *Executor) active() []*job {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	jobs := make([]*job, 0, len(e.jobs))
+	for _, j := range e.jobs {
+		if !j.done {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// prune : 끝난 부모 주문을 지움
+func (e *Executor) prune() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	jobs := e.jobs[:0]
+	for _, j := range e.jobs {
+		if !j.done {
+			jobs = append(jobs, j)
+		}
+	}
+	e.jobs = jobs
+}
+
+// advance : 부모 주문 하나를 한 단계 진행 (e.step 을 잡은 상태에서 호출)
+func (e *Executor) advance(j *job, now time.Time) {
+	if j.child != nil && !e.settle(j) {
+		e.manage(j, now)
+		if j.child != nil {
+			return
+		}
+	}
+	if j.remaining <= e.dust(j) {
+		e.finish(j, "done")
+		return
+	}
+	if j.stopping {
+		e.finish(j, "stopped")
+		return
+	}
+
+	switch j.params.Algo {
+	case model.ExecutionTWAP:
+		if j.children >= j.params.Slices {
+			e.finish(j, "all slices sent")
+			return
+		}
+		if now.Before(j.nextAt) {
+			return
+		}
+		amount := j.remaining / float64(j.params.Slices-j.children)
+		child := j.market(amount)
+		j.children++
+		j.nextAt = j.started.Add(j.params.Duration * time.Duration(j.children) / time.Duration(j.params.Slices))
+		// 실패한 조각은 남은 조각에 나눠 다시 시도
+		e.place(j, child, now)
+
+	case model.ExecutionIceberg:
+		if j.children >= j.params.Slices || e.expired(j, now) {
+			e.finish(j, "all slices sent")
+			return
+		}
+		price := j.params.Limit
+		if price <= 0 {
+			price = e.best(j.parent)
+		}
+		amount := j.remaining / float64(j.params.Slices-j.children)
+		j.children++
+		if !e.place(j, e.limit(j, amount, price), now) {
+			e.finish(j, "slice rejected")
+		}
+
+	case model.ExecutionChase:
+		if j.fallback || e.expired(j, now) {
+			// 남은 금액/수량은 최유리 IOC 로 마무리
+			j.fallback = true
+			j.stopping = true
+			j.children++
+			child := j.market(j.remaining)
+			child.Type = model.OrderTypeBest
+			if j.parent.Side == model.SideTypeBuy {
+				// 최유리 매수 금액에는 수수료가 포함되지 않으므로 수수료를 뺀 금액으로 주문
+				child.Price = math.Floor(j.remaining / (1 + e.FeeRate))
+			}
+			e.place(j, child, now)
+			return
+		}
+		j.children++
+		if !e.place(j, e.limit(j, j.remaining, e.best(j.parent)), now) {
+			e.finish(j, "limit rejected")
+		}
+	}
+}
+
+// manage : 체결을 기다리는 자식 주문 관리 (chase 재호가, 제한 시간)
+func (e *Executor) manage(j *job, now time.Time) {
+	child := j.child
+	switch {
+	case child.Type != model.OrderTypeLimit || j.canceling:
+		return // 시장가/최유리는 곧 끝남
+	case e.expired(j, now):
+		// iceberg 는 중단, chase 는 취소 후 최유리 주문
+		if j.params.Algo == model.ExecutionChase {
+			j.fallback = true
+		} else {
+			j.stopping = true
+		}
+	case j.params.Algo == model.ExecutionChase && now.Sub(j.placedAt) >= j.params.Interval:
+		if best := e.best(j.parent); best <= 0 || best == child.Price {
+			return
+		}
+	default:
+		return
+	}
+	if err := j.route.Broker.Cancel(*child, false); err != nil {
+		// 이미 체결됐을 수 있으므로 다음 Step 에서 상태를 다시 확인
+		log.Warnf("[Execution] cancel %s %s: %v", j.parent.Pair, child.ExchangeID, err)
+		return
+	}
+	j.canceling = true
+	e.settle(j)
+}
+
+// place : 자식 주문을 내고 체결 대기로 둡니다. 제출하지 못하면 false
+func (e *Executor) place(j *job, child model.Order, now time.Time) bool {
+	placed, err := j.route.Place(child)
+	if err != nil {
+		log.Warnf("[Execution] %s %s child %d failed: %v", j.params.Algo, j.parent.Pair, j.children, err)
+		return false
+	}
+	if placed.Pair == "" {
+		placed.Pair = j.parent.Pair
+	}
+	if placed.Side == "" {
+		placed.Side = child.Side
+	}
+	if placed.Type == "" {
+		placed.Type = child.Type
+	}
+	j.child = &placed
+	j.placedAt = now
+	e.settle(j) // 시장가/백테스트는 바로 끝났을 수 있음
+	return true
+}
+
+// settle : 체결 대기 중인 자식 주문 상태를 확인하고, 끝났으면 반영합니다. 끝났으면 true
+func (e *Executor) settle(j *job) bool {
+	child := *j.child
+	if !child.Closed() {
+		current, err := j.route.Broker.Order(j.parent.Pair, child.ExchangeID, false)
+		if err != nil {
+			log.Warnf("[Execution] %s %s: %v", j.parent.Pair, child.ExchangeID, err)
+			return false
+		}
+		if !current.Closed() {
+			j.child.Price = current.Price
+			return false
+		}
+		current.Strategy, current.FeedID = child.Strategy, child.FeedID
+		if current.Pair == "" {
+			current.Pair = j.parent.Pair
+		}
+		child = current
+	}
+	j.child = nil
+	j.canceling = false
+
+	quantity, price := child.ExecutedQuantity, child.AvgPrice
+	if quantity <= 0 && child.Status == model.OrderStatusTypeDone {
+		quantity, price = child.Quantity, child.Price
+	}
+	if quantity <= 0 {
+		j.route.Done(child, fmt.Errorf("order canceled without execution: %s", child.ExchangeID))
+		return true
+	}
+
+	j.executed += quantity
+	j.funds += quantity * price
+	if j.parent.Side == model.SideTypeBuy {
+		fee := child.PaidFee
+		if fee <= 0 {
+			fee = quantity * price * e.FeeRate
+		}
+		j.remaining -= quantity*price + fee
+	} else {
+		j.remaining -= quantity
+	}
+	j.remaining = math.Max(j.remaining, 0)
+
+	// 이후 예산/손익 반영은 평균 체결가, 체결 수량 기준 (MyOrderToOrder 와 같게)
+	child.Price, child.Quantity = price, quantity
+	j.route.Done(child, nil)
+	return true
+}
+
+func (e *Executor) finish(j *job, reason string) {
+	e.mu.Lock()
+	j.done = true
+	e.mu.Unlock()
+	avg := 0.0
+	if j.executed > 0 {
+		avg = j.funds / j.executed
+	}
+	log.Infof("[Execution] %s %s %s %s - Strategy: %s, children: %d, executed: %.8f @ %.4f, remaining: %.8f",
+		j.params.Algo, j.parent.Pair, j.parent.Side, reason, j.parent.Strategy, j.children, j.executed, avg, j.remaining)
+}
+
+func (e *Executor) expired(j *job, now time.Time) bool {
+	return j.params.Timeout > 0 && !now.Before(j.started.Add(j.params.Timeout))
+}
+
+// dust : 남아도 더 낼 수 없는 금액/수량 (매수는 1 KRW 미만)
+func (e *Executor) dust(j *job) float64 {
+	if j.parent.Side == model.SideTypeBuy {
+		return 1
+	}
+	return epsilon
+}
+
+// best : 매수는 최우선 매수 호가, 매도는 최우선 매도 호가. 호가를 모르면 현재가
+func (e *Executor) best(parent model.Order) float64 {
+	if book, ok := e.quotes.(bookQuoter); ok {
+		bid, ask, err := book.BestQuote(parent.Pair)
+		if err == nil && bid > 0 && ask > 0 {
+			if parent.Side == model.SideTypeBuy {
+				return bid
+			}
+			return ask
+		}
+		log.Warnf("[Execution] best quote %s: %v, using last price", parent.Pair, err)
+	}
+	price, err := e.quotes.LastQuote(parent.Pair)
+	if err != nil {
+		log.Warnf("[Execution] last quote %s: %v", parent.Pair, err)
+		return 0
+	}
+	return price
+}
+
+// market : amount(매수 KRW, 매도 수량)만큼의 시장가 자식 주문
+func (j *job) market(amount float64) model.Order {
+	child := j.childOrder()
+	if j.parent.Side == model.SideTypeBuy {
+		child.Type = model.OrderTypePrice
+		child.Price = amount
+	} else {
+		child.Type = model.OrderTypeMarket
+		child.Quantity = amount
+	}
+	return child
+}
+
+// limit : amount(매수 KRW, 매도 수량)만큼의 price 지정가 자식 주문
+func (e *Executor) limit(j *job, amount, price float64) model.Order {
+	child := j.childOrder()
+	child.Type = model.OrderTypeLimit
+	child.Price = price
+	child.Quantity = amount
+	if j.parent.Side == model.SideTypeBuy && price > 0 {
+		child.Quantity = amount / price / (1 + e.FeeRate)
+	}
+	return child
+}
+
+func (j *job) childOrder() model.Order {
+	return model.Order{
+		Pair:     j.parent.Pair,
+		Side:     j.parent.Side,
+		Strategy: j.parent.Strategy,
+		FeedID:   j.parent.FeedID,
+	}
+}
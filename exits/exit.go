@@ -0,0 +1,114 @@
+package exits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Kind : 보호 청산 종류
+type Kind string
+
+const (
+	KindStopLoss     Kind = "stop_loss"     // 가격이 Trigger 이하로 내려가면 매도
+	KindTakeProfit   Kind = "take_profit"   // 가격이 Trigger 이상으로 올라가면 매도
+	KindTrailingStop Kind = "trailing_stop" // 최고가가 오른 만큼 Trigger 도 올리고, 이하로 내려가면 매도
+)
+
+// Exit : 포지션 하나에 붙은 보호 청산 주문 한 건 (거래소에 걸지 않고 클라이언트에서 가격을 보고 발동).
+// 같은 Group 의 청산은 OCO 입니다. 하나가 발동하면 나머지는 취소됩니다.
+type Exit struct {
+	ID        string
+	Group     string
+	Strategy  string
+	Pair      string
+	Kind      Kind
+	Quantity  float64 // 발동 시 시장가 매도할 수량 (포지션 수량)
+	Entry     float64 // 진입가 (평균 체결가)
+	Trigger   float64 // 발동 가격
+	High      float64 // 트레일링: 붙인 뒤 최고가
+	Triggered bool    // 매도 주문을 냈고 결과를 기다리는 중
+	CreatedAt time.Time
+}
+
+// Triggers : 봉(low~high) 안에서 발동하는지. 트레일링은 먼저 기존 Trigger 로 확인한 뒤 high 로 끌어올림
+func (e *Exit) Triggers(low, high float64) bool {
+	switch e.Kind {
+	case KindStopLoss:
+		return low <= e.Trigger
+	case KindTakeProfit:
+		return high >= e.Trigger
+	case KindTrailingStop:
+		if low <= e.Trigger {
+			return true
+		}
+		if high > e.High {
+			e.Trigger += high - e.High
+			e.High = high
+		}
+	}
+	return false
+}
+
+// Policy : 전략의 포지션에 붙일 보호 청산. 비율은 진입가 기준이고 0 이면 붙이지 않습니다.
+type Policy struct {
+	StopLoss     float64 // 손절: 진입가 × (1 - StopLoss)
+	TakeProfit   float64 // 익절: 진입가 × (1 + TakeProfit)
+	TrailingStop float64 // 트레일링 폭: 진입가 × TrailingStop 만큼 최고가 아래에서 따라감
+}
+
+func (p Policy) Empty() bool {
+	return p.StopLoss <= 0 && p.TakeProfit <= 0 && p.TrailingStop <= 0
+}
+
+func (p Policy) Validate() error {
+	var errs []error
+	check := func(name string, v float64) {
+		if v < 0 || v >= 1 {
+			errs = append(errs, fmt.Errorf("%s must be between 0 and 1 (got %v)", name, v))
+		}
+	}
+	check("stop_loss", p.StopLoss)
+	check("trailing_stop", p.TrailingStop)
+	if p.TakeProfit < 0 {
+		errs = append(errs, fmt.Errorf("take_profit must not be negative (got %v)", p.TakeProfit))
+	}
+	return errors.Join(errs...)
+}
+
+// legs : entry 에 quantity 만큼 진입한 포지션에 붙일 청산들 (하나의 OCO 그룹)
+func (p Policy) legs(group, strategy, pair string, entry, quantity float64, now time.Time) []*Exit {
+	var legs []*Exit
+	add := func(kind Kind, trigger float64) {
+		legs = append(legs, &Exit{
+			ID:        group + ":" + string(kind),
+			Group:     group,
+			Strategy:  strategy,
+			Pair:      pair,
+			Kind:      kind,
+			Quantity:  quantity,
+			Entry:     entry,
+			Trigger:   trigger,
+			High:      entry,
+			CreatedAt: now,
+		})
+	}
+	if p.StopLoss > 0 {
+		add(KindStopLoss, entry*(1-p.StopLoss))
+	}
+	if p.TakeProfit > 0 {
+		add(KindTakeProfit, entry*(1+p.TakeProfit))
+	}
+	if p.TrailingStop > 0 {
+		add(KindTrailingStop, entry*(1-p.TrailingStop))
+	}
+	return legs
+}
+
+// Store : 재시작해도 보호 청산이 남도록 저장하는 곳 (storage.Storage)
+type Store interface {
+	// SaveExitGroup : group 의 청산을 exits 로 통째로 바꿉니다. exits 가 비어있으면 group 을 지웁니다.
+	SaveExitGroup(ctx context.Context, group string, exits []Exit) error
+	Exits(ctx context.Context) ([]Exit, error)
+}
@@ -0,0 +1,322 @@
+package exits
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"raccoon/model"
+	"raccoon/risk"
+	"raccoon/utils/log"
+	"raccoon/utils/tools"
+)
+
+const (
+	// RetryInterval : 청산 매도가 거절되면 이 시간 뒤에 다시 발동할 수 있음 (매초 같은 거절이 반복되지 않도록)
+	RetryInterval = 30 * time.Second
+	// MaxRetries : 청산 매도가 이만큼 실패하면 (잔고 부족 등) 포지션이 없는 것으로 보고 청산을 지움
+	MaxRetries = 3
+
+	// 이보다 적게 남은 수량은 포지션이 닫힌 것으로 봄 (수수료/반올림 잔량)
+	dustQuantity = 1e-8
+)
+
+// position : 전략/종목 하나의 보호 청산 그룹
+type position struct {
+	group   string
+	legs    []*Exit
+	retries int
+	retryAt time.Time
+}
+
+func (p *position) triggered() bool {
+	return len(p.legs) > 0 && p.legs[0].Triggered
+}
+
+func (p *position) quantity() float64 {
+	if len(p.legs) == 0 {
+		return 0
+	}
+	return p.legs[0].Quantity
+}
+
+// Manager : 전략 밖에서 포지션의 손절/익절/트레일링 청산을 관리합니다.
+//   - 전략의 매수가 체결되면 그 전략의 Policy 대로 청산 그룹(OCO)을 붙이고, 매도 체결만큼 수량을 줄임
+//   - OnCandle(1초봉 등)로 가격을 보다가 발동하면 전략 이름으로 시장가 매도를 발행하고 나머지 청산은 취소
+//   - Store 가 있으면 바뀔 때마다 저장하므로 재시작해도 청산이 유지됨
+type Manager struct {
+	store   Store
+	publish func(order model.Order)
+	clock   tools.Clock
+
+	mu        sync.Mutex
+	policies  map[string]Policy    // key=strategy
+	positions map[string]*position // key=strategy|pair
+	seq       int64
+}
+
+// NewManager : store 에 저장된 청산을 불러옵니다. store 가 nil 이면 메모리에만 보관합니다.
+// publish 는 발동한 청산의 매도 주문을 주문 피드에 발행하는 함수입니다.
+func NewManager(store Store, publish func(order model.Order)) (*Manager, error) {
+	m := &Manager{
+		store:     store,
+		publish:   publish,
+		clock:     tools.RealClock{},
+		policies:  make(map[string]Policy),
+		positions: make(map[string]*position),
+	}
+	if store == nil {
+		return m, nil
+	}
+	saved, err := store.Exits(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for i := range saved {
+		e := saved[i]
+		// 발동 후 결과를 받기 전에 종료됐으면 매도가 됐는지 알 수 없으므로 다시 걸어둠 (안 팔렸으면 보호, 팔렸으면 거절 후 정리)
+		e.Triggered = false
+		key := positionKey(e.Strategy, e.Pair)
+		p, ok := m.positions[key]
+		if !ok {
+			p = &position{group: e.Group}
+			m.positions[key] = p
+		}
+		p.legs = append(p.legs, &e)
+	}
+	if len(saved) > 0 {
+		log.Infof("[Exits] restored %d protective exits for %d positions", len(saved), len(m.positions))
+	}
+	return m, nil
+}
+
+// SetClock : 재시도 간격 계산에 쓸 시계 (백테스트/테스트용)
+func (m *Manager) SetClock(clock tools.Clock) {
+	m.clock = clock
+}
+
+// SetPolicy : strategy 의 매수 체결에 붙일 청산. 빈 Policy 면 새 청산을 붙이지 않습니다.
+func (m *Manager) SetPolicy(strategy string, policy Policy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[strategy] = policy
+}
+
+// Exits : pair 에 걸린 청산 (전략, 종류 순). pair 가 비어있으면 전체
+func (m *Manager) Exits(pair string) []Exit {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Exit
+	for _, p := range m.positions {
+		for _, e := range p.legs {
+			if pair == "" || e.Pair == strings.ToUpper(pair) {
+				out = append(out, *e)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Strategy != out[j].Strategy {
+			return out[i].Strategy < out[j].Strategy
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out
+}
+
+// Cancel : strategy 의 pair 포지션에 걸린 청산을 모두 취소합니다.
+func (m *Manager) Cancel(strategy, pair string) {
+	m.mu.Lock()
+	key := positionKey(strategy, pair)
+	p, ok := m.positions[key]
+	delete(m.positions, key)
+	m.mu.Unlock()
+	if ok {
+		m.save(p.group, nil)
+	}
+}
+
+// OnCandle : 봉의 저가/고가로 청산 발동을 확인합니다. 진행 중인 봉도 그대로 넘기면 됩니다.
+func (m *Manager) OnCandle(candle model.Candle) {
+	pair := strings.ToUpper(candle.Pair)
+	now := m.clock.Now()
+
+	var orders []model.Order
+	var changed []*position
+	m.mu.Lock()
+	for _, p := range m.positions {
+		if len(p.legs) == 0 || p.legs[0].Pair != pair || p.triggered() || now.Before(p.retryAt) {
+			continue
+		}
+		var fired *Exit
+		moved := false
+		for _, e := range p.legs {
+			trigger := e.Trigger
+			if e.Triggers(candle.Low, candle.High) {
+				fired = e
+				break
+			}
+			moved = moved || e.Trigger != trigger
+		}
+		if fired == nil {
+			if moved {
+				changed = append(changed, p)
+			}
+			continue
+		}
+
+		// OCO: 같은 그룹은 모두 발동 처리 (결과를 받을 때까지 다시 발동하지 않음)
+		for _, e := range p.legs {
+			e.Triggered = true
+		}
+		changed = append(changed, p)
+		orders = append(orders, model.Order{
+			Pair:      fired.Pair,
+			Side:      model.SideTypeSell,
+			Type:      model.OrderTypeMarket,
+			Quantity:  fired.Quantity,
+			Strategy:  fired.Strategy,
+			CreatedAt: now,
+		})
+		log.Warnf("[Exits] %s triggered - Strategy: %s, Pair: %s, Trigger: %.4f, Low: %.4f, High: %.4f, Quantity: %.8f",
+			fired.Kind, fired.Strategy, fired.Pair, fired.Trigger, candle.Low, candle.High, fired.Quantity)
+	}
+	snapshots := snapshot(changed)
+	m.mu.Unlock()
+
+	for group, legs := range snapshots {
+		m.save(group, legs)
+	}
+	// 동기 주문 피드(백테스트)는 발행 중에 OnOrderExecuted 가 불리므로 잠금 밖에서 발행
+	for _, order := range orders {
+		m.publish(order)
+	}
+}
+
+// OnOrderExecuted : 주문 결과(consumer.OrderExecutedCallback)로 청산을 붙이거나 줄입니다.
+func (m *Manager) OnOrderExecuted(order model.Order, err error) {
+	if order.Strategy == "" {
+		return
+	}
+	key := positionKey(order.Strategy, order.Pair)
+	quantity, price := filled(order)
+	if err == nil && (quantity <= 0 || price <= 0) {
+		log.Warnf("[Exits] skip order without execution - Strategy: %s, Pair: %s, Side: %s", order.Strategy, order.Pair, order.Side)
+		return
+	}
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	p := m.positions[key]
+	var replaced string // 추가 매수로 새 그룹으로 바뀐 이전 그룹
+	switch {
+	case order.Side == model.SideTypeBuy && err == nil:
+		policy := m.policies[order.Strategy]
+		if policy.Empty() {
+			m.mu.Unlock()
+			return
+		}
+		// 추가 매수는 평균 진입가로 그룹을 새로 붙임
+		if p != nil {
+			held := p.quantity()
+			price = (p.legs[0].Entry*held + price*quantity) / (held + quantity)
+			quantity += held
+			replaced = p.group
+		}
+		m.seq++
+		p = &position{group: order.Strategy + ":" + strings.ToUpper(order.Pair) + ":" +
+			strconv.FormatInt(now.UnixMilli(), 36) + strconv.FormatInt(m.seq, 36)}
+		p.legs = policy.legs(p.group, order.Strategy, strings.ToUpper(order.Pair), price, quantity, now)
+		m.positions[key] = p
+		log.Infof("[Exits] protecting %s %s: %.8f @ %.4f (%d exits)", order.Strategy, order.Pair, quantity, price, len(p.legs))
+
+	case order.Side == model.SideTypeSell && p != nil && err != nil:
+		if !p.triggered() {
+			m.mu.Unlock()
+			return
+		}
+		// 청산 매도가 거절됨: 잠시 뒤 다시 발동하도록 되돌림.
+		// 리스크 검사(킬 스위치 등) 거절은 포지션이 남아있으므로 횟수에 세지 않음
+		if !errors.Is(err, risk.ErrRejected) {
+			p.retries++
+		}
+		if p.retries >= MaxRetries {
+			log.Errorf("[Exits] exit sell for %s %s failed %d times, dropping exits: %v", order.Strategy, order.Pair, p.retries, err)
+			delete(m.positions, key)
+			m.mu.Unlock()
+			m.save(p.group, nil)
+			return
+		}
+		for _, e := range p.legs {
+			e.Triggered = false
+		}
+		p.retryAt = now.Add(RetryInterval)
+		log.Warnf("[Exits] exit sell for %s %s rejected, re-armed: %v", order.Strategy, order.Pair, err)
+
+	case order.Side == model.SideTypeSell && p != nil && err == nil:
+		remaining := p.quantity() - quantity
+		if p.triggered() || remaining <= dustQuantity {
+			delete(m.positions, key)
+			m.mu.Unlock()
+			m.save(p.group, nil)
+			log.Infof("[Exits] %s %s position closed, exits removed", order.Strategy, order.Pair)
+			return
+		}
+		// 전략이 일부만 매도: 남은 수량만 보호
+		for _, e := range p.legs {
+			e.Quantity = remaining
+		}
+
+	default:
+		m.mu.Unlock()
+		return
+	}
+	group := p.group
+	legs := snapshot([]*position{p})[group]
+	m.mu.Unlock()
+	if replaced != "" {
+		m.save(replaced, nil)
+	}
+	m.save(group, legs)
+}
+
+func (m *Manager) save(group string, legs []Exit) {
+	if m.store == nil {
+		return
+	}
+	if err := m.store.SaveExitGroup(context.Background(), group, legs); err != nil {
+		log.Errorf("[Exits] failed to save exits %s: %v", group, err)
+	}
+}
+
+// snapshot : 잠금 밖에서 저장할 수 있도록 복사 (m.mu 를 잡은 상태에서 호출)
+func snapshot(positions []*position) map[string][]Exit {
+	out := make(map[string][]Exit, len(positions))
+	for _, p := range positions {
+		legs := make([]Exit, 0, len(p.legs))
+		for _, e := range p.legs {
+			legs = append(legs, *e)
+		}
+		out[p.group] = legs
+	}
+	return out
+}
+
+// filled : 체결 수량과 평균 체결가. 체결 정보가 없으면 주문 수량/가격
+func filled(order model.Order) (float64, float64) {
+	quantity, price := order.ExecutedQuantity, order.AvgPrice
+	if quantity <= 0 {
+		quantity = order.Quantity
+	}
+	if price <= 0 {
+		price = order.Price
+	}
+	return quantity, price
+}
+
+func positionKey(strategy, pair string) string {
+	return strategy + "|" + strings.ToUpper(pair)
+}
@@ -32,7 +32,7 @@ type DataSubscription struct {
 }
 
 type DataFeedSubscription struct {
-	exchange               interfaces.Exchange
+	exchange               interfaces.DataFeeder
 	Feeds                  *set.LinkedHashSetString      // (pair--timeframe) 세트
 	DataFeeds              map[string]*DataFeed          // key=(pair--timeframe), value=channel pair
 	SubscriptionsByFeedKey map[string][]DataSubscription // key=(pair--timeframe), value=subscriber list
@@ -41,7 +41,7 @@ type DataFeedSubscription struct {
 	cancel context.CancelFunc
 }
 
-func NewDataFeed(exchange interfaces.Exchange) *DataFeedSubscription {
+func NewDataFeed(exchange interfaces.DataFeeder) *DataFeedSubscription {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &DataFeedSubscription{
 		exchange:               exchange,
@@ -77,13 +77,23 @@ func (d *DataFeedSubscription) Preload(pair, period string, candles []model.Cand
 	}
 
 	for _, candle := range candles {
-		for _, subscription := range d.SubscriptionsByFeedKey[key] {
-			// onCandleClose=true 라면, Complete=true 인 봉만 전달
-			if subscription.onCandleClose && !candle.Complete {
-				continue
-			}
-			subscription.consumer(candle)
+		d.dispatch(key, candle)
+	}
+}
+
+// Publish : 캔들을 호출한 고루틴에서 바로 구독자들에게 전달합니다.
+// 백테스트 엔진처럼 거래소 스트림 없이 이벤트 순서를 직접 제어할 때 사용
+func (d *DataFeedSubscription) Publish(pair, period string, candle model.Candle) {
+	d.dispatch(d.makeFeedKey(pair, period), candle)
+}
+
+func (d *DataFeedSubscription) dispatch(key string, candle model.Candle) {
+	for _, subscription := range d.SubscriptionsByFeedKey[key] {
+		// onCandleClose=true 라면, Complete=true 인 봉만 전달
+		if subscription.onCandleClose && !candle.Complete {
+			continue
 		}
+		subscription.consumer(candle)
 	}
 }
 
@@ -108,12 +118,7 @@ func (d *DataFeedSubscription) Start(loadSync bool) {
 						return
 					}
 					// candle 들어옴 => 구독자들에게 브로드캐스트
-					for _, subscription := range d.SubscriptionsByFeedKey[key] {
-						if subscription.onCandleClose && !candle.Complete {
-							continue
-						}
-						subscription.consumer(candle)
-					}
+					d.dispatch(key, candle)
 
 				case err := <-feed.Err:
 					if err != nil {
@@ -0,0 +1,96 @@
+package feed
+
+import (
+	"context"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+	"strings"
+	"sync"
+)
+
+type ExecutionFeedConsumer func(msg model.UpbitMyOrderMessage)
+
+type AssetFeedConsumer func(msg model.UpbitMyAssetMessage)
+
+// ExecutionFeedSubscription : 거래소 private 스트림(체결/잔고)을 구독자에게 전달합니다.
+// 주문 피드가 "주문 의도"를 전달한다면, 이 피드는 거래소가 확인한 실제 결과를 전달합니다.
+type ExecutionFeedSubscription struct {
+	feeder                 interfaces.PrivateFeeder
+	SubscriptionsByFeedKey map[string][]ExecutionFeedConsumer // key=pair
+	assetSubscriptions     []AssetFeedConsumer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.RWMutex
+}
+
+func NewExecutionFeed(feeder interfaces.PrivateFeeder) *ExecutionFeedSubscription {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ExecutionFeedSubscription{
+		feeder:                 feeder,
+		SubscriptionsByFeedKey: make(map[string][]ExecutionFeedConsumer),
+		ctx:                    ctx,
+		cancel:                 cancel,
+	}
+}
+
+func (e *ExecutionFeedSubscription) Subscribe(pair string, consumer ExecutionFeedConsumer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := strings.ToUpper(pair)
+	e.SubscriptionsByFeedKey[key] = append(e.SubscriptionsByFeedKey[key], consumer)
+}
+
+func (e *ExecutionFeedSubscription) SubscribeAsset(consumer AssetFeedConsumer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.assetSubscriptions = append(e.assetSubscriptions, consumer)
+}
+
+func (e *ExecutionFeedSubscription) Start() {
+	cOrder, cAsset, cErr := e.feeder.PrivateSubscription()
+
+	go func() {
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case msg, ok := <-cOrder:
+				if !ok {
+					return
+				}
+				e.mu.RLock()
+				consumers := e.SubscriptionsByFeedKey[strings.ToUpper(msg.Code)]
+				e.mu.RUnlock()
+				for _, consumer := range consumers {
+					consumer(msg)
+				}
+			case msg, ok := <-cAsset:
+				if !ok {
+					return
+				}
+				e.mu.RLock()
+				consumers := e.assetSubscriptions
+				e.mu.RUnlock()
+				for _, consumer := range consumers {
+					consumer(msg)
+				}
+			case err, ok := <-cErr:
+				if !ok {
+					return
+				}
+				log.Error("executionFeedSubscription/start: ", err)
+			}
+		}
+	}()
+
+	log.Infof("Execution feed connected.")
+}
+
+func (e *ExecutionFeedSubscription) Stop() {
+	e.cancel()
+}
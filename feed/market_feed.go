@@ -0,0 +1,171 @@
+package feed
+
+import (
+	"context"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+	"strings"
+	"sync"
+)
+
+type BookConsumer func(book model.Book)
+
+type TradeConsumer func(trade model.TradePrint)
+
+type TickerConsumer func(ticker model.Ticker)
+
+// MarketFeedSubscription : 거래소 공개 실시간 시세(최우선 호가, 체결, 현재가)를 종목별 구독자에게 전달합니다.
+// 종목마다 마지막 값을 보관하므로 구독하지 않고 Book/LastTrade/Ticker 로 조회만 해도 됩니다 (해당 스트림을 구독한 종목만).
+type MarketFeedSubscription struct {
+	feeder interfaces.MarketFeeder
+
+	books   map[string][]BookConsumer   // key=pair
+	trades  map[string][]TradeConsumer  // key=pair
+	tickers map[string][]TickerConsumer // key=pair
+
+	lastBook   map[string]model.Book
+	lastTrade  map[string]model.TradePrint
+	lastTicker map[string]model.Ticker
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.RWMutex
+}
+
+func NewMarketFeed(feeder interfaces.MarketFeeder) *MarketFeedSubscription {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MarketFeedSubscription{
+		feeder:     feeder,
+		books:      make(map[string][]BookConsumer),
+		trades:     make(map[string][]TradeConsumer),
+		tickers:    make(map[string][]TickerConsumer),
+		lastBook:   make(map[string]model.Book),
+		lastTrade:  make(map[string]model.TradePrint),
+		lastTicker: make(map[string]model.Ticker),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// SubscribeBook : pair 의 최우선 호가. consumer 가 nil 이면 마지막 값만 보관
+func (m *MarketFeedSubscription) SubscribeBook(pair string, consumer BookConsumer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(pair)
+	consumers := m.books[key]
+	if consumer != nil {
+		consumers = append(consumers, consumer)
+	}
+	m.books[key] = consumers
+}
+
+// SubscribeTrade : pair 의 체결. consumer 가 nil 이면 마지막 값만 보관
+func (m *MarketFeedSubscription) SubscribeTrade(pair string, consumer TradeConsumer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(pair)
+	consumers := m.trades[key]
+	if consumer != nil {
+		consumers = append(consumers, consumer)
+	}
+	m.trades[key] = consumers
+}
+
+// SubscribeTicker : pair 의 현재가. consumer 가 nil 이면 마지막 값만 보관
+func (m *MarketFeedSubscription) SubscribeTicker(pair string, consumer TickerConsumer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToUpper(pair)
+	consumers := m.tickers[key]
+	if consumer != nil {
+		consumers = append(consumers, consumer)
+	}
+	m.tickers[key] = consumers
+}
+
+// Book : pair 의 마지막 최우선 호가
+func (m *MarketFeedSubscription) Book(pair string) (model.Book, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	book, ok := m.lastBook[strings.ToUpper(pair)]
+	return book, ok
+}
+
+// LastTrade : pair 의 마지막 체결
+func (m *MarketFeedSubscription) LastTrade(pair string) (model.TradePrint, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	trade, ok := m.lastTrade[strings.ToUpper(pair)]
+	return trade, ok
+}
+
+// Ticker : pair 의 마지막 현재가
+func (m *MarketFeedSubscription) Ticker(pair string) (model.Ticker, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ticker, ok := m.lastTicker[strings.ToUpper(pair)]
+	return ticker, ok
+}
+
+// Start : 구독한 종목/스트림마다 거래소 스트림을 열고 전달을 시작합니다. 구독은 Start 전에 해야 합니다.
+func (m *MarketFeedSubscription) Start() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for pair := range m.books {
+		go consume(m.ctx, m.feeder.OrderbookSubscription(pair), model.UpbitOrderbookMessage.Book, func(book model.Book) {
+			m.mu.Lock()
+			m.lastBook[pair] = book
+			consumers := m.books[pair]
+			m.mu.Unlock()
+			for _, consumer := range consumers {
+				consumer(book)
+			}
+		})
+	}
+	for pair := range m.trades {
+		go consume(m.ctx, m.feeder.TradeSubscription(pair), model.UpbitTradeMessage.TradePrint, func(trade model.TradePrint) {
+			m.mu.Lock()
+			m.lastTrade[pair] = trade
+			consumers := m.trades[pair]
+			m.mu.Unlock()
+			for _, consumer := range consumers {
+				consumer(trade)
+			}
+		})
+	}
+	for pair := range m.tickers {
+		go consume(m.ctx, m.feeder.TickerSubscription(pair), model.UpbitTickerMessage.Ticker, func(ticker model.Ticker) {
+			m.mu.Lock()
+			m.lastTicker[pair] = ticker
+			consumers := m.tickers[pair]
+			m.mu.Unlock()
+			for _, consumer := range consumers {
+				consumer(ticker)
+			}
+		})
+	}
+
+	log.Infof("Market feed connected. (orderbook=%d, trade=%d, ticker=%d pairs)", len(m.books), len(m.trades), len(m.tickers))
+}
+
+func (m *MarketFeedSubscription) Stop() {
+	m.cancel()
+}
+
+// consume : 거래소 메시지를 변환해 deliver 로 넘김. 채널이 닫히거나 ctx 가 끝나면 종료
+func consume[M, T any](ctx context.Context, ch chan M, convert func(M) T, deliver func(T)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			deliver(convert(msg))
+		}
+	}
+}
@@ -2,124 +2,289 @@ package feed
 
 import (
 	"context"
-	"fmt"
 	"raccoon/model"
+	"raccoon/utils/log"
 	"sync"
 )
 
-type OrderFeed struct {
-	Data chan model.Order
-	Err  chan error
-}
-
 type OrderFeedConsumer func(order model.Order)
 
+// OrderFeedAckConsumer : 주문을 처리하고 결과를 반환하는 구독자. nil 이면 ack, 에러면 reject
+type OrderFeedAckConsumer func(order model.Order) error
+
+// OrderAckCallback : ack 구독자가 주문을 처리할 때마다 호출됩니다. err 가 nil 이 아니면 reject
+type OrderAckCallback func(order model.Order, err error)
+
+// OrderSubscription : 구독자 한 명의 전달 상태
+//   - queue 는 구독자별로 따로 두어 느린 구독자(텔레그램, 웹서버)가 다른 구독자를 막지 않음
+//   - lastFeedID 보다 작거나 같은 FeedID 는 이미 받은 주문이므로 버림 (중복 방지, 순서 보장)
 type OrderSubscription struct {
-	consumer    OrderFeedConsumer
-	lastOrderId int64
+	consumer OrderFeedAckConsumer
+	ack      bool   // SubscribeAck 로 등록된 구독자만 ack/reject 콜백 대상
+	strategy string // 비어있지 않으면 이 전략이 낸 주문만 전달
+
+	mu         sync.Mutex
+	queue      []model.Order
+	signal     chan struct{}
+	lastFeedID int64
 }
 
 type OrderFeedSubscription struct {
-	OrderFeeds             map[string]*OrderFeed
-	SubscriptionsByFeedKey map[string][]OrderSubscription
+	SubscriptionsByFeedKey map[string][]*OrderSubscription // key=pair
 
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// synchronous=true 이면 채널/고루틴 없이 Publish 호출 시점에 바로 구독자에게 전달 (백테스트용)
+	synchronous bool
+	delivering  bool          // synchronous: 구독자 안에서 다시 Publish 한 주문은 pending 에 쌓았다가 순서대로 전달
+	pending     []model.Order // synchronous: 아직 전달하지 않은 주문
+
+	started  bool
+	lastID   int64 // 마지막으로 매긴 FeedID
+	ackHooks []OrderAckCallback
+
+	// WithStrategy 로 만든 핸들: Publish 시 strategy 태그를 붙여 parent 로 전달
+	parent   *OrderFeedSubscription
+	strategy string
+
 	mu sync.RWMutex
 }
 
 func NewOrderFeed() *OrderFeedSubscription {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &OrderFeedSubscription{
-		OrderFeeds:             make(map[string]*OrderFeed),
-		SubscriptionsByFeedKey: make(map[string][]OrderSubscription),
+		SubscriptionsByFeedKey: make(map[string][]*OrderSubscription),
 		ctx:                    ctx,
 		cancel:                 cancel,
 	}
 }
 
+// NewSyncOrderFeed : Publish 한 주문이 반환되기 전에 모든 구독자에게 처리되는 주문 피드.
+// 주문이 만들어진 캔들 안에서 체결까지 끝나야 하는 백테스트에서 사용합니다.
+func NewSyncOrderFeed() *OrderFeedSubscription {
+	d := NewOrderFeed()
+	d.synchronous = true
+	return d
+}
+
+// WithStrategy : 발행하는 주문에 전략 이름을 태그하는 핸들을 반환합니다.
+// 같은 주문 피드를 여러 전략이 공유할 때 전략 생성자에 이 핸들을 넘깁니다.
+// 핸들은 공유 피드의 수명을 갖지 않으므로 핸들의 Stop 은 아무 일도 하지 않습니다.
+func (d *OrderFeedSubscription) WithStrategy(name string) *OrderFeedSubscription {
+	root := d.root()
+	return &OrderFeedSubscription{
+		parent:   root,
+		strategy: name,
+		ctx:      root.ctx,
+	}
+}
+
 func (d *OrderFeedSubscription) Subscribe(pair string, consumer OrderFeedConsumer) {
+	d.SubscribeStrategy(pair, "", consumer)
+}
+
+// SubscribeStrategy : pair 주문 중 strategy 가 낸 주문만 구독합니다. strategy 가 비어있으면 전체 구독
+func (d *OrderFeedSubscription) SubscribeStrategy(pair, strategy string, consumer OrderFeedConsumer) {
+	d.root().subscribe(pair, strategy, false, func(order model.Order) error {
+		consumer(order)
+		return nil
+	})
+}
+
+// SubscribeAck : 처리 결과(ack/reject)를 반환하는 구독자를 등록합니다. 결과는 OnAck 콜백으로 전달됩니다.
+// 주문을 실제로 실행하는 구독자(OrderFeedConsumerBroker)에 사용합니다.
+func (d *OrderFeedSubscription) SubscribeAck(pair, strategy string, consumer OrderFeedAckConsumer) {
+	d.root().subscribe(pair, strategy, true, consumer)
+}
+
+// OnAck : ack 구독자가 주문을 처리한 결과를 받을 콜백을 등록합니다.
+func (d *OrderFeedSubscription) OnAck(cb OrderAckCallback) {
+	root := d.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	root.ackHooks = append(root.ackHooks, cb)
+}
+
+func (d *OrderFeedSubscription) subscribe(pair, strategy string, ack bool, consumer OrderFeedAckConsumer) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	if _, ok := d.OrderFeeds[pair]; !ok {
-		d.OrderFeeds[pair] = &OrderFeed{
-			Data: make(chan model.Order, 100), //버퍼링된 채널로 퍼블리시 블로킹 방지
-			Err:  make(chan error, 10),
-		}
+	sub := &OrderSubscription{
+		consumer: consumer,
+		ack:      ack,
+		strategy: strategy,
+		signal:   make(chan struct{}, 1),
 	}
+	d.SubscriptionsByFeedKey[pair] = append(d.SubscriptionsByFeedKey[pair], sub)
 
-	if _, ok := d.SubscriptionsByFeedKey[pair]; !ok {
-		d.SubscriptionsByFeedKey[pair] = make([]OrderSubscription, 0)
+	if d.started && !d.synchronous {
+		go d.run(sub)
 	}
-	d.SubscriptionsByFeedKey[pair] = append(d.SubscriptionsByFeedKey[pair], OrderSubscription{
-		consumer: consumer,
-	})
-
 }
 
+// Publish : 주문에 FeedID 를 매기고 pair 구독자에게 전달합니다.
+// 이미 FeedID 가 있는 주문(재발행)은 번호를 바꾸지 않으므로 이미 받은 구독자는 중복으로 버립니다.
+// 비동기 피드에서는 구독자별 큐에 넣고 바로 반환하므로 느린 구독자가 주문 실행을 막지 않습니다.
 func (d *OrderFeedSubscription) Publish(order model.Order) {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
+	if d.parent != nil {
+		if order.Strategy == "" {
+			order.Strategy = d.strategy
+		}
+		d.parent.Publish(order)
+		return
+	}
 
-	if feed, ok := d.OrderFeeds[order.Pair]; ok {
-		select {
-		case feed.Data <- order:
+	if d.ctx.Err() != nil {
+		return
+	}
+
+	d.mu.Lock()
+	if order.FeedID == 0 {
+		d.lastID++
+		order.FeedID = d.lastID
+	}
+
+	if d.synchronous {
+		d.pending = append(d.pending, order)
+		if d.delivering {
+			d.mu.Unlock()
 			return
-		case <-d.ctx.Done():
+		}
+		d.delivering = true
+		d.mu.Unlock()
+		d.drainSync()
+		return
+	}
+
+	defer d.mu.Unlock()
+	for _, sub := range d.SubscriptionsByFeedKey[order.Pair] {
+		if !sub.matches(order) {
+			continue
+		}
+		sub.mu.Lock()
+		sub.queue = append(sub.queue, order)
+		sub.mu.Unlock()
+		select {
+		case sub.signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// drainSync : synchronous 피드에서 pending 주문을 발행 순서대로 전달합니다.
+func (d *OrderFeedSubscription) drainSync() {
+	for {
+		d.mu.Lock()
+		if len(d.pending) == 0 || d.ctx.Err() != nil {
+			d.pending = nil
+			d.delivering = false
+			d.mu.Unlock()
 			return
 		}
+		order := d.pending[0]
+		d.pending = d.pending[1:]
+		subscriptions := append([]*OrderSubscription(nil), d.SubscriptionsByFeedKey[order.Pair]...)
+		d.mu.Unlock()
 
+		for _, sub := range subscriptions {
+			if sub.matches(order) {
+				d.deliver(sub, order)
+			}
+		}
 	}
 }
 
 func (d *OrderFeedSubscription) Start() {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-
-	for pair := range d.OrderFeeds {
-		go func(pair string, feed *OrderFeed) {
-			for {
-				select {
-				case <-d.ctx.Done():
-					close(feed.Data)
-					close(feed.Err)
-					return
-				case order, ok := <-feed.Data:
-					if !ok {
-						return
-					}
-					d.deliverToSubscribers(pair, order)
-				case err, ok := <-feed.Err:
-					if ok {
-						//TODO Error handling
-						fmt.Println(err)
-						return
-					}
-				}
+	if d.parent != nil {
+		d.parent.Start()
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started {
+		return
+	}
+	d.started = true
+	if d.synchronous {
+		return
+	}
+
+	for _, subscriptions := range d.SubscriptionsByFeedKey {
+		for _, sub := range subscriptions {
+			go d.run(sub)
+		}
+	}
+}
+
+// run : 구독자 한 명의 큐를 순서대로 비우는 고루틴. Start 전에 발행된 주문도 여기서 전달됩니다.
+func (d *OrderFeedSubscription) run(sub *OrderSubscription) {
+	for {
+		sub.mu.Lock()
+		if len(sub.queue) == 0 {
+			sub.mu.Unlock()
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-sub.signal:
 			}
-		}(pair, d.OrderFeeds[pair])
+			continue
+		}
+		order := sub.queue[0]
+		sub.queue = sub.queue[1:]
+		sub.mu.Unlock()
+
+		if d.ctx.Err() != nil {
+			return
+		}
+		d.deliver(sub, order)
 	}
 }
 
-func (d *OrderFeedSubscription) deliverToSubscribers(pair string, order model.Order) {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
+// deliver : 이미 받은 주문은 버리고, ack 구독자의 처리 결과는 OnAck 콜백으로 전달합니다.
+func (d *OrderFeedSubscription) deliver(sub *OrderSubscription, order model.Order) {
+	sub.mu.Lock()
+	if order.FeedID <= sub.lastFeedID {
+		sub.mu.Unlock()
+		return
+	}
+	sub.lastFeedID = order.FeedID
+	sub.mu.Unlock()
 
-	subscriptions, ok := d.SubscriptionsByFeedKey[pair]
-	if !ok {
+	err := sub.consumer(order)
+	if !sub.ack {
 		return
 	}
+	if err != nil {
+		log.Warnf("[OrderFeed] Order rejected - FeedID: %d, Pair: %s, Strategy: %s, err: %v",
+			order.FeedID, order.Pair, order.Strategy, err)
+	}
 
-	for _, sub := range subscriptions {
-		//TODO lastOrderId와 order의 id를 비교하는 로직을 구현하고 중복및 구매 순서 보장 해야함
-		sub.lastOrderId = order.ID
-		//TODO 비동기 처리 필요, but 고루틴으로 하면 order의 순서를 보장 못함. 메세지큐 도입해야함
-		sub.consumer(order)
+	d.mu.RLock()
+	hooks := d.ackHooks
+	d.mu.RUnlock()
+	for _, cb := range hooks {
+		cb(order, err)
 	}
 }
 
+func (s *OrderSubscription) matches(order model.Order) bool {
+	return s.strategy == "" || s.strategy == order.Strategy
+}
+
+// Stop : 구독자 고루틴을 멈춥니다. Stop 이후에 발행하거나 큐에 남은 주문은 전달하지 않습니다.
 func (d *OrderFeedSubscription) Stop() {
+	if d.parent != nil {
+		return // 공유 피드는 만든 쪽(root)이 멈춤
+	}
 	d.cancel()
 }
+
+func (d *OrderFeedSubscription) root() *OrderFeedSubscription {
+	if d.parent != nil {
+		return d.parent
+	}
+	return d
+}
@@ -25,6 +25,16 @@ type Broker interface {
 	Cancel(order model.Order, isIdentifier bool) error
 }
 
+// IdentifiedBroker : 클라이언트 주문 식별자(identifier)를 붙여 주문할 수 있는 브로커.
+// 응답을 받기 전에 프로세스가 죽어도 Order(pair, identifier, true) 로 주문 접수 여부를 확인할 수 있습니다.
+type IdentifiedBroker interface {
+	CreateOrderLimitIdentified(identifier string, side model.SideType, pair string,
+		quantity float64, limit float64, tif ...model.TimeInForceType) (model.Order, error)
+	CreateOrderMarketIdentified(identifier string, side model.SideType, pair string, quantity float64) (model.Order, error)
+	CreateOrderBestIdentified(identifier string, side model.SideType, pair string,
+		quantity float64, tif ...model.TimeInForceType) (model.Order, error)
+}
+
 type DataFeeder interface {
 	AssetsInfo(pair string) model.AssetInfo
 	LastQuote(pair string) (float64, error)
@@ -35,6 +45,18 @@ type DataFeeder interface {
 	Stop()
 }
 
+// PrivateFeeder : 거래소가 직접 확인해주는 체결(myOrder) 및 잔고(myAsset) 스트림
+type PrivateFeeder interface {
+	PrivateSubscription() (chan model.UpbitMyOrderMessage, chan model.UpbitMyAssetMessage, chan error)
+}
+
+// MarketFeeder : 거래소 공개 실시간 시세 스트림 (호가, 체결, 현재가)
+type MarketFeeder interface {
+	OrderbookSubscription(pair string) chan model.UpbitOrderbookMessage
+	TradeSubscription(pair string) chan model.UpbitTradeMessage
+	TickerSubscription(pair string) chan model.UpbitTickerMessage
+}
+
 type Notifier interface {
 	SendNotification(message string) error
 	OrderNotifier(order model.Order, err error)
@@ -49,14 +71,42 @@ type Strategy interface {
 	OnCandle(df *model.Dataframe, broker Broker)
 }
 
+// TunableStrategy : 최적화 대상 파라미터를 선언하는 전략
+type TunableStrategy interface {
+	Strategy
+	Parameters() []model.ParamSpec
+	SetParameters(params model.ParamSet) error
+}
+
+// OrderManager : 주문 생애주기(wait → trade → done/cancel) 조회. 구현체는 oms.Manager
+type OrderManager interface {
+	Order(exchangeID string) (model.Order, bool)
+	OpenOrders(pair string) []model.Order
+	StrategyOrders(strategy string) []model.Order
+	Subscribe(handler func(event model.OrderEvent))
+}
+
+// OrderManagedStrategy : 주문 상태를 조회하거나 체결 이벤트를 받아야 하는 전략. 시작 전에 OrderManager 가 주입됩니다.
+type OrderManagedStrategy interface {
+	Strategy
+	SetOrderManager(orders OrderManager)
+}
+
 type HighFrequencyStrategy interface {
 	Strategy
 	OnPartialCandle(df *model.Dataframe, broker Broker)
 }
 
+// MarketDataStrategy : 봉 외에 실시간 최우선 호가와 체결을 받는 전략 (실거래/모의투자만, 백테스트에는 없음)
+type MarketDataStrategy interface {
+	Strategy
+	OnBook(book model.Book, broker Broker)
+	OnTrade(trade model.TradePrint, broker Broker)
+}
+
 type WebServer interface {
 	OnCandle(candle model.Candle)
 	OnOrder(order model.Order)
-	OnIndicators(timestamp time.Time, values []webserver.IndicatorValue)
+	OnIndicators(pair string, timestamp time.Time, values []webserver.IndicatorValue)
 	Start(port string) error
 }
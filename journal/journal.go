@@ -0,0 +1,313 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+// Status : 저널에 기록된 주문 의도의 상태
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 의도만 기록됨. 거래소 응답을 받기 전
+	StatusSubmitted Status = "submitted" // 거래소가 접수함. 체결 대기 중
+	StatusDone      Status = "done"      // 체결 또는 취소로 종료
+	StatusRejected  Status = "rejected"  // 거래소가 주문을 받지 않음
+	StatusAbandoned Status = "abandoned" // 재시작 시 거래소에 없는 것으로 확인된 의도 (다시 보내지 않음)
+)
+
+var ErrUnknownIdentifier = errors.New("journal: unknown identifier")
+
+// Entry : 저널 파일의 한 줄
+type Entry struct {
+	Seq        int64       `json:"seq"`
+	Time       time.Time   `json:"time"`
+	Identifier string      `json:"identifier"`
+	Status     Status      `json:"status"`
+	Order      model.Order `json:"order"` // pending 은 요청한 주문, 그 이후는 거래소가 돌려준 주문
+	Error      string      `json:"error,omitempty"`
+}
+
+// Record : identifier 하나의 최신 상태
+type Record struct {
+	Identifier string
+	Status     Status
+	Order      model.Order
+	Error      string
+	Seq        int64 // 의도를 기록한 순서
+	UpdatedAt  time.Time
+}
+
+// Closed : 더 이상 거래소에서 바뀌지 않는 상태인지
+func (r Record) Closed() bool {
+	return r.Status == StatusDone || r.Status == StatusRejected || r.Status == StatusAbandoned
+}
+
+// Journal : 주문 의도와 거래소 결과를 주문 전에 디스크에 남기는 write-ahead 로그 (JSON Lines).
+//   - Intent 로 identifier 를 받아 기록한 뒤 그 identifier 로 주문하고, 결과를 Submitted/Rejected 로 기록
+//   - 매 기록마다 fsync 하므로 프로세스가 어느 시점에 죽어도 보낸(보냈을 수 있는) 주문이 남음
+//   - 재시작 시 Reconcile 로 끝나지 않은 기록을 거래소 주문과 맞춤
+type Journal struct {
+	path   string
+	prefix string // identifier 접두사 (Open 시각). 저널 파일이 지워져도 identifier 가 겹치지 않게 함
+
+	mu      sync.Mutex
+	file    *os.File
+	seq     int64
+	records map[string]*Record // 종료되지 않은 기록만
+}
+
+// Open : path 의 저널을 읽어 종료되지 않은 기록을 복원합니다. 파일이 없으면 새로 만듭니다.
+// 종료된 기록은 버리고 남은 기록만으로 파일을 다시 씁니다.
+func Open(path string) (*Journal, error) {
+	j := &Journal{
+		path:    path,
+		prefix:  "rc" + strconv.FormatInt(time.Now().UnixMilli(), 36),
+		records: make(map[string]*Record),
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("journal: open %s: %w", path, err)
+	}
+	j.file = file
+	return j, nil
+}
+
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// Intent : 주문을 보내기 전에 호출합니다. 반환한 identifier 로 주문해야 재시작 후 찾을 수 있습니다.
+func (j *Journal) Intent(order model.Order) (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	identifier := fmt.Sprintf("%s-%d", j.prefix, j.seq+1)
+	order.Identifier = identifier
+	_, err := j.append(Entry{Identifier: identifier, Status: StatusPending, Order: order})
+	return identifier, err
+}
+
+// Submitted : 거래소가 주문을 접수했을 때. 이미 체결/취소된 주문이면 바로 종료로 기록합니다.
+func (j *Journal) Submitted(identifier string, order model.Order) error {
+	_, err := j.submitted(identifier, order)
+	return err
+}
+
+// Rejected : 거래소가 주문을 받지 않았음이 확실할 때.
+func (j *Journal) Rejected(identifier string, cause error) error {
+	_, err := j.close(identifier, StatusRejected, cause)
+	return err
+}
+
+// Completed : 접수된 주문이 체결/취소로 끝났을 때 (private 스트림 등). 저널에 없는 주문은 무시합니다.
+func (j *Journal) Completed(order model.Order) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, r := range j.records {
+		if (order.Identifier != "" && r.Identifier == order.Identifier) ||
+			(order.ExchangeID != "" && r.Order.ExchangeID == order.ExchangeID) {
+			order.Identifier = r.Identifier
+			_, err := j.append(Entry{Identifier: r.Identifier, Status: StatusDone, Order: order})
+			return err
+		}
+	}
+	return nil
+}
+
+// Record : identifier 의 최신 상태. 종료된 기록은 찾지 않습니다.
+func (j *Journal) Record(identifier string) (Record, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	r, ok := j.records[identifier]
+	if !ok {
+		return Record{}, false
+	}
+	return *r, true
+}
+
+// Open : 종료되지 않은 기록 (의도 순서)
+func (j *Journal) Open() []Record {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	records := make([]Record, 0, len(j.records))
+	for _, r := range j.records {
+		records = append(records, *r)
+	}
+	sort.Slice(records, func(a, b int) bool { return records[a].Seq < records[b].Seq })
+	return records
+}
+
+func (j *Journal) submitted(identifier string, order model.Order) (Record, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, ok := j.records[identifier]; !ok {
+		return Record{}, fmt.Errorf("%w: %s", ErrUnknownIdentifier, identifier)
+	}
+	order.Identifier = identifier
+	return j.append(Entry{Identifier: identifier, Status: submittedStatus(order), Order: order})
+}
+
+func (j *Journal) close(identifier string, status Status, cause error) (Record, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	r, ok := j.records[identifier]
+	if !ok {
+		return Record{}, fmt.Errorf("%w: %s", ErrUnknownIdentifier, identifier)
+	}
+	entry := Entry{Identifier: identifier, Status: status, Order: r.Order}
+	if cause != nil {
+		entry.Error = cause.Error()
+	}
+	return j.append(entry)
+}
+
+// append : 한 줄을 기록하고 fsync 한 뒤 메모리 상태에 반영합니다. (j.mu 를 잡은 상태에서 호출)
+func (j *Journal) append(entry Entry) (Record, error) {
+	if j.file == nil {
+		return Record{}, fmt.Errorf("journal: %s is closed", j.path)
+	}
+	entry.Seq = j.seq + 1
+	entry.Time = time.Now()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Record{}, err
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return Record{}, fmt.Errorf("journal: write %s: %w", j.path, err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return Record{}, fmt.Errorf("journal: sync %s: %w", j.path, err)
+	}
+	return j.apply(entry), nil
+}
+
+// apply : 기록 하나를 메모리 상태에 반영하고 반영 후 상태를 반환합니다. 종료된 기록은 메모리에서 지웁니다.
+func (j *Journal) apply(entry Entry) Record {
+	if entry.Seq > j.seq {
+		j.seq = entry.Seq
+	}
+	r, ok := j.records[entry.Identifier]
+	if !ok {
+		r = &Record{Identifier: entry.Identifier, Seq: entry.Seq}
+	}
+	r.Status = entry.Status
+	r.Order = entry.Order
+	r.Error = entry.Error
+	r.UpdatedAt = entry.Time
+	if r.Closed() {
+		delete(j.records, entry.Identifier)
+	} else {
+		j.records[entry.Identifier] = r
+	}
+	return *r
+}
+
+// load : 저널 파일을 읽습니다. 기록 도중 죽어 마지막 줄이 잘린 경우는 그 줄만 버립니다.
+func (j *Journal) load() error {
+	file, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("journal: open %s: %w", j.path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var torn error
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if torn != nil {
+			return torn
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			torn = fmt.Errorf("journal: %s line %d: %w", j.path, line, err)
+			continue
+		}
+		j.apply(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("journal: read %s: %w", j.path, err)
+	}
+	if torn != nil {
+		log.Warnf("[Journal] ignoring incomplete last entry: %v", torn)
+	}
+	return nil
+}
+
+// compact : 종료되지 않은 기록의 최신 상태만 남겨 파일을 다시 씁니다.
+func (j *Journal) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("journal: compact %s: %w", j.path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	for _, r := range j.Open() {
+		line, err := json.Marshal(Entry{
+			Seq: r.Seq, Time: r.UpdatedAt, Identifier: r.Identifier, Status: r.Status, Order: r.Order, Error: r.Error,
+		})
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("journal: compact %s: %w", j.path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("journal: compact %s: %w", j.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), j.path)
+}
+
+func submittedStatus(order model.Order) Status {
+	if order.Status == model.OrderStatusTypeDone || order.Status == model.OrderStatusTypeCanceled {
+		return StatusDone
+	}
+	return StatusSubmitted
+}
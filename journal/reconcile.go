@@ -0,0 +1,112 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+
+	"raccoon/exchange"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+// Reconcile : 재시작 시 종료되지 않은 기록을 거래소 주문과 맞춥니다. 주문을 다시 보내지는 않습니다.
+//   - pending : identifier 로 조회해 접수됐으면 submitted/done, 거래소에 없으면 abandoned
+//   - submitted : OpenOrders 에 없으면 uuid 로 조회해 최종 상태를 기록
+//
+// 확인하지 못한 기록(조회 실패)은 그대로 남기고 에러로 알려줍니다. 상태가 바뀐 기록을 반환합니다.
+func (j *Journal) Reconcile(broker interfaces.Broker) ([]Record, error) {
+	var changed []Record
+	var errs []error
+	openOrders := make(map[string]map[string]bool) // pair → 미체결 주문 uuid
+
+	for _, r := range j.Open() {
+		var updated Record
+		var err error
+		switch r.Status {
+		case StatusPending:
+			updated, err = j.reconcilePending(broker, r)
+		case StatusSubmitted:
+			pair := r.Order.Pair
+			if _, ok := openOrders[pair]; !ok {
+				orders, openErr := broker.OpenOrders(pair, 0)
+				if openErr != nil {
+					errs = append(errs, fmt.Errorf("%s: open orders %s: %w", r.Identifier, pair, openErr))
+					continue
+				}
+				openOrders[pair] = make(map[string]bool, len(orders))
+				for _, o := range orders {
+					openOrders[pair][o.ExchangeID] = true
+				}
+			}
+			if openOrders[pair][r.Order.ExchangeID] {
+				continue
+			}
+			updated, err = j.reconcileSubmitted(broker, r)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if updated.Status != r.Status {
+			changed = append(changed, updated)
+		}
+	}
+
+	if len(errs) > 0 {
+		return changed, fmt.Errorf("journal: reconcile: %w", errors.Join(errs...))
+	}
+	return changed, nil
+}
+
+// Resolve : 주문 요청이 에러로 끝났을 때 거래소가 실제로 접수했는지 identifier 로 확인해 기록합니다.
+// 접수된 주문을 찾으면 그 주문을 반환합니다. 확인하지 못하면 pending 으로 남겨 다음 Reconcile 에 맡깁니다.
+func (j *Journal) Resolve(broker interfaces.Broker, identifier string, cause error) (model.Order, bool) {
+	r, ok := j.Record(identifier)
+	if !ok {
+		return model.Order{}, false
+	}
+	if _, identified := broker.(interfaces.IdentifiedBroker); !identified {
+		// identifier 로 조회할 수 없으면 에러를 그대로 믿음
+		logWriteErr(j.Rejected(identifier, cause))
+		return model.Order{}, false
+	}
+
+	order, err := broker.Order(r.Order.Pair, identifier, true)
+	switch {
+	case err == nil:
+		logWriteErr(j.Submitted(identifier, order))
+		return order, true
+	case errors.Is(err, exchange.ErrOrderNotFound):
+		logWriteErr(j.Rejected(identifier, cause))
+	}
+	return model.Order{}, false
+}
+
+func (j *Journal) reconcilePending(broker interfaces.Broker, r Record) (Record, error) {
+	if _, ok := broker.(interfaces.IdentifiedBroker); !ok {
+		return r, fmt.Errorf("%s: broker cannot look up orders by identifier", r.Identifier)
+	}
+	order, err := broker.Order(r.Order.Pair, r.Identifier, true)
+	if errors.Is(err, exchange.ErrOrderNotFound) {
+		return j.close(r.Identifier, StatusAbandoned, errors.New("not found on exchange after restart"))
+	}
+	if err != nil {
+		return r, fmt.Errorf("%s: %w", r.Identifier, err)
+	}
+	return j.submitted(r.Identifier, order)
+}
+
+func (j *Journal) reconcileSubmitted(broker interfaces.Broker, r Record) (Record, error) {
+	order, err := broker.Order(r.Order.Pair, r.Order.ExchangeID, false)
+	if err != nil {
+		return r, fmt.Errorf("%s: %w", r.Identifier, err)
+	}
+	return j.submitted(r.Identifier, order)
+}
+
+func logWriteErr(err error) {
+	if err != nil {
+		log.Errorf("[Journal] %v", err)
+	}
+}
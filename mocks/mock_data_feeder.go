@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"errors"
+	"time"
+
+	"raccoon/model"
+)
+
+// MockDataFeeder : 메모리에 들고 있는 과거 캔들을 돌려주는 DataFeeder (백테스트 테스트용)
+type MockDataFeeder struct {
+	Candles map[string][]model.Candle  // key=pair_timeframe
+	Quotes  map[string]float64         // key=pair, LastQuote 가 돌려줄 현재가
+	Assets  map[string]model.AssetInfo // key=pair, AssetsInfo 가 돌려줄 종목 정보
+}
+
+func NewMockDataFeeder() *MockDataFeeder {
+	return &MockDataFeeder{
+		Candles: make(map[string][]model.Candle),
+		Quotes:  make(map[string]float64),
+		Assets:  make(map[string]model.AssetInfo),
+	}
+}
+
+func (m *MockDataFeeder) Add(pair, period string, candles ...model.Candle) {
+	key := pair + "_" + period
+	m.Candles[key] = append(m.Candles[key], candles...)
+}
+
+func (m *MockDataFeeder) Start() {}
+func (m *MockDataFeeder) Stop()  {}
+
+func (m *MockDataFeeder) AssetsInfo(pair string) model.AssetInfo {
+	return m.Assets[pair]
+}
+
+func (m *MockDataFeeder) LastQuote(pair string) (float64, error) {
+	if quote, ok := m.Quotes[pair]; ok {
+		return quote, nil
+	}
+	return 0, errors.New("no quote in mock")
+}
+
+func (m *MockDataFeeder) CandlesByLimit(pair, period string, limit int) ([]model.Candle, error) {
+	candles := m.Candles[pair+"_"+period]
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return append([]model.Candle(nil), candles...), nil
+}
+
+func (m *MockDataFeeder) CandlesByPeriod(pair, period string, start, end time.Time) ([]model.Candle, error) {
+	var out []model.Candle
+	for _, c := range m.Candles[pair+"_"+period] {
+		if c.Time.Before(start) || c.Time.After(end) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (m *MockDataFeeder) CandlesSubscription(pair, period string) (chan model.Candle, chan error) {
+	return make(chan model.Candle), make(chan error)
+}
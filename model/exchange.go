@@ -56,6 +56,7 @@ type OrderChance struct {
 // OrderResponse : 주문 생성/취소/조회 시 반환되는 객체
 type OrderResponse struct {
 	UUID            string  `json:"uuid"`
+	Identifier      string  `json:"identifier"`
 	Side            string  `json:"side"`
 	OrdType         string  `json:"ord_type"`
 	Price           string  `json:"price"`
@@ -269,14 +270,21 @@ type Balance struct {
 	UnitCurrency string  `json:"unit_currency"`
 }
 
+// UpbitMyAssetUnit : myAsset 메시지의 개별 자산 정보
+type UpbitMyAssetUnit struct {
+	Currency string  `json:"currency"` // 화폐 코드, ex) "KRW"
+	Balance  float64 `json:"balance"`  // 주문가능 수량
+	Locked   float64 `json:"locked"`   // 주문 중 묶여있는 수량
+}
+
 // UpbitMyAssetMessage : 업비트 myAsset 타입 응답 메시지 구조체
 type UpbitMyAssetMessage struct {
-	Type           string  `json:"type"`            // "myAsset"
-	AssetUUID      string  `json:"asset_uuid"`      // 자산 고유 아이디
-	Assets         []Asset `json:"assets"`          // 자산 리스트
-	AssetTimestamp int64   `json:"asset_timestamp"` // 자산 타임스탬프 (millisecond)
-	Timestamp      int64   `json:"timestamp"`       // 타임스탬프 (millisecond)
-	StreamType     string  `json:"stream_type"`     // 스트림 타입 (예: "REALTIME")
+	Type           string             `json:"type"`            // "myAsset"
+	AssetUUID      string             `json:"asset_uuid"`      // 자산 고유 아이디
+	Assets         []UpbitMyAssetUnit `json:"assets"`          // 자산 리스트
+	AssetTimestamp int64              `json:"asset_timestamp"` // 자산 타임스탬프 (millisecond)
+	Timestamp      int64              `json:"timestamp"`       // 타임스탬프 (millisecond)
+	StreamType     string             `json:"stream_type"`     // 스트림 타입 (예: "REALTIME")
 }
 
 type AssetInfo struct {
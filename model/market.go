@@ -0,0 +1,97 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Book : 최우선 호가 (top of book)
+type Book struct {
+	Pair         string    `json:"pair"`
+	Time         time.Time `json:"time"`
+	BidPrice     float64   `json:"bid_price"` // 최우선 매수 호가
+	BidSize      float64   `json:"bid_size"`
+	AskPrice     float64   `json:"ask_price"` // 최우선 매도 호가
+	AskSize      float64   `json:"ask_size"`
+	TotalBidSize float64   `json:"total_bid_size"` // 호가창 전체 매수 잔량
+	TotalAskSize float64   `json:"total_ask_size"` // 호가창 전체 매도 잔량
+}
+
+// Spread : 최우선 매도 호가 - 최우선 매수 호가
+func (b Book) Spread() float64 {
+	return b.AskPrice - b.BidPrice
+}
+
+// Mid : 최우선 매수/매도 호가의 중간값
+func (b Book) Mid() float64 {
+	return (b.AskPrice + b.BidPrice) / 2
+}
+
+// SpreadRate : 중간값 대비 스프레드 비율. 호가가 없으면 0
+func (b Book) SpreadRate() float64 {
+	mid := b.Mid()
+	if mid <= 0 {
+		return 0
+	}
+	return b.Spread() / mid
+}
+
+// TradePrint : 거래소 체결 한 건 (다른 참여자 체결 포함). Side 는 체결을 일으킨 쪽 (bid=매수 체결, ask=매도 체결)
+type TradePrint struct {
+	Pair         string    `json:"pair"`
+	Time         time.Time `json:"time"`
+	Price        float64   `json:"price"`
+	Quantity     float64   `json:"quantity"`
+	Side         SideType  `json:"side"`
+	SequentialID int64     `json:"sequential_id"` // 체결 번호 (중복 확인용)
+}
+
+// Ticker : 현재가 요약
+type Ticker struct {
+	Pair              string    `json:"pair"`
+	Time              time.Time `json:"time"`
+	Price             float64   `json:"price"`                // 현재가
+	ChangeRate        float64   `json:"change_rate"`          // 전일 대비 등락율 (부호 포함)
+	AccTradeVolume24h float64   `json:"acc_trade_volume_24h"` // 24시간 누적 거래량
+	AccTradePrice24h  float64   `json:"acc_trade_price_24h"`  // 24시간 누적 거래대금
+}
+
+// Book : 호가 메시지의 최우선 호가
+func (m UpbitOrderbookMessage) Book() Book {
+	book := Book{
+		Pair:         m.Code,
+		Time:         time.UnixMilli(m.Timestamp),
+		TotalBidSize: m.TotalBidSize,
+		TotalAskSize: m.TotalAskSize,
+	}
+	if len(m.OrderbookUnits) > 0 {
+		best := m.OrderbookUnits[0]
+		book.BidPrice, book.BidSize = best.BidPrice, best.BidSize
+		book.AskPrice, book.AskSize = best.AskPrice, best.AskSize
+	}
+	return book
+}
+
+// TradePrint : 체결 메시지를 TradePrint 로 변환합니다.
+func (m UpbitTradeMessage) TradePrint() TradePrint {
+	return TradePrint{
+		Pair:         m.Code,
+		Time:         time.UnixMilli(m.TradeTimestamp),
+		Price:        m.TradePrice,
+		Quantity:     m.TradeVolume,
+		Side:         SideType(strings.ToLower(m.AskBid)),
+		SequentialID: m.SequentialID,
+	}
+}
+
+// Ticker : 현재가 메시지를 Ticker 로 변환합니다.
+func (m UpbitTickerMessage) Ticker() Ticker {
+	return Ticker{
+		Pair:              m.Code,
+		Time:              time.UnixMilli(m.Timestamp),
+		Price:             m.TradePrice,
+		ChangeRate:        m.SignedChangeRate,
+		AccTradeVolume24h: m.AccTradeVolume24h,
+		AccTradePrice24h:  m.AccTradePrice24h,
+	}
+}
@@ -31,18 +31,41 @@ const (
 	OrderStatusTypeCanceled OrderStatusType = "cancel"
 	OrderStatusTypeDone     OrderStatusType = "done"
 	OrderStatusTypeWait     OrderStatusType = "wait"
+	OrderStatusTypeTrade    OrderStatusType = "trade"
 	OrderStatusTypeWatch    OrderStatusType = "watch"
 )
 
+// Fill : 주문의 체결 한 건
+type Fill struct {
+	TradeID  string    `json:"trade_id"`
+	Price    float64   `json:"price"`
+	Quantity float64   `json:"quantity"`
+	Funds    float64   `json:"funds"` // 체결 금액 (Price * Quantity)
+	Fee      float64   `json:"fee"`
+	Maker    bool      `json:"maker,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
 type Order struct {
 	ID         int64           `json:"id"`
+	FeedID     int64           `json:"feed_id,omitempty"` // 주문 피드가 발행 순서대로 매기는 번호
 	ExchangeID string          `json:"exchange_id"`
+	Identifier string          `json:"identifier,omitempty"` // 클라이언트 주문 식별자 (Upbit identifier)
 	Pair       string          `json:"pair"`
 	Side       SideType        `json:"side"`
 	Type       OrderType       `json:"type"`
 	Status     OrderStatusType `json:"status"`
 	Price      float64         `json:"price"`
 	Quantity   float64         `json:"quantity"`
+	Strategy   string          `json:"strategy,omitempty"`  // 주문을 낸 전략 (여러 전략 동시 운용 시)
+	Paper      bool            `json:"paper,omitempty"`     // 모의투자(PaperBroker) 주문
+	Execution  *Execution      `json:"execution,omitempty"` // 나눠서 실행할 주문이면 실행 방식 (nil 이면 시장가 한 번)
+
+	// 체결 내역 (부분 체결 포함)
+	ExecutedQuantity float64 `json:"executed_quantity,omitempty"` // 체결된 수량
+	AvgPrice         float64 `json:"avg_price,omitempty"`         // 평균 체결가
+	PaidFee          float64 `json:"paid_fee,omitempty"`          // 지불한 수수료
+	Fills            []Fill  `json:"fills,omitempty"`             // 체결 목록 (체결 순서)
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -53,3 +76,52 @@ type Order struct {
 	ProfitValue float64 `json:"profit_value"`
 	Candle      Candle  `json:"-"`
 }
+
+// Closed : 체결 완료 또는 취소로 더 이상 바뀌지 않는 주문인지
+func (o Order) Closed() bool {
+	return o.Status == OrderStatusTypeDone || o.Status == OrderStatusTypeCanceled
+}
+
+// RemainingQuantity : 아직 체결되지 않은 수량. 시장가 매수처럼 수량이 정해지지 않은 주문은 0
+func (o Order) RemainingQuantity() float64 {
+	if o.Closed() || o.Quantity <= o.ExecutedQuantity {
+		return 0
+	}
+	return o.Quantity - o.ExecutedQuantity
+}
+
+// ExecutionAlgo : 주문 피드 뒤에서 주문 하나(부모)를 여러 자식 주문으로 나눠 실행하는 방식
+type ExecutionAlgo string
+
+const (
+	ExecutionTWAP    ExecutionAlgo = "twap"    // Duration 동안 같은 간격으로 Slices 번 나눠 시장가
+	ExecutionIceberg ExecutionAlgo = "iceberg" // Slices 번 나눠 지정가, 앞 조각이 끝나면 다음 조각
+	ExecutionChase   ExecutionAlgo = "chase"   // 최우선 호가에 지정가, 호가가 움직이면 다시 내고 Timeout 뒤 최유리 주문
+)
+
+// Execution : 부모 주문의 실행 방식. 부모 주문은 평소처럼 매수는 Price(KRW 금액), 매도는 Quantity 로 냅니다.
+type Execution struct {
+	Algo     ExecutionAlgo `json:"algo"`
+	Slices   int           `json:"slices,omitempty"`   // twap/iceberg 조각 수
+	Duration time.Duration `json:"duration,omitempty"` // twap 전체 실행 기간
+	Limit    float64       `json:"limit,omitempty"`    // iceberg 지정가. 0 이면 조각마다 최우선 호가
+	Interval time.Duration `json:"interval,omitempty"` // chase 재호가 간격
+	Timeout  time.Duration `json:"timeout,omitempty"`  // iceberg/chase 최대 실행 기간. 0 이면 제한 없음 (chase 는 이후 최유리 주문)
+}
+
+type OrderEventType string
+
+// 주문 생애주기 이벤트: wait → trade(부분 체결, 여러 번) → done/cancel
+const (
+	OrderEventNew      OrderEventType = "new"      // 거래소가 주문을 접수
+	OrderEventTrade    OrderEventType = "trade"    // 체결 발생 (Fill 에 이번 체결)
+	OrderEventDone     OrderEventType = "done"     // 전량 체결
+	OrderEventCanceled OrderEventType = "canceled" // 취소 (일부 체결 후 취소 포함)
+)
+
+// OrderEvent : 주문 상태가 바뀔 때마다 전달되는 이벤트. Order 는 이벤트 시점의 누적 상태
+type OrderEvent struct {
+	Type  OrderEventType `json:"type"`
+	Order Order          `json:"order"`
+	Fill  *Fill          `json:"fill,omitempty"`
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type ParamType string
+
+const (
+	ParamTypeFloat ParamType = "float"
+	ParamTypeInt   ParamType = "int"
+)
+
+// ParamSpec : 전략이 외부에서 조정 가능하다고 선언한 파라미터와 그 범위
+type ParamSpec struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Type        ParamType `json:"type"`
+	Default     float64   `json:"default"`
+	Min         float64   `json:"min"`
+	Max         float64   `json:"max"`
+	Step        float64   `json:"step"` // 그리드 탐색 간격. 0 이면 int 는 1, float 는 Min/Max 두 값
+}
+
+// Values : Min 부터 Max 까지 Step 간격의 값들 (그리드 탐색용)
+func (p ParamSpec) Values() []float64 {
+	step := p.Step
+	if step <= 0 && p.Type == ParamTypeInt {
+		step = 1
+	}
+	if step <= 0 || p.Max <= p.Min {
+		if p.Max <= p.Min {
+			return []float64{p.Min}
+		}
+		return []float64{p.Min, p.Max}
+	}
+
+	n := int(math.Floor((p.Max-p.Min)/step+1e-9)) + 1
+	values := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		values = append(values, p.normalize(p.Min+float64(i)*step))
+	}
+	return values
+}
+
+// Validate : 값이 범위 안이고, int 타입이면 정수인지 확인
+func (p ParamSpec) Validate(v float64) error {
+	if math.IsNaN(v) || v < p.Min || v > p.Max {
+		return fmt.Errorf("param %s=%v out of range [%v, %v]", p.Name, v, p.Min, p.Max)
+	}
+	if p.Type == ParamTypeInt && v != math.Trunc(v) {
+		return fmt.Errorf("param %s=%v must be an integer", p.Name, v)
+	}
+	return nil
+}
+
+func (p ParamSpec) normalize(v float64) float64 {
+	if p.Type == ParamTypeInt {
+		return math.Round(v)
+	}
+	return math.Round(v*1e9) / 1e9 // 0.1 단위 누적 오차 제거
+}
+
+// ParamSet : 파라미터 이름 -> 값
+type ParamSet map[string]float64
+
+// DefaultParams : 스펙의 기본값들로 만든 ParamSet
+func DefaultParams(specs []ParamSpec) ParamSet {
+	set := make(ParamSet, len(specs))
+	for _, spec := range specs {
+		set[spec.Name] = spec.Default
+	}
+	return set
+}
+
+// Merge : base 위에 p 를 덮어쓴 새 ParamSet
+func (p ParamSet) Merge(base ParamSet) ParamSet {
+	out := make(ParamSet, len(base)+len(p))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range p {
+		out[k] = v
+	}
+	return out
+}
+
+// String : 이름순으로 정렬된 "a=1 b=2" 형태
+func (p ParamSet) String() string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%g", k, p[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ApplyParams : specs 기준으로 set 을 검증하고, 알 수 없는 이름이 있으면 에러
+func ApplyParams(specs []ParamSpec, set ParamSet, apply func(name string, v float64)) error {
+	byName := make(map[string]ParamSpec, len(specs))
+	for _, spec := range specs {
+		byName[spec.Name] = spec
+	}
+	for name, v := range set {
+		spec, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("unknown param: %s", name)
+		}
+		if err := spec.Validate(v); err != nil {
+			return err
+		}
+	}
+	for name, v := range set {
+		apply(name, v)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"raccoon/interfaces"
+	"raccoon/model"
+)
+
+// PaperPrefix : 모의투자 알림 앞에 붙는 표시
+const PaperPrefix = "[모의투자] "
+
+// PaperNotifier : 모든 알림에 모의투자 표시를 붙이는 Notifier 래퍼
+type PaperNotifier struct {
+	notifier interfaces.Notifier
+}
+
+func NewPaperNotifier(notifier interfaces.Notifier) *PaperNotifier {
+	return &PaperNotifier{notifier: notifier}
+}
+
+func (p *PaperNotifier) SendNotification(message string) error {
+	return p.notifier.SendNotification(PaperPrefix + message)
+}
+
+// OrderNotifier : 실패한 주문(요청 그대로)도 모의투자 주문으로 표시
+func (p *PaperNotifier) OrderNotifier(order model.Order, err error) {
+	order.Paper = true
+	p.notifier.OrderNotifier(order, err)
+}
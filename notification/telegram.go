@@ -38,7 +38,7 @@ func (t *TelegramNotifier) SendNotification(message string) error {
 
 func (t *TelegramNotifier) OrderNotifier(order model.Order, err error) {
 	if err != nil {
-		message := fmt.Sprintf("주문 실행 실패:\n종목: %s\n오류: %v", order.Pair, err)
+		message := fmt.Sprintf("%s주문 실행 실패:\n종목: %s%s\n오류: %v", paperTag(order), order.Pair, strategyLine(order), err)
 		if sendErr := t.SendNotification(message); sendErr != nil {
 			log.Printf("텔레그램 알림 전송 실패: %v\n", sendErr)
 		}
@@ -52,10 +52,34 @@ func (t *TelegramNotifier) OrderNotifier(order model.Order, err error) {
 		default:
 			action = "주문"
 		}
-		message := fmt.Sprintf("주문 체결 성공:\n종목: %s\n동작: %s\n가격: %.2f\n수량: %.8f",
-			order.Pair, action, order.Price, order.Quantity)
+		message := fmt.Sprintf("%s주문 체결 성공:\n종목: %s%s\n동작: %s\n가격: %.2f\n수량: %.8f%s",
+			paperTag(order), order.Pair, strategyLine(order), action, order.Price, order.Quantity, profitLine(order))
 		if sendErr := t.SendNotification(message); sendErr != nil {
 			log.Printf("텔레그램 알림 전송 실패: %v\n", sendErr)
 		}
 	}
 }
+
+// strategyLine : 여러 전략을 함께 돌릴 때 어느 전략의 주문인지 표시
+func strategyLine(order model.Order) string {
+	if order.Strategy == "" {
+		return ""
+	}
+	return "\n전략: " + order.Strategy
+}
+
+// profitLine : 매도 체결의 실현 손익 (포트폴리오 회계가 채운 경우)
+func profitLine(order model.Order) string {
+	if order.Side != model.SideTypeSell || order.ProfitValue == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n실현 손익: %.2f KRW (%.2f%%)", order.ProfitValue, order.Profit*100)
+}
+
+// paperTag : 모의투자 주문은 실제 주문과 헷갈리지 않도록 제목에 표시
+func paperTag(order model.Order) string {
+	if !order.Paper {
+		return ""
+	}
+	return PaperPrefix
+}
@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"raccoon/utils/log"
+)
+
+// CommandHandler : 텔레그램 명령을 처리하고 답장할 메시지를 반환합니다. 빈 문자열이면 답장하지 않습니다.
+// command 는 "/" 와 "@봇이름" 을 뗀 소문자 (예: "/halt@raccoon_bot 점검" → "halt", ["점검"])
+type CommandHandler func(command string, args []string) string
+
+// commandListener : 명령을 받을 수 있는 Notifier
+type commandListener interface {
+	ListenCommands(handler CommandHandler, stop <-chan struct{})
+}
+
+const commandPollTimeout = 30 * time.Second
+
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Date int64  `json:"date"`
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// ListenCommands : getUpdates 롱 폴링으로 ChatID 채팅에서 온 명령만 handler 로 넘기고 답장합니다. stop 이 닫힐 때까지 블록됩니다.
+// 시작 전에 쌓여 있던 메시지는 무시합니다. (재시작 직후 예전 /flatten 이 다시 실행되지 않도록)
+func (t *TelegramNotifier) ListenCommands(handler CommandHandler, stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	started := time.Now().Unix()
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := t.getUpdates(ctx, offset)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("[Telegram] getUpdates failed: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			msg := u.Message
+			if msg == nil || msg.Date < started || strconv.FormatInt(msg.Chat.ID, 10) != t.ChatID {
+				continue
+			}
+			command, args, ok := parseCommand(msg.Text)
+			if !ok {
+				continue
+			}
+			log.Infof("[Telegram] command /%s %v", command, args)
+			if reply := handler(command, args); reply != "" {
+				if err := t.SendNotification(reply); err != nil {
+					log.Errorf("[Telegram] reply failed: %v", err)
+				}
+			}
+		}
+	}
+}
+
+func (t *TelegramNotifier) getUpdates(ctx context.Context, offset int64) ([]telegramUpdate, error) {
+	params := url.Values{}
+	params.Set("timeout", strconv.Itoa(int(commandPollTimeout.Seconds())))
+	params.Set("offset", strconv.FormatInt(offset, 10))
+	params.Set("allowed_updates", `["message"]`)
+	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?%s", t.BotToken, params.Encode())
+
+	ctx, cancel := context.WithTimeout(ctx, commandPollTimeout+10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OK          bool             `json:"ok"`
+		Description string           `json:"description"`
+		Result      []telegramUpdate `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if !body.OK {
+		return nil, fmt.Errorf("telegram: %s", body.Description)
+	}
+	return body.Result, nil
+}
+
+// parseCommand : "/halt@bot 사유" → ("halt", ["사유"])
+func parseCommand(text string) (string, []string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil, false
+	}
+	command := strings.TrimPrefix(fields[0], "/")
+	if i := strings.Index(command, "@"); i >= 0 {
+		command = command[:i]
+	}
+	return strings.ToLower(command), fields[1:], command != ""
+}
+
+// ListenCommands : 감싼 Notifier 가 명령을 받을 수 있으면 그대로 전달. 답장에도 모의투자 표시를 붙임
+func (p *PaperNotifier) ListenCommands(handler CommandHandler, stop <-chan struct{}) {
+	listener, ok := p.notifier.(commandListener)
+	if !ok {
+		<-stop
+		return
+	}
+	listener.ListenCommands(func(command string, args []string) string {
+		if reply := handler(command, args); reply != "" {
+			return PaperPrefix + reply
+		}
+		return ""
+	}, stop)
+}
@@ -0,0 +1,325 @@
+package oms
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"raccoon/exchange"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+// DefaultPollInterval : private 스트림이 끊겨도 미체결 주문 상태를 따라가도록 조회하는 주기
+const DefaultPollInterval = 10 * time.Second
+
+// maxClosedOrders : 종료된 주문은 최근 것만 보관
+const maxClosedOrders = 1000
+
+// Manager : 거래소에 접수된 주문을 wait → trade → done/cancel 까지 추적하는 주문 관리자.
+//   - Track 으로 등록한 주문과 private 스트림(OnExecution)으로 들어온 주문을 관리
+//   - 미체결 주문은 Poll 로 거래소에 조회해 스트림을 놓쳐도 상태를 맞춤
+//   - 부분 체결은 Fills 에 누적하고, 상태가 바뀔 때마다 구독자에게 model.OrderEvent 를 전달
+//   - 전략은 interfaces.OrderManager 로 주문 상태를 조회
+type Manager struct {
+	broker interfaces.Broker
+
+	mu       sync.RWMutex
+	orders   map[string]*model.Order // key=ExchangeID
+	closed   []string                // 종료된 주문 (종료 순서), 오래된 것부터 정리
+	handlers []func(event model.OrderEvent)
+
+	// dispatchMu : 상태 갱신과 이벤트 전달을 한 번에 묶어 Poll 과 OnExecution 이 동시에 들어와도
+	// 같은 주문의 이벤트가 갱신 순서대로 전달되게 함 (trade 보다 done 이 먼저 가지 않도록)
+	dispatchMu sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewManager(broker interfaces.Broker) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		broker: broker,
+		orders: make(map[string]*model.Order),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Subscribe : 주문 이벤트를 받을 핸들러를 등록합니다. 핸들러는 상태를 갱신한 고루틴에서 순서대로 호출됩니다.
+// 핸들러 안에서 Track/OnExecution 을 다시 호출하면 안 됩니다.
+func (m *Manager) Subscribe(handler func(event model.OrderEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Track : 거래소가 접수한 주문(REST 응답)을 등록합니다. 이미 알고 있는 주문이면 상태만 갱신합니다.
+func (m *Manager) Track(order model.Order) {
+	if order.ExchangeID == "" {
+		return
+	}
+	m.dispatchMu.Lock()
+	defer m.dispatchMu.Unlock()
+	m.dispatch(m.apply(order))
+}
+
+// OnExecution : private 스트림(myOrder) 메시지로 주문 상태를 갱신합니다.
+func (m *Manager) OnExecution(msg model.UpbitMyOrderMessage) {
+	m.Track(exchange.MyOrderToOrder(msg))
+}
+
+// Start : interval 마다 미체결 주문을 조회합니다. interval 이 0 이하면 DefaultPollInterval
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+				m.Poll()
+			}
+		}
+	}()
+}
+
+func (m *Manager) Stop() {
+	m.cancel()
+}
+
+// Poll : 미체결 주문을 거래소에 하나씩 조회해 상태를 맞춥니다.
+func (m *Manager) Poll() {
+	for _, open := range m.OpenOrders("") {
+		order, err := m.broker.Order(open.Pair, open.ExchangeID, false)
+		if err != nil {
+			log.Warnf("[OMS] poll %s %s: %v", open.Pair, open.ExchangeID, err)
+			continue
+		}
+		if order.Pair == "" {
+			order.Pair = open.Pair
+		}
+		m.Track(order)
+	}
+}
+
+// Order : ExchangeID 로 주문의 현재 상태를 조회합니다.
+func (m *Manager) Order(exchangeID string) (model.Order, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	o, ok := m.orders[exchangeID]
+	if !ok {
+		return model.Order{}, false
+	}
+	return copyOrder(*o), true
+}
+
+// OpenOrders : 종료되지 않은 주문 (생성 순서). pair 가 비어있으면 전체
+func (m *Manager) OpenOrders(pair string) []model.Order {
+	return m.filter(func(o *model.Order) bool {
+		return !o.Closed() && (pair == "" || o.Pair == strings.ToUpper(pair))
+	})
+}
+
+// StrategyOrders : strategy 가 낸 주문 (종료된 주문 포함, 생성 순서)
+func (m *Manager) StrategyOrders(strategy string) []model.Order {
+	return m.filter(func(o *model.Order) bool {
+		return o.Strategy == strategy
+	})
+}
+
+func (m *Manager) filter(keep func(o *model.Order) bool) []model.Order {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]model.Order, 0)
+	for _, o := range m.orders {
+		if keep(o) {
+			result = append(result, copyOrder(*o))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// apply : 새 상태를 기존 주문에 합치고 발생한 이벤트를 반환합니다.
+func (m *Manager) apply(update model.Order) []model.OrderEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	update.Pair = strings.ToUpper(update.Pair)
+	current, known := m.orders[update.ExchangeID]
+	if !known {
+		current = &model.Order{
+			ExchangeID: update.ExchangeID,
+			Identifier: update.Identifier,
+			Pair:       update.Pair,
+			Side:       update.Side,
+			Type:       update.Type,
+			Status:     model.OrderStatusTypeWait,
+			Price:      update.Price,
+			Quantity:   update.Quantity,
+			Strategy:   update.Strategy,
+			Paper:      update.Paper,
+			FeedID:     update.FeedID,
+			CreatedAt:  update.CreatedAt,
+			UpdatedAt:  update.UpdatedAt,
+		}
+		if update.Status == model.OrderStatusTypeTrade {
+			current.Quantity = 0 // trade 메시지의 수량은 체결량
+		}
+		m.orders[update.ExchangeID] = current
+	} else if current.Closed() {
+		return nil // 종료 후 늦게 도착한 메시지
+	}
+
+	var events []model.OrderEvent
+	if !known {
+		events = append(events, model.OrderEvent{Type: model.OrderEventNew, Order: copyOrder(*current)})
+	}
+	if current.Strategy == "" {
+		current.Strategy = update.Strategy
+	}
+	if current.Identifier == "" {
+		current.Identifier = update.Identifier
+	}
+	if !update.UpdatedAt.IsZero() {
+		current.UpdatedAt = update.UpdatedAt
+	}
+
+	for _, fill := range newFills(current, update) {
+		fill := fill
+		current.Fills = append(current.Fills, fill)
+		current.ExecutedQuantity += fill.Quantity
+		current.PaidFee += fill.Fee
+		current.AvgPrice = averagePrice(current.Fills)
+		if !current.Closed() {
+			current.Status = model.OrderStatusTypeTrade
+		}
+		events = append(events, model.OrderEvent{Type: model.OrderEventTrade, Order: copyOrder(*current), Fill: &fill})
+	}
+
+	// 거래소가 알려준 누적값이 더 정확하면 그 값을 사용 (수수료는 체결별로 오지 않을 수 있음)
+	if update.ExecutedQuantity > current.ExecutedQuantity {
+		current.ExecutedQuantity = update.ExecutedQuantity
+	}
+	if update.AvgPrice > 0 && update.ExecutedQuantity >= current.ExecutedQuantity {
+		current.AvgPrice = update.AvgPrice
+	}
+	if update.PaidFee > current.PaidFee {
+		current.PaidFee = update.PaidFee
+	}
+
+	if update.Closed() {
+		current.Status = update.Status
+		eventType := model.OrderEventDone
+		if update.Status == model.OrderStatusTypeCanceled {
+			eventType = model.OrderEventCanceled
+		}
+		events = append(events, model.OrderEvent{Type: eventType, Order: copyOrder(*current)})
+		m.closed = append(m.closed, current.ExchangeID)
+		m.evictClosed()
+	}
+	return events
+}
+
+// newFills : update 에 담긴 체결 중 아직 기록하지 않은 것.
+// 체결 목록 없이 누적 체결량만 늘어난 경우(주문 목록 조회 등)는 늘어난 만큼을 체결 한 건으로 기록합니다.
+func newFills(current *model.Order, update model.Order) []model.Fill {
+	seen := make(map[string]bool, len(current.Fills))
+	for _, f := range current.Fills {
+		seen[f.TradeID] = true
+	}
+
+	var fills []model.Fill
+	executed := current.ExecutedQuantity
+	for _, f := range update.Fills {
+		if f.TradeID != "" && seen[f.TradeID] {
+			continue
+		}
+		fills = append(fills, f)
+		executed += f.Quantity
+	}
+
+	const epsilon = 1e-12
+	if delta := update.ExecutedQuantity - executed; delta > epsilon && update.AvgPrice > 0 {
+		// 누적 평균가와 지금까지의 체결 금액으로 빠진 체결의 가격을 역산
+		funds := update.AvgPrice*update.ExecutedQuantity - totalFunds(current.Fills) - totalFunds(fills)
+		fills = append(fills, model.Fill{
+			Price:    funds / delta,
+			Quantity: delta,
+			Funds:    funds,
+			Fee:      math.Max(update.PaidFee-current.PaidFee-totalFees(fills), 0),
+			Time:     update.UpdatedAt,
+		})
+	}
+	return fills
+}
+
+func (m *Manager) dispatch(events []model.OrderEvent) {
+	if len(events) == 0 {
+		return
+	}
+	m.mu.RLock()
+	handlers := m.handlers
+	m.mu.RUnlock()
+
+	for _, event := range events {
+		log.Infof("[OMS] %s %s %s %s executed=%.8f avg=%.2f",
+			event.Type, event.Order.Pair, event.Order.Side, event.Order.ExchangeID, event.Order.ExecutedQuantity, event.Order.AvgPrice)
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+}
+
+// evictClosed : 종료된 주문이 maxClosedOrders 를 넘으면 오래된 것부터 지움 (m.mu 를 잡은 상태에서 호출)
+func (m *Manager) evictClosed() {
+	for len(m.closed) > maxClosedOrders {
+		delete(m.orders, m.closed[0])
+		m.closed = m.closed[1:]
+	}
+}
+
+func averagePrice(fills []model.Fill) float64 {
+	var quantity float64
+	for _, f := range fills {
+		quantity += f.Quantity
+	}
+	if quantity == 0 {
+		return 0
+	}
+	return totalFunds(fills) / quantity
+}
+
+func totalFunds(fills []model.Fill) float64 {
+	var funds float64
+	for _, f := range fills {
+		funds += f.Funds
+	}
+	return funds
+}
+
+func totalFees(fills []model.Fill) float64 {
+	var fee float64
+	for _, f := range fills {
+		fee += f.Fee
+	}
+	return fee
+}
+
+func copyOrder(o model.Order) model.Order {
+	o.Fills = append([]model.Fill(nil), o.Fills...)
+	return o
+}
@@ -0,0 +1,52 @@
+package optimize
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"raccoon/interfaces"
+	"raccoon/model"
+)
+
+// memoFeeder : 같은 구간을 수백 번 조회하는 최적화에서 캔들을 한 번만 불러오도록 결과를 기억합니다.
+// 시행끼리 슬라이스를 공유하지 않도록 항상 복사본을 돌려줍니다.
+type memoFeeder struct {
+	interfaces.DataFeeder
+
+	mu      sync.Mutex
+	entries map[string]*memoEntry
+}
+
+type memoEntry struct {
+	once    sync.Once
+	candles []model.Candle
+	err     error
+}
+
+func newMemoFeeder(feeder interfaces.DataFeeder) *memoFeeder {
+	if m, ok := feeder.(*memoFeeder); ok {
+		return m
+	}
+	return &memoFeeder{DataFeeder: feeder, entries: make(map[string]*memoEntry)}
+}
+
+func (m *memoFeeder) CandlesByPeriod(pair, period string, start, end time.Time) ([]model.Candle, error) {
+	key := fmt.Sprintf("%s_%s_%d_%d", pair, period, start.UnixNano(), end.UnixNano())
+
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoEntry{}
+		m.entries[key] = entry
+	}
+	m.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.candles, entry.err = m.DataFeeder.CandlesByPeriod(pair, period, start, end)
+	})
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return append([]model.Candle(nil), entry.candles...), nil
+}
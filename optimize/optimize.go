@@ -0,0 +1,330 @@
+package optimize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"raccoon/backtest"
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/strategy"
+	"raccoon/utils/log"
+)
+
+var (
+	ErrNoParameters  = errors.New("optimize: strategy has no tunable parameters")
+	ErrUnknownMetric = errors.New("optimize: unknown metric")
+)
+
+// StrategyFactory : 시행마다 새 전략 인스턴스를 만듭니다. (전략은 상태를 가지므로 공유 불가)
+type StrategyFactory func(orderFeed *feed.OrderFeedSubscription) interfaces.TunableStrategy
+
+// FactoryByName : 전략 레지스트리에 등록된 이름으로 StrategyFactory 를 만듭니다.
+func FactoryByName(name string) (StrategyFactory, error) {
+	if _, err := strategy.NewTunable(name, nil); err != nil {
+		return nil, err
+	}
+	return func(orderFeed *feed.OrderFeedSubscription) interfaces.TunableStrategy {
+		tunable, _ := strategy.NewTunable(name, orderFeed)
+		return tunable
+	}, nil
+}
+
+type Metric string
+
+const (
+	MetricTotalReturn  Metric = "total_return"
+	MetricAnnualized   Metric = "annualized_return"
+	MetricSharpe       Metric = "sharpe"
+	MetricSortino      Metric = "sortino"
+	MetricCalmar       Metric = "calmar"
+	MetricProfitFactor Metric = "profit_factor"
+	MetricWinRate      Metric = "win_rate"
+	MetricMaxDrawdown  Metric = "max_drawdown" // 작을수록 좋음
+)
+
+// Score : 리포트에서 metric 값을 꺼내 "클수록 좋은" 점수로 변환
+func Score(report *backtest.Report, metric Metric) (float64, error) {
+	switch metric {
+	case MetricTotalReturn:
+		return report.TotalReturn, nil
+	case MetricAnnualized:
+		return report.AnnualizedReturn, nil
+	case MetricSharpe:
+		return report.Sharpe, nil
+	case MetricSortino:
+		return report.Sortino, nil
+	case MetricCalmar:
+		return report.Calmar, nil
+	case MetricProfitFactor:
+		return float64(report.ProfitFactor), nil
+	case MetricWinRate:
+		return report.WinRate, nil
+	case MetricMaxDrawdown:
+		return -report.MaxDrawdown, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownMetric, metric)
+	}
+}
+
+type Settings struct {
+	Pair       string
+	Start      time.Time
+	End        time.Time
+	InitialKRW float64
+	Feeder     interfaces.DataFeeder
+
+	Metric  Metric
+	Workers int // 0 이면 CPU 수
+	TopN    int // 0 이면 10
+
+	// Specs : 탐색할 파라미터 범위. 비어 있으면 전략이 선언한 Parameters() 전체
+	Specs []model.ParamSpec
+	// Fixed : 탐색하지 않고 고정할 값
+	Fixed model.ParamSet
+}
+
+// Trial : 파라미터 조합 하나에 대한 백테스트 결과
+type Trial struct {
+	Params model.ParamSet
+	Score  float64
+	Report *backtest.Report
+	Err    error
+}
+
+// SensitivityRow : 한 파라미터가 특정 값일 때의 점수 분포 (다른 파라미터는 모두 섞인 상태)
+type SensitivityRow struct {
+	Param  string
+	Value  float64
+	Trials int
+	Mean   float64
+	Best   float64
+	Worst  float64
+}
+
+type Result struct {
+	Metric      Metric
+	Trials      []Trial // 점수 내림차순, 실패한 시행은 맨 뒤
+	Top         []Trial
+	Sensitivity []SensitivityRow
+}
+
+type Optimizer struct {
+	factory  StrategyFactory
+	settings Settings
+}
+
+func NewOptimizer(factory StrategyFactory, settings Settings) *Optimizer {
+	if settings.Workers <= 0 {
+		settings.Workers = runtime.NumCPU()
+	}
+	if settings.TopN <= 0 {
+		settings.TopN = 10
+	}
+	if settings.Metric == "" {
+		settings.Metric = MetricSharpe
+	}
+	settings.Feeder = newMemoFeeder(settings.Feeder)
+	return &Optimizer{factory: factory, settings: settings}
+}
+
+// Specs : 탐색 대상 파라미터 범위
+func (o *Optimizer) Specs() []model.ParamSpec {
+	if len(o.settings.Specs) > 0 {
+		return o.settings.Specs
+	}
+	var specs []model.ParamSpec
+	for _, spec := range o.factory(feed.NewSyncOrderFeed()).Parameters() {
+		if _, fixed := o.settings.Fixed[spec.Name]; !fixed {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// GridSearch : 모든 파라미터 조합을 시험합니다.
+func (o *Optimizer) GridSearch() (*Result, error) {
+	specs := o.Specs()
+	if len(specs) == 0 {
+		return nil, ErrNoParameters
+	}
+	return o.Run(Grid(specs))
+}
+
+// RandomSearch : 그리드 위의 조합 n개를 무작위로 뽑아 시험합니다. 같은 seed 면 같은 조합
+func (o *Optimizer) RandomSearch(n int, seed int64) (*Result, error) {
+	specs := o.Specs()
+	if len(specs) == 0 {
+		return nil, ErrNoParameters
+	}
+	return o.Run(Random(specs, n, seed))
+}
+
+// Run : 주어진 조합들을 Workers 개씩 병렬로 백테스트하고 점수순으로 정렬합니다.
+func (o *Optimizer) Run(sets []model.ParamSet) (*Result, error) {
+	if _, err := Score(&backtest.Report{}, o.settings.Metric); err != nil {
+		return nil, err
+	}
+
+	trials := make([]Trial, len(sets))
+	jobs := make(chan int)
+	wg := new(sync.WaitGroup)
+	for w := 0; w < o.settings.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				trials[i] = o.runTrial(sets[i])
+			}
+		}()
+	}
+	for i := range sets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.SliceStable(trials, func(i, j int) bool {
+		if (trials[i].Err == nil) != (trials[j].Err == nil) {
+			return trials[i].Err == nil
+		}
+		return trials[i].Score > trials[j].Score
+	})
+
+	result := &Result{
+		Metric:      o.settings.Metric,
+		Trials:      trials,
+		Sensitivity: sensitivity(trials),
+	}
+	for _, t := range trials {
+		if t.Err != nil || len(result.Top) >= o.settings.TopN {
+			break
+		}
+		result.Top = append(result.Top, t)
+	}
+	return result, nil
+}
+
+// Evaluate : 파라미터 하나로 백테스트를 돌려 점수를 매깁니다.
+func (o *Optimizer) Evaluate(params model.ParamSet, start, end time.Time) Trial {
+	trial := Trial{Params: params}
+
+	engine := backtest.NewEngine(o.settings.Feeder, o.settings.InitialKRW)
+	strat := o.factory(engine.OrderFeed())
+	if err := strat.SetParameters(params.Merge(o.settings.Fixed)); err != nil {
+		trial.Err = err
+		return trial
+	}
+	engine.AddStrategy(o.settings.Pair, strat)
+
+	res, err := engine.Run(start, end)
+	if err != nil {
+		trial.Err = err
+		return trial
+	}
+	trial.Report = backtest.NewReport(res)
+	trial.Score, trial.Err = Score(trial.Report, o.settings.Metric)
+	if math.IsNaN(trial.Score) {
+		trial.Score = math.Inf(-1)
+	}
+	return trial
+}
+
+func (o *Optimizer) runTrial(params model.ParamSet) Trial {
+	trial := o.Evaluate(params, o.settings.Start, o.settings.End)
+	if trial.Err != nil {
+		log.Warnf("[Optimize] %s failed: %v", params, trial.Err)
+	}
+	return trial
+}
+
+// Grid : 스펙들의 모든 값 조합 (데카르트 곱)
+func Grid(specs []model.ParamSpec) []model.ParamSet {
+	sets := []model.ParamSet{{}}
+	for _, spec := range specs {
+		var next []model.ParamSet
+		for _, set := range sets {
+			for _, v := range spec.Values() {
+				next = append(next, set.Merge(model.ParamSet{spec.Name: v}))
+			}
+		}
+		sets = next
+	}
+	return sets
+}
+
+// Random : 각 파라미터의 그리드 값 중 하나씩 골라 n개의 조합을 만듭니다. (중복 제거, 최대 그리드 크기)
+func Random(specs []model.ParamSpec, n int, seed int64) []model.ParamSet {
+	rng := rand.New(rand.NewSource(seed))
+
+	total := 1
+	values := make([][]float64, len(specs))
+	for i, spec := range specs {
+		values[i] = spec.Values()
+		if total <= n {
+			total *= len(values[i])
+		}
+	}
+	if n > total {
+		n = total
+	}
+
+	seen := make(map[string]bool)
+	var sets []model.ParamSet
+	for len(sets) < n {
+		set := make(model.ParamSet, len(specs))
+		for i, spec := range specs {
+			set[spec.Name] = values[i][rng.Intn(len(values[i]))]
+		}
+		if key := set.String(); !seen[key] {
+			seen[key] = true
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+func sensitivity(trials []Trial) []SensitivityRow {
+	type bucket struct {
+		param string
+		value float64
+	}
+	rows := make(map[bucket]*SensitivityRow)
+	for _, t := range trials {
+		if t.Err != nil {
+			continue
+		}
+		for name, v := range t.Params {
+			b := bucket{name, v}
+			row, ok := rows[b]
+			if !ok {
+				row = &SensitivityRow{Param: name, Value: v, Best: t.Score, Worst: t.Score}
+				rows[b] = row
+			}
+			row.Trials++
+			row.Mean += t.Score
+			row.Best = math.Max(row.Best, t.Score)
+			row.Worst = math.Min(row.Worst, t.Score)
+		}
+	}
+
+	out := make([]SensitivityRow, 0, len(rows))
+	for _, row := range rows {
+		row.Mean /= float64(row.Trials)
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Param != out[j].Param {
+			return out[i].Param < out[j].Param
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
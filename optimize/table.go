@@ -0,0 +1,36 @@
+package optimize
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteTable : 상위 조합과 파라미터 민감도 표를 사람이 읽기 좋은 텍스트로 씁니다.
+func (r *Result) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Top %d by %s\n", len(r.Top), r.Metric)
+	fmt.Fprintln(tw, "rank\tscore\treturn\tmdd\tsharpe\ttrades\tparams")
+	for i, t := range r.Top {
+		fmt.Fprintf(tw, "%d\t%.4f\t%.2f%%\t%.2f%%\t%.2f\t%d\t%s\n",
+			i+1, t.Score, t.Report.TotalReturn*100, t.Report.MaxDrawdown*100, t.Report.Sharpe, t.Report.Trades, t.Params)
+	}
+
+	fmt.Fprintf(tw, "\nSensitivity (%s)\n", r.Metric)
+	fmt.Fprintln(tw, "param\tvalue\ttrials\tmean\tbest\tworst")
+	for _, row := range r.Sensitivity {
+		fmt.Fprintf(tw, "%s\t%g\t%d\t%.4f\t%.4f\t%.4f\n", row.Param, row.Value, row.Trials, row.Mean, row.Best, row.Worst)
+	}
+
+	var failed int
+	for _, t := range r.Trials {
+		if t.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(tw, "\n%d of %d trials failed\n", failed, len(r.Trials))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,267 @@
+package optimize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+	"time"
+
+	"raccoon/backtest"
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+var ErrInvalidWindow = errors.New("optimize: invalid walk-forward window")
+
+type WalkForwardMode string
+
+const (
+	// WalkForwardRolling : 고정 길이의 in-sample 구간이 앞으로 이동
+	WalkForwardRolling WalkForwardMode = "rolling"
+	// WalkForwardAnchored : in-sample 시작은 고정, 끝만 늘어남
+	WalkForwardAnchored WalkForwardMode = "anchored"
+)
+
+type WalkForwardSettings struct {
+	Mode        WalkForwardMode
+	InSample    time.Duration
+	OutOfSample time.Duration
+	Step        time.Duration // 0 이면 OutOfSample (구간이 겹치지 않게 이어붙임)
+
+	// RandomTrials > 0 이면 각 구간을 랜덤 탐색, 아니면 그리드 탐색
+	RandomTrials int
+	Seed         int64
+}
+
+// Window : walk-forward 한 구간. in-sample 에서 고른 파라미터로 바로 다음 out-of-sample 을 평가
+type Window struct {
+	Index    int
+	InStart  time.Time
+	InEnd    time.Time
+	OutStart time.Time
+	OutEnd   time.Time
+
+	Params      model.ParamSet
+	InScore     float64
+	OutScore    float64
+	InSample    *backtest.Report
+	OutOfSample *backtest.Report
+	Err         error
+}
+
+type WalkForwardResult struct {
+	Metric  Metric
+	Windows []Window
+
+	// OutOfSample : 각 out-of-sample 구간의 equity 와 주문을 복리로 이어붙인 결과
+	OutOfSample *backtest.Report
+
+	InSampleMeanScore    float64
+	OutOfSampleMeanScore float64
+	// Efficiency : out-of-sample 연환산 수익률 / in-sample 연환산 수익률 평균 (1에 가까울수록 과최적화가 적음)
+	Efficiency float64
+}
+
+// Windows : 전체 기간 [start, end]를 walk-forward 구간들로 나눕니다.
+func (wf WalkForwardSettings) Windows(start, end time.Time) ([]Window, error) {
+	if wf.InSample <= 0 || wf.OutOfSample <= 0 {
+		return nil, fmt.Errorf("%w: in-sample/out-of-sample must be positive", ErrInvalidWindow)
+	}
+	step := wf.Step
+	if step <= 0 {
+		step = wf.OutOfSample
+	}
+
+	var windows []Window
+	for k := 0; ; k++ {
+		offset := time.Duration(k) * step
+		w := Window{Index: k, InStart: start.Add(offset), InEnd: start.Add(offset + wf.InSample)}
+		if wf.Mode == WalkForwardAnchored {
+			w.InStart = start
+		}
+		w.OutStart = w.InEnd
+		w.OutEnd = w.OutStart.Add(wf.OutOfSample)
+		if !w.OutStart.Before(end) {
+			break
+		}
+		if w.OutEnd.After(end) {
+			w.OutEnd = end
+		}
+		windows = append(windows, w)
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("%w: period %v ~ %v is shorter than in-sample %v", ErrInvalidWindow, start, end, wf.InSample)
+	}
+	return windows, nil
+}
+
+// WalkForward : 구간마다 in-sample 최적화 -> out-of-sample 평가를 반복하고 결과를 이어붙입니다.
+func (o *Optimizer) WalkForward(wf WalkForwardSettings) (*WalkForwardResult, error) {
+	windows, err := wf.Windows(o.settings.Start, o.settings.End)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WalkForwardResult{Metric: o.settings.Metric}
+	for _, w := range windows {
+		sub := &Optimizer{factory: o.factory, settings: o.settings}
+		sub.settings.Start, sub.settings.End = w.InStart, w.InEnd
+
+		var opt *Result
+		if wf.RandomTrials > 0 {
+			opt, err = sub.RandomSearch(wf.RandomTrials, wf.Seed+int64(w.Index))
+		} else {
+			opt, err = sub.GridSearch()
+		}
+		if err == nil && len(opt.Top) == 0 {
+			err = errors.New("optimize: every in-sample trial failed")
+		}
+		if err != nil {
+			w.Err = err
+			result.Windows = append(result.Windows, w)
+			log.Warnf("[WalkForward] window %d in-sample failed: %v", w.Index, err)
+			continue
+		}
+
+		best := opt.Top[0]
+		w.Params, w.InScore, w.InSample = best.Params, best.Score, best.Report
+
+		out := o.Evaluate(best.Params, w.OutStart, w.OutEnd)
+		w.OutScore, w.OutOfSample, w.Err = out.Score, out.Report, out.Err
+		result.Windows = append(result.Windows, w)
+		log.Infof("[WalkForward] window %d: in=%.4f out=%.4f params=%s", w.Index, w.InScore, w.OutScore, w.Params)
+	}
+
+	result.summarize(o.settings.InitialKRW)
+	return result, nil
+}
+
+func (r *WalkForwardResult) summarize(initialKRW float64) {
+	stitched := &backtest.Result{
+		InitialKRW: initialKRW,
+		Candles:    make(map[string][]model.Candle),
+	}
+	capital := initialKRW
+
+	var n int
+	var inAnnual, outAnnual float64
+	for _, w := range r.Windows {
+		if w.Err != nil || w.OutOfSample == nil {
+			continue
+		}
+		n++
+		r.InSampleMeanScore += w.InScore
+		r.OutOfSampleMeanScore += w.OutScore
+		inAnnual += w.InSample.AnnualizedReturn
+		outAnnual += w.OutOfSample.AnnualizedReturn
+
+		if stitched.Start.IsZero() {
+			stitched.Start = w.OutStart
+		}
+		stitched.End = w.OutEnd
+
+		// 각 구간은 같은 초기자금으로 돌렸으므로 직전 구간 종료 자산 기준으로 환산
+		scale := capital / w.OutOfSample.InitialKRW
+		for _, p := range w.OutOfSample.EquityCurve {
+			stitched.Equity = append(stitched.Equity, backtest.EquityPoint{
+				Time:   p.Time,
+				Equity: p.Equity * scale,
+				Cash:   p.Cash * scale,
+			})
+		}
+		capital = w.OutOfSample.FinalEquity * scale
+
+		source := w.OutOfSample.Source()
+		stitched.FeeRate = source.FeeRate
+		stitched.Rejected += source.Rejected
+		stitched.Orders = append(stitched.Orders, windowOrders(source, w.OutEnd, scale)...)
+		for pair, candles := range source.Candles {
+			stitched.Candles[pair] = append(stitched.Candles[pair], candles...)
+		}
+	}
+	if n == 0 {
+		return
+	}
+
+	r.InSampleMeanScore /= float64(n)
+	r.OutOfSampleMeanScore /= float64(n)
+	if inAnnual != 0 {
+		r.Efficiency = outAnnual / inAnnual
+	}
+	stitched.FinalEquity = capital
+	r.OutOfSample = backtest.NewReport(stitched)
+}
+
+// windowOrders : 구간의 체결 주문을 이어붙인 자산 규모(scale)로 환산합니다.
+// 다음 구간은 현금으로 다시 시작하므로, 끝까지 들고 있던 포지션은 구간 마지막 종가로 정리한 매도로 봅니다.
+func windowOrders(result *backtest.Result, end time.Time, scale float64) []model.Order {
+	orders := make([]model.Order, 0, len(result.Orders))
+	holding := make(map[string]float64)
+	var pairs []string
+	for _, order := range result.Orders {
+		order.Quantity *= scale
+		orders = append(orders, order)
+
+		if _, ok := holding[order.Pair]; !ok {
+			pairs = append(pairs, order.Pair)
+		}
+		if order.Side == model.SideTypeBuy {
+			holding[order.Pair] += order.Quantity
+		} else {
+			holding[order.Pair] = math.Max(0, holding[order.Pair]-order.Quantity)
+		}
+	}
+
+	for _, pair := range pairs {
+		candles := result.Candles[pair]
+		if holding[pair] <= 1e-12 || len(candles) == 0 {
+			continue
+		}
+		orders = append(orders, model.Order{
+			Pair:      pair,
+			Side:      model.SideTypeSell,
+			Type:      model.OrderTypeMarket,
+			Status:    model.OrderStatusTypeDone,
+			Price:     candles[len(candles)-1].Close,
+			Quantity:  holding[pair],
+			CreatedAt: end,
+			UpdatedAt: end,
+		})
+	}
+	return orders
+}
+
+// WriteTable : 구간별 in-sample / out-of-sample 성과 비교표
+func (r *WalkForwardResult) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Walk-forward by %s\n", r.Metric)
+	fmt.Fprintln(tw, "#\tin-sample\tout-of-sample\tin score\tout score\tin return\tout return\tparams")
+	for _, win := range r.Windows {
+		period := func(a, b time.Time) string {
+			return a.Format("2006-01-02 15:04") + "~" + b.Format("2006-01-02 15:04")
+		}
+		if win.Err != nil {
+			fmt.Fprintf(tw, "%d\t%s\t%s\terror: %v\n", win.Index, period(win.InStart, win.InEnd), period(win.OutStart, win.OutEnd), win.Err)
+			continue
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%.4f\t%.4f\t%.2f%%\t%.2f%%\t%s\n",
+			win.Index, period(win.InStart, win.InEnd), period(win.OutStart, win.OutEnd),
+			win.InScore, win.OutScore, win.InSample.TotalReturn*100, win.OutOfSample.TotalReturn*100, win.Params)
+	}
+
+	fmt.Fprintf(tw, "\nmean in-sample score\t%.4f\n", r.InSampleMeanScore)
+	fmt.Fprintf(tw, "mean out-of-sample score\t%.4f\n", r.OutOfSampleMeanScore)
+	fmt.Fprintf(tw, "degradation\t%.4f\n", r.InSampleMeanScore-r.OutOfSampleMeanScore)
+	fmt.Fprintf(tw, "walk-forward efficiency\t%.2f\n", r.Efficiency)
+	if r.OutOfSample != nil {
+		fmt.Fprintf(tw, "stitched out-of-sample return\t%.2f%%\n", r.OutOfSample.TotalReturn*100)
+		fmt.Fprintf(tw, "stitched out-of-sample max drawdown\t%.2f%%\n", r.OutOfSample.MaxDrawdown*100)
+		for _, b := range r.OutOfSample.Benchmarks {
+			fmt.Fprintf(tw, "buy & hold %s\t%.2f%%\n", b.Pair, b.TotalReturn*100)
+		}
+	}
+	return tw.Flush()
+}
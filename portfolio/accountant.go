@@ -0,0 +1,409 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"raccoon/allocation"
+	"raccoon/exchange"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+// CostMethod : 매도 시 매입 원가를 정하는 방식
+type CostMethod string
+
+const (
+	CostAverage CostMethod = "average" // 이동평균 단가 (업비트 avg_buy_price 와 같은 방식)
+	CostFIFO    CostMethod = "fifo"    // 먼저 산 수량부터 매도한 것으로 계산
+)
+
+// epsilon : 이보다 작은 수량은 0 으로 봄 (부동소수 오차)
+const epsilon = 1e-12
+
+// ParseCostMethod : 비어있으면 CostAverage
+func ParseCostMethod(s string) (CostMethod, error) {
+	switch CostMethod(strings.ToLower(strings.TrimSpace(s))) {
+	case "", CostAverage:
+		return CostAverage, nil
+	case CostFIFO:
+		return CostFIFO, nil
+	default:
+		return "", fmt.Errorf("unknown cost method %q (supported: %s, %s)", s, CostAverage, CostFIFO)
+	}
+}
+
+// quoter : LastQuote 를 제공하는 브로커(거래소)라면 미실현 손익 계산에 현재가를 사용 (없으면 마지막 체결가)
+type quoter interface {
+	LastQuote(pair string) (float64, error)
+}
+
+// Accountant : 체결된 주문으로 전략/종목별 실현·미실현 손익과 수수료를 계산하는 포트폴리오 회계.
+//   - Apply 로 체결된 주문을 반영하고, 매도 주문에는 Profit(수익률)/ProfitValue(실현 손익 KRW)를 채움
+//   - 매입 원가는 매수 수수료를 포함하고, 실현 손익은 매도 수수료를 뺀 금액
+//   - 거래소 잔고가 아니라 이 Accountant 를 거친 체결만 집계 (봇 밖에서 산 코인은 포함하지 않음)
+type Accountant struct {
+	FeeRate float64 // 주문에 수수료(PaidFee)가 없을 때 추정에 사용
+
+	method CostMethod
+	broker interfaces.Broker
+
+	mu         sync.Mutex
+	positions  map[positionKey]*position
+	lastPrices map[string]float64              // pair → 마지막 체결가
+	daily      map[string]map[string]*DailyPnL // 날짜(KST) → 전략 → 그날 실현 손익
+}
+
+type positionKey struct {
+	strategy string
+	pair     string
+}
+
+// lot : 매수 한 건 중 아직 팔지 않은 수량과 그 원가 (수수료 포함)
+type lot struct {
+	quantity float64
+	cost     float64
+}
+
+type position struct {
+	lots     []lot // CostFIFO: 매수 순서, CostAverage: 하나로 합침
+	realized float64
+	fees     float64
+	trades   int // 실현된 매도 수
+}
+
+// PositionStats : 전략/종목 하나의 보유 현황과 손익
+type PositionStats struct {
+	Strategy    string  `json:"strategy,omitempty"`
+	Pair        string  `json:"pair"`
+	Quantity    float64 `json:"quantity"`
+	AvgCost     float64 `json:"avg_cost"`   // 보유 수량의 평균 단가 (매수 수수료 포함)
+	CostBasis   float64 `json:"cost_basis"` // 보유 수량의 매입 원가
+	Price       float64 `json:"price"`      // 평가에 사용한 가격
+	MarketValue float64 `json:"market_value"`
+	Realized    float64 `json:"realized"`
+	Unrealized  float64 `json:"unrealized"`
+	Fees        float64 `json:"fees"`
+	Trades      int     `json:"trades"`
+}
+
+// Summary : 여러 포지션을 합친 손익 (KRW)
+type Summary struct {
+	Realized      float64 `json:"realized"`
+	Unrealized    float64 `json:"unrealized"`
+	Fees          float64 `json:"fees"`
+	PnL           float64 `json:"pnl"` // 실현 + 미실현
+	CostBasis     float64 `json:"cost_basis"`
+	MarketValue   float64 `json:"market_value"`
+	TodayRealized float64 `json:"today_realized"` // 오늘(KST) 실현 손익
+	Trades        int     `json:"trades"`
+}
+
+// DailyPnL : 하루(KST) 동안의 실현 손익
+type DailyPnL struct {
+	Date     string  `json:"date"` // 2006-01-02
+	Realized float64 `json:"realized"`
+	Fees     float64 `json:"fees"`
+	Trades   int     `json:"trades"`
+}
+
+// NewAccountant : broker 가 LastQuote 를 제공하면 미실현 손익을 현재가로 계산합니다. broker 는 nil 이어도 됩니다.
+func NewAccountant(method CostMethod, broker interfaces.Broker) *Accountant {
+	if method == "" {
+		method = CostAverage
+	}
+	return &Accountant{
+		FeeRate:    allocation.DefaultFeeRate,
+		method:     method,
+		broker:     broker,
+		positions:  make(map[positionKey]*position),
+		lastPrices: make(map[string]float64),
+		daily:      make(map[string]map[string]*DailyPnL),
+	}
+}
+
+func (a *Accountant) Method() CostMethod {
+	return a.method
+}
+
+// Apply : 체결된 주문을 반영하고, 매도 주문이면 실현 손익을 채워 반환합니다.
+// 체결 수량/가격은 ExecutedQuantity/AvgPrice 를 우선 사용하고, 없으면 Quantity/Price 를 사용합니다.
+func (a *Accountant) Apply(order model.Order) model.Order {
+	quantity, price, fee := a.execution(order)
+	if quantity <= epsilon || price <= 0 {
+		log.Warnf("[Portfolio] skip order without execution - Pair: %s, Side: %s, uuid: %s", order.Pair, order.Side, order.ExchangeID)
+		return order
+	}
+
+	pair := strings.ToUpper(order.Pair)
+	at := order.UpdatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lastPrices[pair] = price
+	pos := a.position(order.Strategy, pair)
+	pos.fees += fee
+	day := a.day(order.Strategy, at)
+	day.Fees += fee
+
+	switch order.Side {
+	case model.SideTypeBuy:
+		pos.buy(a.method, quantity, price*quantity+fee)
+	case model.SideTypeSell:
+		held := pos.quantity()
+		if held <= epsilon {
+			log.Warnf("[Portfolio] sell without tracked position - Strategy: %s, Pair: %s", order.Strategy, pair)
+			return order
+		}
+		if quantity > held {
+			// 봇 밖에서 산 수량까지 판 경우 추적한 수량만 실현
+			fee *= held / quantity
+			quantity = held
+		}
+		basis := pos.sell(quantity)
+		realized := price*quantity - fee - basis
+		pos.realized += realized
+		pos.trades++
+		day.Realized += realized
+		day.Trades++
+
+		order.ProfitValue = realized
+		if basis > 0 {
+			order.Profit = realized / basis
+		}
+	}
+	return order
+}
+
+// execution : 주문의 체결 수량, 평균 체결가, 수수료
+func (a *Accountant) execution(order model.Order) (quantity, price, fee float64) {
+	quantity, price = order.ExecutedQuantity, order.AvgPrice
+	if quantity <= 0 {
+		quantity = order.Quantity
+	}
+	if price <= 0 {
+		price = order.Price
+	}
+	fee = order.PaidFee
+	if fee <= 0 {
+		fee = price * quantity * a.FeeRate
+	}
+	return quantity, price, fee
+}
+
+// Position : 전략/종목 하나의 현황
+func (a *Accountant) Position(strategy, pair string) PositionStats {
+	pair = strings.ToUpper(pair)
+	quotes := a.quotes([]string{pair})
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pos, ok := a.positions[positionKey{strategy, pair}]
+	if !ok {
+		return PositionStats{Strategy: strategy, Pair: pair}
+	}
+	return a.stats(positionKey{strategy, pair}, pos, quotes)
+}
+
+// Positions : strategy 의 모든 종목 현황 (전략, 종목 순서). strategy 가 비어있으면 전체
+func (a *Accountant) Positions(strategy string) []PositionStats {
+	a.mu.Lock()
+	keys := make([]positionKey, 0, len(a.positions))
+	var pairs []string
+	for key := range a.positions {
+		if strategy == "" || key.strategy == strategy {
+			keys = append(keys, key)
+			pairs = append(pairs, key.pair)
+		}
+	}
+	a.mu.Unlock()
+
+	quotes := a.quotes(pairs)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].strategy != keys[j].strategy {
+			return keys[i].strategy < keys[j].strategy
+		}
+		return keys[i].pair < keys[j].pair
+	})
+	result := make([]PositionStats, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, a.stats(key, a.positions[key], quotes))
+	}
+	return result
+}
+
+// Summary : strategy 의 손익 합계. strategy 가 비어있으면 전체
+func (a *Accountant) Summary(strategy string) Summary {
+	var s Summary
+	for _, p := range a.Positions(strategy) {
+		s.Realized += p.Realized
+		s.Unrealized += p.Unrealized
+		s.Fees += p.Fees
+		s.CostBasis += p.CostBasis
+		s.MarketValue += p.MarketValue
+		s.Trades += p.Trades
+	}
+	s.PnL = s.Realized + s.Unrealized
+	s.TodayRealized = a.TodayRealized(strategy)
+	return s
+}
+
+// TodayRealized : strategy 의 오늘(KST) 실현 손익. strategy 가 비어있으면 전체. 현재가를 조회하지 않습니다.
+func (a *Accountant) TodayRealized(strategy string) float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var realized float64
+	today := time.Now().In(exchange.KSTLocation).Format(time.DateOnly)
+	for name, d := range a.daily[today] {
+		if strategy == "" || name == strategy {
+			realized += d.Realized
+		}
+	}
+	return realized
+}
+
+// Daily : strategy 의 날짜별 실현 손익 (날짜 순서). strategy 가 비어있으면 전체
+func (a *Accountant) Daily(strategy string) []DailyPnL {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := make([]DailyPnL, 0, len(a.daily))
+	for date, byStrategy := range a.daily {
+		total := DailyPnL{Date: date}
+		for name, d := range byStrategy {
+			if strategy != "" && name != strategy {
+				continue
+			}
+			total.Realized += d.Realized
+			total.Fees += d.Fees
+			total.Trades += d.Trades
+		}
+		result = append(result, total)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	return result
+}
+
+// stats : a.mu 를 잡은 상태에서 호출
+func (a *Accountant) stats(key positionKey, pos *position, quotes map[string]float64) PositionStats {
+	stats := PositionStats{
+		Strategy:  key.strategy,
+		Pair:      key.pair,
+		Quantity:  pos.quantity(),
+		CostBasis: pos.cost(),
+		Realized:  pos.realized,
+		Fees:      pos.fees,
+		Trades:    pos.trades,
+	}
+	if stats.Quantity <= epsilon {
+		stats.Quantity, stats.CostBasis = 0, 0
+		return stats
+	}
+	stats.AvgCost = stats.CostBasis / stats.Quantity
+	stats.Price = quotes[key.pair]
+	if stats.Price <= 0 {
+		stats.Price = a.lastPrices[key.pair]
+	}
+	stats.MarketValue = stats.Quantity * stats.Price
+	stats.Unrealized = stats.MarketValue - stats.CostBasis
+	return stats
+}
+
+// quotes : 현재가 조회 (거래소 호출이 있을 수 있으므로 a.mu 밖에서 호출)
+func (a *Accountant) quotes(pairs []string) map[string]float64 {
+	quotes := make(map[string]float64, len(pairs))
+	q, ok := a.broker.(quoter)
+	if !ok {
+		return quotes
+	}
+	for _, pair := range pairs {
+		if _, done := quotes[pair]; done {
+			continue
+		}
+		if price, err := q.LastQuote(pair); err == nil {
+			quotes[pair] = price
+		}
+	}
+	return quotes
+}
+
+func (a *Accountant) position(strategy, pair string) *position {
+	key := positionKey{strategy, pair}
+	pos, ok := a.positions[key]
+	if !ok {
+		pos = &position{}
+		a.positions[key] = pos
+	}
+	return pos
+}
+
+func (a *Accountant) day(strategy string, at time.Time) *DailyPnL {
+	date := at.In(exchange.KSTLocation).Format(time.DateOnly)
+	byStrategy, ok := a.daily[date]
+	if !ok {
+		byStrategy = make(map[string]*DailyPnL)
+		a.daily[date] = byStrategy
+	}
+	d, ok := byStrategy[strategy]
+	if !ok {
+		d = &DailyPnL{Date: date}
+		byStrategy[strategy] = d
+	}
+	return d
+}
+
+func (p *position) buy(method CostMethod, quantity, cost float64) {
+	if method == CostAverage && len(p.lots) > 0 {
+		p.lots[0].quantity += quantity
+		p.lots[0].cost += cost
+		return
+	}
+	p.lots = append(p.lots, lot{quantity: quantity, cost: cost})
+}
+
+// sell : quantity 만큼 앞의 lot 부터 덜어내고 그 원가를 반환합니다. (CostAverage 는 lot 이 하나)
+func (p *position) sell(quantity float64) float64 {
+	var basis float64
+	for quantity > epsilon && len(p.lots) > 0 {
+		l := &p.lots[0]
+		take := math.Min(quantity, l.quantity)
+		cost := l.cost * take / l.quantity
+		basis += cost
+		l.quantity -= take
+		l.cost -= cost
+		quantity -= take
+		if l.quantity <= epsilon {
+			p.lots = p.lots[1:]
+		}
+	}
+	return basis
+}
+
+func (p *position) quantity() float64 {
+	var q float64
+	for _, l := range p.lots {
+		q += l.quantity
+	}
+	return q
+}
+
+func (p *position) cost() float64 {
+	var c float64
+	for _, l := range p.lots {
+		c += l.cost
+	}
+	return c
+}
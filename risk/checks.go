@@ -0,0 +1,218 @@
+package risk
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/portfolio"
+	"raccoon/utils/tools"
+)
+
+// quoter : 매도 주문 금액과 보유 평가금액 계산에 사용
+type quoter interface {
+	LastQuote(pair string) (float64, error)
+}
+
+// orderValue : 주문 금액 (KRW). 시장가 매수는 Price 가 금액, 나머지는 수량 × 가격(없으면 현재가)
+// 금액을 알 수 없으면 0
+func orderValue(order model.Order, q quoter) float64 {
+	if order.Side == model.SideTypeBuy && order.Type == model.OrderTypePrice {
+		return order.Price
+	}
+	price := order.Price
+	if price <= 0 && q != nil {
+		price, _ = q.LastQuote(order.Pair)
+	}
+	return order.Quantity * price
+}
+
+func asQuoter(broker interfaces.Broker) quoter {
+	q, _ := broker.(quoter)
+	return q
+}
+
+// minOrderTotal : 거래소 최소 주문 금액보다 작은 주문을 거절 (어차피 거래소가 거절)
+type minOrderTotal struct {
+	feeder interfaces.DataFeeder
+}
+
+// MinOrderTotal : feeder 의 AssetInfo.MinPrice 보다 작은 주문을 거절합니다. MinPrice 를 모르면 통과
+func MinOrderTotal(feeder interfaces.DataFeeder) Check {
+	return &minOrderTotal{feeder: feeder}
+}
+
+func (c *minOrderTotal) Name() string { return "min_order_total" }
+
+func (c *minOrderTotal) Check(order model.Order) error {
+	minTotal := c.feeder.AssetsInfo(order.Pair).MinPrice
+	if minTotal <= 0 {
+		return nil
+	}
+	value := orderValue(order, c.feeder)
+	if value > 0 && value < minTotal {
+		return reject(c.Name(), "order total %.2f KRW is below the exchange minimum %.2f KRW", value, minTotal)
+	}
+	return nil
+}
+
+// maxPosition : 매수 후 종목 보유 평가금액이 한도를 넘으면 거절
+type maxPosition struct {
+	max    float64
+	broker interfaces.Broker
+}
+
+// MaxPositionValue : 매수 후 종목당 보유 평가금액이 max KRW 를 넘는 주문을 거절합니다.
+func MaxPositionValue(max float64, broker interfaces.Broker) Check {
+	return &maxPosition{max: max, broker: broker}
+}
+
+func (c *maxPosition) Name() string { return "max_position" }
+
+func (c *maxPosition) Check(order model.Order) error {
+	if order.Side != model.SideTypeBuy {
+		return nil
+	}
+	asset, _, avgBuyPrice, err := c.broker.Position(order.Pair)
+	if err != nil {
+		return nil
+	}
+	price := avgBuyPrice
+	if q := asQuoter(c.broker); q != nil {
+		if quote, err := q.LastQuote(order.Pair); err == nil && quote > 0 {
+			price = quote
+		}
+	}
+	held := asset * price
+	value := orderValue(order, asQuoter(c.broker))
+	if held+value > c.max {
+		return reject(c.Name(), "%s position would be %.2f KRW (held %.2f + order %.2f), limit %.2f KRW",
+			order.Pair, held+value, held, value, c.max)
+	}
+	return nil
+}
+
+// maxEquityRate : 주문 금액이 총자산의 일정 비율을 넘으면 거절
+type maxEquityRate struct {
+	rate   float64
+	broker interfaces.Broker
+}
+
+// MaxOrderEquityRate : 매수 금액이 총자산(KRW + 코인 평가금액)의 rate 를 넘는 주문을 거절합니다.
+func MaxOrderEquityRate(rate float64, broker interfaces.Broker) Check {
+	return &maxEquityRate{rate: rate, broker: broker}
+}
+
+func (c *maxEquityRate) Name() string { return "max_order_equity_rate" }
+
+func (c *maxEquityRate) Check(order model.Order) error {
+	if order.Side != model.SideTypeBuy {
+		return nil
+	}
+	equity, ok := c.equity()
+	if !ok || equity <= 0 {
+		return nil
+	}
+	value := orderValue(order, asQuoter(c.broker))
+	if value/equity > c.rate {
+		return reject(c.Name(), "order %.2f KRW is %.2f%% of equity %.2f KRW, limit %.2f%%",
+			value, value/equity*100, equity, c.rate*100)
+	}
+	return nil
+}
+
+func (c *maxEquityRate) equity() (float64, bool) {
+	account, err := c.broker.Account()
+	if err != nil {
+		return 0, false
+	}
+	q := asQuoter(c.broker)
+	var equity float64
+	for _, b := range account.Balances {
+		amount := b.Balance + b.Locked
+		if b.Currency == "KRW" {
+			equity += amount
+			continue
+		}
+		price := b.AvgBuyPrice
+		if q != nil {
+			if quote, err := q.LastQuote("KRW-" + strings.ToUpper(b.Currency)); err == nil && quote > 0 {
+				price = quote
+			}
+		}
+		equity += amount * price
+	}
+	return equity, true
+}
+
+// rateLimit : 최근 1분 동안 접수된 주문 수 제한
+type rateLimit struct {
+	max   int
+	clock tools.Clock
+
+	mu    sync.Mutex
+	times []time.Time // 접수 시각 (오래된 순)
+}
+
+// MaxOrdersPerMinute : 최근 1분 동안 접수된 주문이 max 건이면 다음 주문을 거절합니다. clock 이 nil 이면 실제 시각
+func MaxOrdersPerMinute(max int, clock tools.Clock) Check {
+	if clock == nil {
+		clock = tools.RealClock{}
+	}
+	return &rateLimit{max: max, clock: clock}
+}
+
+func (c *rateLimit) Name() string { return "max_orders_per_minute" }
+
+func (c *rateLimit) Check(order model.Order) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire()
+	if len(c.times) >= c.max {
+		return reject(c.Name(), "%d orders in the last minute, limit %d", len(c.times), c.max)
+	}
+	return nil
+}
+
+func (c *rateLimit) Record(order model.Order) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire()
+	c.times = append(c.times, c.clock.Now())
+}
+
+// expire : c.mu 를 잡은 상태에서 호출
+func (c *rateLimit) expire() {
+	cutoff := c.clock.Now().Add(-time.Minute)
+	i := 0
+	for i < len(c.times) && !c.times[i].After(cutoff) {
+		i++
+	}
+	c.times = c.times[i:]
+}
+
+// dailyLoss : 오늘 실현 손실이 한도에 닿으면 새 매수를 막음 (매도는 허용)
+type dailyLoss struct {
+	max        float64
+	accountant *portfolio.Accountant
+}
+
+// MaxDailyLoss : 오늘(KST) 실현 손실이 max KRW 이상이면 매수 주문을 거절합니다. 포지션 정리(매도)는 막지 않습니다.
+func MaxDailyLoss(max float64, accountant *portfolio.Accountant) Check {
+	return &dailyLoss{max: max, accountant: accountant}
+}
+
+func (c *dailyLoss) Name() string { return "max_daily_loss" }
+
+func (c *dailyLoss) Check(order model.Order) error {
+	if order.Side != model.SideTypeBuy {
+		return nil
+	}
+	today := c.accountant.TodayRealized("")
+	if -today >= c.max {
+		return reject(c.Name(), "realized loss today %.2f KRW reached the limit %.2f KRW", -today, c.max)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"raccoon/model"
+)
+
+// KillSwitchState : 킬 스위치 상태. 파일에 그대로 저장됩니다.
+type KillSwitchState struct {
+	Engaged bool      `json:"engaged"`
+	Reason  string    `json:"reason,omitempty"`
+	Source  string    `json:"source,omitempty"`  // 누가 켰는지/껐는지 (web, telegram, signal 등)
+	Flatten bool      `json:"flatten,omitempty"` // 켤 때 보유 코인을 모두 시장가 매도했는지
+	Time    time.Time `json:"time"`
+}
+
+// KillSwitch : 켜져 있으면 모든 새 주문을 거절하는 전역 중지 스위치.
+// path 가 있으면 상태를 바꿀 때마다 파일에 기록하므로, 켠 채로 재시작해도 주문이 막힌 상태로 시작합니다.
+type KillSwitch struct {
+	path string
+
+	mu    sync.RWMutex
+	state KillSwitchState
+}
+
+// OpenKillSwitch : path 에 저장된 상태를 읽습니다. 파일이 없으면 꺼진 상태, path 가 비어있으면 메모리에만 보관합니다.
+func OpenKillSwitch(path string) (*KillSwitch, error) {
+	k := &KillSwitch{path: path}
+	if path == "" {
+		return k, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kill switch: read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(raw, &k.state); err != nil {
+		return nil, fmt.Errorf("kill switch: %s: %w", path, err)
+	}
+	return k, nil
+}
+
+// Engage : 킬 스위치를 켭니다. 이미 켜져 있으면 사유만 갱신합니다.
+func (k *KillSwitch) Engage(source, reason string, flatten bool) error {
+	return k.set(KillSwitchState{Engaged: true, Reason: reason, Source: source, Flatten: flatten, Time: time.Now()})
+}
+
+// Release : 킬 스위치를 끄고 주문을 다시 허용합니다.
+func (k *KillSwitch) Release(source string) error {
+	return k.set(KillSwitchState{Source: source, Time: time.Now()})
+}
+
+func (k *KillSwitch) State() KillSwitchState {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.state
+}
+
+func (k *KillSwitch) Engaged() bool {
+	return k.State().Engaged
+}
+
+func (k *KillSwitch) Name() string { return "kill_switch" }
+
+func (k *KillSwitch) Check(order model.Order) error {
+	state := k.State()
+	if !state.Engaged {
+		return nil
+	}
+	return reject(k.Name(), "trading halted by %s since %s: %s",
+		state.Source, state.Time.Format(time.DateTime), state.Reason)
+}
+
+// set : 파일에 먼저 기록하고 성공하면 상태를 바꿈 (기록 실패 시 이전 상태 유지)
+func (k *KillSwitch) set(state KillSwitchState) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.save(state); err != nil {
+		return err
+	}
+	k.state = state
+	return nil
+}
+
+// save : 임시 파일에 쓴 뒤 rename 해서, 쓰는 도중 죽어도 이전 상태 파일이 남도록 함
+func (k *KillSwitch) save(state KillSwitchState) error {
+	if k.path == "" {
+		return nil
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(k.path), filepath.Base(k.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("kill switch: save %s: %w", k.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("kill switch: save %s: %w", k.path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("kill switch: save %s: %w", k.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), k.path)
+}
@@ -0,0 +1,119 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+var ErrRejected = errors.New("risk: order rejected")
+
+// Rejection : 리스크 검사에서 거절된 주문과 그 이유. errors.Is(err, ErrRejected) 로 구분합니다.
+type Rejection struct {
+	Check  string // 거절한 검사 이름 (예: max_position)
+	Reason string
+}
+
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("risk: %s: %s", r.Check, r.Reason)
+}
+
+func (r *Rejection) Unwrap() error {
+	return ErrRejected
+}
+
+func reject(check, format string, args ...any) *Rejection {
+	return &Rejection{Check: check, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Check : 주문을 거래소에 보내기 전에 하는 검사 하나. 거절하면 *Rejection 을 반환합니다.
+// 검사에 필요한 값을 조회하지 못하면 주문을 막지 않고 nil 을 반환합니다. (조회 실패로 손절 매도까지 막지 않도록)
+type Check interface {
+	Name() string
+	Check(order model.Order) error
+}
+
+// submissionRecorder : 거래소가 주문을 접수했을 때 기록이 필요한 검사 (주문 빈도 제한 등)
+type submissionRecorder interface {
+	Record(order model.Order)
+}
+
+// Manager : 주문 피드와 브로커 사이에서 주문마다 등록된 검사를 순서대로 실행하는 리스크 관리자.
+// 킬 스위치가 있으면 가장 먼저 확인하고, 첫 번째로 거절한 검사의 Rejection 을 반환합니다.
+type Manager struct {
+	mu         sync.RWMutex
+	killSwitch *KillSwitch
+	checks     []Check
+}
+
+func NewManager(checks ...Check) *Manager {
+	return &Manager{checks: checks}
+}
+
+// Add : 검사를 추가합니다. 먼저 추가한 검사부터 실행됩니다.
+func (m *Manager) Add(checks ...Check) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks = append(m.checks, checks...)
+}
+
+// SetKillSwitch : 켜져 있는 동안 모든 주문을 거절할 킬 스위치
+func (m *Manager) SetKillSwitch(k *KillSwitch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.killSwitch = k
+}
+
+// KillSwitch : 설정된 킬 스위치. 없으면 nil
+func (m *Manager) KillSwitch() *KillSwitch {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.killSwitch
+}
+
+// Checks : 등록된 검사 이름 (실행 순서, 킬 스위치 제외)
+func (m *Manager) Checks() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.checks))
+	for _, c := range m.checks {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// Validate : 주문이 모든 검사를 통과하면 nil
+func (m *Manager) Validate(order model.Order) error {
+	m.mu.RLock()
+	checks := m.checks
+	if m.killSwitch != nil {
+		checks = append([]Check{m.killSwitch}, checks...)
+	}
+	m.mu.RUnlock()
+
+	order.Pair = strings.ToUpper(order.Pair)
+	for _, c := range checks {
+		if err := c.Check(order); err != nil {
+			log.Warnf("[Risk] rejected - Strategy: %s, Pair: %s, Side: %s: %v", order.Strategy, order.Pair, order.Side, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Submitted : 거래소가 주문을 접수한 뒤 호출합니다.
+func (m *Manager) Submitted(order model.Order) {
+	m.mu.RLock()
+	checks := m.checks
+	m.mu.RUnlock()
+
+	for _, c := range checks {
+		if r, ok := c.(submissionRecorder); ok {
+			r.Record(order)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"raccoon/exits"
+)
+
+// SaveExitGroup : group 의 보호 청산을 list 로 통째로 바꿉니다. list 가 비어있으면 group 을 지웁니다. (exits.Store)
+func (s *Storage) SaveExitGroup(ctx context.Context, group string, list []exits.Exit) error {
+	err := s.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.tx.GetTx(ctx).ExecContext(ctx, `DELETE FROM exits WHERE grp = ?`, group); err != nil {
+			return err
+		}
+		for _, e := range list {
+			_, err := s.tx.GetTx(ctx).ExecContext(ctx, `INSERT INTO exits
+				(id, grp, strategy, pair, kind, quantity, entry, trig, high, triggered, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				e.ID, group, e.Strategy, e.Pair, string(e.Kind), e.Quantity, e.Entry, e.Trigger, e.High,
+				e.Triggered, toMillis(e.CreatedAt))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("storage: save exits %s: %w", group, err)
+	}
+	return nil
+}
+
+// Exits : 저장된 보호 청산 전체 (그룹, 종류 순서)
+func (s *Storage) Exits(ctx context.Context) ([]exits.Exit, error) {
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, `SELECT id, grp, strategy, pair, kind, quantity, entry, trig, high, triggered, created_at
+		FROM exits ORDER BY grp, kind`)
+	if err != nil {
+		return nil, fmt.Errorf("storage: exits: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]exits.Exit, 0)
+	for rows.Next() {
+		var e exits.Exit
+		var kind string
+		var ms int64
+		if err := rows.Scan(&e.ID, &e.Group, &e.Strategy, &e.Pair, &kind, &e.Quantity, &e.Entry, &e.Trigger, &e.High,
+			&e.Triggered, &ms); err != nil {
+			return nil, fmt.Errorf("storage: exits: %w", err)
+		}
+		e.Kind = exits.Kind(kind)
+		e.CreatedAt = fromMillis(ms)
+		list = append(list, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: exits: %w", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"raccoon/model"
+)
+
+// Trade : 저장된 체결 한 건과 그 체결이 속한 주문 정보
+type Trade struct {
+	model.Fill
+	OrderExchangeID string
+	Pair            string
+	Side            model.SideType
+	Strategy        string
+}
+
+const fillColumns = `order_exchange_id, COALESCE(trade_id, ''), pair, side, strategy, price, quantity, funds, fee, maker, time`
+
+// SaveFill : order 의 체결 한 건을 저장합니다. 같은 TradeID 의 체결이 이미 있으면 무시하고 false 를 반환합니다.
+func (s *Storage) SaveFill(ctx context.Context, order model.Order, fill model.Fill) (bool, error) {
+	if order.ExchangeID == "" {
+		return false, fmt.Errorf("storage: save fill: order has no exchange id")
+	}
+	result, err := s.tx.GetTx(ctx).ExecContext(ctx, `INSERT INTO fills (order_exchange_id, trade_id, pair, side, strategy, price, quantity, funds, fee, maker, time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (order_exchange_id, trade_id) DO NOTHING`,
+		order.ExchangeID, nullString(fill.TradeID), strings.ToUpper(order.Pair), string(order.Side), order.Strategy,
+		fill.Price, fill.Quantity, fill.Funds, fill.Fee, fill.Maker, toMillis(fill.Time))
+	if err != nil {
+		return false, fmt.Errorf("storage: save fill %s/%s: %w", order.ExchangeID, fill.TradeID, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("storage: save fill %s/%s: %w", order.ExchangeID, fill.TradeID, err)
+	}
+	return inserted > 0, nil
+}
+
+// OrderFills : 주문 하나의 체결 목록 (체결 순서)
+func (s *Storage) OrderFills(ctx context.Context, exchangeID string) ([]model.Fill, error) {
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, `SELECT `+fillColumns+` FROM fills WHERE order_exchange_id = ? ORDER BY time, id`, exchangeID)
+	if err != nil {
+		return nil, fmt.Errorf("storage: fills %s: %w", exchangeID, err)
+	}
+	trades, err := scanTrades(rows)
+	if err != nil {
+		return nil, fmt.Errorf("storage: fills %s: %w", exchangeID, err)
+	}
+	fills := make([]model.Fill, 0, len(trades))
+	for _, t := range trades {
+		fills = append(fills, t.Fill)
+	}
+	return fills, nil
+}
+
+// Trades : 조건에 맞는 체결 (체결 시각 순서)
+func (s *Storage) Trades(ctx context.Context, filter Filter) ([]Trade, error) {
+	query, args := filter.query(fillColumns, "fills", "time")
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: trades: %w", err)
+	}
+	trades, err := scanTrades(rows)
+	if err != nil {
+		return nil, fmt.Errorf("storage: trades: %w", err)
+	}
+	return trades, nil
+}
+
+func scanTrades(rows *sql.Rows) ([]Trade, error) {
+	defer rows.Close()
+	trades := make([]Trade, 0)
+	for rows.Next() {
+		var t Trade
+		var side string
+		var ms int64
+		err := rows.Scan(&t.OrderExchangeID, &t.TradeID, &t.Pair, &side, &t.Strategy,
+			&t.Price, &t.Quantity, &t.Funds, &t.Fee, &t.Maker, &ms)
+		if err != nil {
+			return nil, err
+		}
+		t.Side = model.SideType(side)
+		t.Time = fromMillis(ms)
+		trades = append(trades, t)
+	}
+	return trades, rows.Err()
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"raccoon/utils/log"
+)
+
+type migration struct {
+	version    int
+	name       string
+	statements []string
+}
+
+// migrations : 스키마 변경 이력. 이미 배포된 항목은 고치지 말고 새 버전을 뒤에 추가합니다.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create orders, fills, account snapshots and signals",
+		statements: []string{
+			`CREATE TABLE orders (
+				id                INTEGER PRIMARY KEY AUTOINCREMENT,
+				exchange_id       TEXT UNIQUE,
+				identifier        TEXT,
+				feed_id           INTEGER NOT NULL DEFAULT 0,
+				pair              TEXT NOT NULL,
+				side              TEXT NOT NULL,
+				type              TEXT NOT NULL,
+				status            TEXT NOT NULL,
+				price             REAL NOT NULL DEFAULT 0,
+				quantity          REAL NOT NULL DEFAULT 0,
+				executed_quantity REAL NOT NULL DEFAULT 0,
+				avg_price         REAL NOT NULL DEFAULT 0,
+				paid_fee          REAL NOT NULL DEFAULT 0,
+				strategy          TEXT NOT NULL DEFAULT '',
+				paper             INTEGER NOT NULL DEFAULT 0,
+				created_at        INTEGER NOT NULL DEFAULT 0,
+				updated_at        INTEGER NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX orders_pair_created_at ON orders (pair, created_at)`,
+			`CREATE INDEX orders_strategy_created_at ON orders (strategy, created_at)`,
+			`CREATE TABLE fills (
+				id                INTEGER PRIMARY KEY AUTOINCREMENT,
+				order_exchange_id TEXT NOT NULL,
+				trade_id          TEXT,
+				pair              TEXT NOT NULL,
+				side              TEXT NOT NULL,
+				strategy          TEXT NOT NULL DEFAULT '',
+				price             REAL NOT NULL,
+				quantity          REAL NOT NULL,
+				funds             REAL NOT NULL,
+				fee               REAL NOT NULL DEFAULT 0,
+				maker             INTEGER NOT NULL DEFAULT 0,
+				time              INTEGER NOT NULL DEFAULT 0,
+				UNIQUE (order_exchange_id, trade_id)
+			)`,
+			`CREATE INDEX fills_pair_time ON fills (pair, time)`,
+			`CREATE TABLE account_snapshots (
+				id     INTEGER PRIMARY KEY AUTOINCREMENT,
+				time   INTEGER NOT NULL,
+				krw    REAL NOT NULL,
+				equity REAL NOT NULL,
+				paper  INTEGER NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX account_snapshots_time ON account_snapshots (time)`,
+			`CREATE TABLE account_balances (
+				snapshot_id   INTEGER NOT NULL REFERENCES account_snapshots (id) ON DELETE CASCADE,
+				currency      TEXT NOT NULL,
+				balance       REAL NOT NULL,
+				locked        REAL NOT NULL,
+				avg_buy_price REAL NOT NULL,
+				price         REAL NOT NULL,
+				PRIMARY KEY (snapshot_id, currency)
+			)`,
+			`CREATE TABLE signals (
+				id       INTEGER PRIMARY KEY AUTOINCREMENT,
+				feed_id  INTEGER NOT NULL DEFAULT 0,
+				strategy TEXT NOT NULL DEFAULT '',
+				pair     TEXT NOT NULL,
+				side     TEXT NOT NULL,
+				type     TEXT NOT NULL,
+				price    REAL NOT NULL DEFAULT 0,
+				quantity REAL NOT NULL DEFAULT 0,
+				time     INTEGER NOT NULL
+			)`,
+			`CREATE INDEX signals_pair_time ON signals (pair, time)`,
+		},
+	},
+	{
+		version: 2,
+		name:    "create protective exits",
+		statements: []string{
+			`CREATE TABLE exits (
+				id         TEXT PRIMARY KEY,
+				grp        TEXT NOT NULL,
+				strategy   TEXT NOT NULL DEFAULT '',
+				pair       TEXT NOT NULL,
+				kind       TEXT NOT NULL,
+				quantity   REAL NOT NULL,
+				entry      REAL NOT NULL,
+				trig       REAL NOT NULL,
+				high       REAL NOT NULL DEFAULT 0,
+				triggered  INTEGER NOT NULL DEFAULT 0,
+				created_at INTEGER NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX exits_grp ON exits (grp)`,
+		},
+	},
+}
+
+// Migrate : 적용되지 않은 마이그레이션을 버전 순서대로 각각 한 트랜잭션으로 적용합니다.
+func (s *Storage) Migrate(ctx context.Context) error {
+	_, err := s.database.DbForJet.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("storage: migrate: %w", err)
+	}
+
+	current, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		err := s.Transaction(ctx, func(ctx context.Context) error {
+			for _, stmt := range m.statements {
+				if _, err := s.tx.GetTx(ctx).ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			_, err := s.tx.GetTx(ctx).ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				m.version, m.name, time.Now().UnixMilli())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("storage: migration %d (%s): %w", m.version, m.name, err)
+		}
+		log.Infof("[Storage] applied migration %d: %s", m.version, m.name)
+	}
+	return nil
+}
+
+// SchemaVersion : 마지막으로 적용된 마이그레이션 버전. 아무것도 적용되지 않았으면 0
+func (s *Storage) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := s.queryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`, nil, &version); err != nil {
+		return 0, fmt.Errorf("storage: schema version: %w", err)
+	}
+	return version, nil
+}
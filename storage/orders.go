@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"raccoon/model"
+)
+
+// Filter : 조회 조건. 비어있는 항목은 조건에서 빠집니다.
+type Filter struct {
+	Pair     string
+	Strategy string
+	From     time.Time // 포함
+	To       time.Time // 제외
+	Limit    int       // 0 이면 전체. 양수면 최근 것부터 Limit 개를 시간 순서로 반환
+}
+
+// where : timeColumn 기준의 WHERE 절과 인자
+func (f Filter) where(timeColumn string) (string, []any) {
+	var conds []string
+	var args []any
+	if f.Pair != "" {
+		conds = append(conds, "pair = ?")
+		args = append(args, strings.ToUpper(f.Pair))
+	}
+	if f.Strategy != "" {
+		conds = append(conds, "strategy = ?")
+		args = append(args, f.Strategy)
+	}
+	if !f.From.IsZero() {
+		conds = append(conds, timeColumn+" >= ?")
+		args = append(args, f.From.UnixMilli())
+	}
+	if !f.To.IsZero() {
+		conds = append(conds, timeColumn+" < ?")
+		args = append(args, f.To.UnixMilli())
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// query : columns 를 table 에서 Filter 조건으로 시간 순서대로 조회합니다.
+func (f Filter) query(columns, table, timeColumn string) (string, []any) {
+	where, args := f.where(timeColumn)
+	order := " ORDER BY " + timeColumn + ", id"
+	if f.Limit <= 0 {
+		return "SELECT " + columns + " FROM " + table + where + order, args
+	}
+	// 최근 Limit 개를 고른 뒤 시간 순서로
+	recent := "SELECT id FROM " + table + where + " ORDER BY " + timeColumn + " DESC, id DESC LIMIT ?"
+	return "SELECT " + columns + " FROM " + table + " WHERE id IN (" + recent + ")" + order, append(args, f.Limit)
+}
+
+const orderColumns = `id, COALESCE(exchange_id, ''), COALESCE(identifier, ''), feed_id, pair, side, type, status,
+	price, quantity, executed_quantity, avg_price, paid_fee, strategy, paper, created_at, updated_at`
+
+// SaveOrder : 주문의 최신 상태를 저장합니다. 같은 ExchangeID 가 있으면 갱신하고 저장된 행 번호를 반환합니다.
+// 체결 목록(Fills)은 SaveFill 로 따로 저장합니다.
+func (s *Storage) SaveOrder(ctx context.Context, order model.Order) (int64, error) {
+	createdAt := order.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	updatedAt := order.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+
+	var id int64
+	err := s.queryRow(ctx, `INSERT INTO orders (exchange_id, identifier, feed_id, pair, side, type, status,
+			price, quantity, executed_quantity, avg_price, paid_fee, strategy, paper, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (exchange_id) DO UPDATE SET
+			identifier = COALESCE(excluded.identifier, identifier),
+			feed_id = MAX(feed_id, excluded.feed_id),
+			status = excluded.status,
+			executed_quantity = excluded.executed_quantity,
+			avg_price = excluded.avg_price,
+			paid_fee = excluded.paid_fee,
+			strategy = CASE WHEN excluded.strategy = '' THEN strategy ELSE excluded.strategy END,
+			updated_at = excluded.updated_at
+		RETURNING id`,
+		[]any{
+			nullString(order.ExchangeID), nullString(order.Identifier), order.FeedID,
+			strings.ToUpper(order.Pair), string(order.Side), string(order.Type), string(order.Status),
+			order.Price, order.Quantity, order.ExecutedQuantity, order.AvgPrice, order.PaidFee,
+			order.Strategy, order.Paper, toMillis(createdAt), toMillis(updatedAt),
+		}, &id)
+	if err != nil {
+		return 0, fmt.Errorf("storage: save order %s: %w", order.ExchangeID, err)
+	}
+	return id, nil
+}
+
+// Order : ExchangeID 로 주문과 체결 목록을 조회합니다. 없으면 ErrNotFound
+func (s *Storage) Order(ctx context.Context, exchangeID string) (model.Order, error) {
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, `SELECT `+orderColumns+` FROM orders WHERE exchange_id = ?`, exchangeID)
+	if err != nil {
+		return model.Order{}, fmt.Errorf("storage: order %s: %w", exchangeID, err)
+	}
+	orders, err := scanOrders(rows)
+	if err != nil {
+		return model.Order{}, fmt.Errorf("storage: order %s: %w", exchangeID, err)
+	}
+	if len(orders) == 0 {
+		return model.Order{}, fmt.Errorf("%w: order %s", ErrNotFound, exchangeID)
+	}
+
+	order := orders[0]
+	fills, err := s.OrderFills(ctx, exchangeID)
+	if err != nil {
+		return model.Order{}, err
+	}
+	order.Fills = fills
+	return order, nil
+}
+
+// Orders : 조건에 맞는 주문 (생성 시각 순서). 체결 목록은 채우지 않습니다.
+func (s *Storage) Orders(ctx context.Context, filter Filter) ([]model.Order, error) {
+	query, args := filter.query(orderColumns, "orders", "created_at")
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: orders: %w", err)
+	}
+	orders, err := scanOrders(rows)
+	if err != nil {
+		return nil, fmt.Errorf("storage: orders: %w", err)
+	}
+	return orders, nil
+}
+
+func scanOrders(rows *sql.Rows) ([]model.Order, error) {
+	defer rows.Close()
+	orders := make([]model.Order, 0)
+	for rows.Next() {
+		var o model.Order
+		var side, typ, status string
+		var createdAt, updatedAt int64
+		err := rows.Scan(&o.ID, &o.ExchangeID, &o.Identifier, &o.FeedID, &o.Pair, &side, &typ, &status,
+			&o.Price, &o.Quantity, &o.ExecutedQuantity, &o.AvgPrice, &o.PaidFee, &o.Strategy, &o.Paper,
+			&createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		o.Side = model.SideType(side)
+		o.Type = model.OrderType(typ)
+		o.Status = model.OrderStatusType(status)
+		o.CreatedAt = fromMillis(createdAt)
+		o.UpdatedAt = fromMillis(updatedAt)
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/utils/log"
+)
+
+// DefaultSnapshotInterval : 계좌 스냅샷 주기
+const DefaultSnapshotInterval = 5 * time.Minute
+
+// quoter : LastQuote 를 제공하는 브로커(거래소)라면 평가금액 계산에 현재가를 사용 (없으면 평균 매수가)
+type quoter interface {
+	LastQuote(pair string) (float64, error)
+}
+
+// Recorder : 봇에서 일어나는 신호/주문/체결/잔고를 저장소에 기록합니다.
+//   - OnSignal : 주문 피드 구독자. 전략이 발행한 주문(신호)
+//   - OnOrderEvent : 주문 관리자(oms) 구독자. 주문 상태와 체결
+//   - Start : interval 마다 Broker.Account() 스냅샷
+//
+// 기록 실패는 로그만 남기고 매매를 막지 않습니다.
+type Recorder struct {
+	store  *Storage
+	broker interfaces.Broker
+	paper  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewRecorder(store *Storage, broker interfaces.Broker, paper bool) *Recorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Recorder{
+		store:  store,
+		broker: broker,
+		paper:  paper,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// OnSignal : 주문 피드로 발행된 주문을 신호로 기록합니다.
+func (r *Recorder) OnSignal(order model.Order) {
+	if err := r.store.SaveSignal(r.ctx, order); err != nil {
+		log.Errorf("[Storage] %v", err)
+	}
+}
+
+// OnOrderEvent : 주문의 최신 상태와 이번 체결을 한 트랜잭션으로 기록합니다.
+func (r *Recorder) OnOrderEvent(event model.OrderEvent) {
+	order := event.Order
+	order.Paper = order.Paper || r.paper
+	err := r.store.Transaction(r.ctx, func(ctx context.Context) error {
+		if _, err := r.store.SaveOrder(ctx, order); err != nil {
+			return err
+		}
+		if event.Fill != nil {
+			if _, err := r.store.SaveFill(ctx, order, *event.Fill); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("[Storage] %s %s: %v", event.Type, order.ExchangeID, err)
+	}
+}
+
+// Snapshot : 현재 잔고와 평가금액을 기록합니다.
+func (r *Recorder) Snapshot() error {
+	account, err := r.broker.Account()
+	if err != nil {
+		return fmt.Errorf("storage: snapshot: %w", err)
+	}
+
+	snapshot := AccountSnapshot{Time: time.Now(), Paper: r.paper}
+	for _, b := range account.Balances {
+		amount := b.Balance + b.Locked
+		price := 1.0
+		if b.Currency == "KRW" {
+			snapshot.KRW += amount
+		} else {
+			price = r.price(b)
+		}
+		snapshot.Equity += amount * price
+		snapshot.Balances = append(snapshot.Balances, SnapshotBalance{Balance: b, Price: price})
+	}
+	_, err = r.store.SaveAccountSnapshot(r.ctx, snapshot)
+	return err
+}
+
+func (r *Recorder) price(b model.Balance) float64 {
+	if q, ok := r.broker.(quoter); ok {
+		if price, err := q.LastQuote("KRW-" + strings.ToUpper(b.Currency)); err == nil && price > 0 {
+			return price
+		}
+	}
+	return b.AvgBuyPrice
+}
+
+// Start : 바로 한 번, 이후 interval 마다 계좌 스냅샷을 기록합니다. interval 이 0 이하면 DefaultSnapshotInterval
+func (r *Recorder) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := r.Snapshot(); err != nil {
+				log.Warnf("[Storage] %v", err)
+			}
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *Recorder) Stop() {
+	r.cancel()
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"raccoon/model"
+)
+
+// SaveSignal : 전략이 주문 피드에 발행한 주문(신호)을 저장합니다. 실행 여부와 관계없이 발행된 그대로 기록합니다.
+func (s *Storage) SaveSignal(ctx context.Context, signal model.Order) error {
+	at := signal.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	_, err := s.tx.GetTx(ctx).ExecContext(ctx, `INSERT INTO signals (feed_id, strategy, pair, side, type, price, quantity, time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		signal.FeedID, signal.Strategy, strings.ToUpper(signal.Pair), string(signal.Side), string(signal.Type),
+		signal.Price, signal.Quantity, toMillis(at))
+	if err != nil {
+		return fmt.Errorf("storage: save signal %s: %w", signal.Pair, err)
+	}
+	return nil
+}
+
+// Signals : 조건에 맞는 신호 (발행 시각 순서). Order.ID 는 신호의 행 번호
+func (s *Storage) Signals(ctx context.Context, filter Filter) ([]model.Order, error) {
+	query, args := filter.query("id, feed_id, strategy, pair, side, type, price, quantity, time", "signals", "time")
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: signals: %w", err)
+	}
+	defer rows.Close()
+
+	signals := make([]model.Order, 0)
+	for rows.Next() {
+		var o model.Order
+		var side, typ string
+		var ms int64
+		if err := rows.Scan(&o.ID, &o.FeedID, &o.Strategy, &o.Pair, &side, &typ, &o.Price, &o.Quantity, &ms); err != nil {
+			return nil, fmt.Errorf("storage: signals: %w", err)
+		}
+		o.Side = model.SideType(side)
+		o.Type = model.OrderType(typ)
+		o.CreatedAt = fromMillis(ms)
+		signals = append(signals, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: signals: %w", err)
+	}
+	return signals, nil
+}
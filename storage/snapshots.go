@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"raccoon/model"
+)
+
+// AccountSnapshot : 한 시점의 계좌 잔고와 평가금액
+type AccountSnapshot struct {
+	ID       int64
+	Time     time.Time
+	KRW      float64 // 주문 가능 + 주문 중 KRW
+	Equity   float64 // KRW + 코인 평가금액
+	Paper    bool
+	Balances []SnapshotBalance
+}
+
+// SnapshotBalance : 스냅샷 시점의 화폐 하나. Price 는 평가에 사용한 가격 (KRW 는 1)
+type SnapshotBalance struct {
+	model.Balance
+	Price float64
+}
+
+// SaveAccountSnapshot : 잔고 스냅샷을 저장하고 행 번호를 반환합니다.
+func (s *Storage) SaveAccountSnapshot(ctx context.Context, snapshot AccountSnapshot) (int64, error) {
+	var id int64
+	err := s.Transaction(ctx, func(ctx context.Context) error {
+		err := s.queryRow(ctx, `INSERT INTO account_snapshots (time, krw, equity, paper) VALUES (?, ?, ?, ?) RETURNING id`,
+			[]any{toMillis(snapshot.Time), snapshot.KRW, snapshot.Equity, snapshot.Paper}, &id)
+		if err != nil {
+			return err
+		}
+		for _, b := range snapshot.Balances {
+			_, err := s.tx.GetTx(ctx).ExecContext(ctx, `INSERT INTO account_balances
+				(snapshot_id, currency, balance, locked, avg_buy_price, price) VALUES (?, ?, ?, ?, ?, ?)`,
+				id, b.Currency, b.Balance.Balance, b.Locked, b.AvgBuyPrice, b.Price)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("storage: save account snapshot: %w", err)
+	}
+	return id, nil
+}
+
+// AccountSnapshots : 조건에 맞는 스냅샷 (시각 순서, 잔고 포함). Filter 의 Pair/Strategy 는 사용하지 않습니다.
+func (s *Storage) AccountSnapshots(ctx context.Context, filter Filter) ([]AccountSnapshot, error) {
+	filter.Pair, filter.Strategy = "", ""
+	query, args := filter.query("id, time, krw, equity, paper", "account_snapshots", "time")
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: account snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]AccountSnapshot, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var snap AccountSnapshot
+		var ms int64
+		if err := rows.Scan(&snap.ID, &ms, &snap.KRW, &snap.Equity, &snap.Paper); err != nil {
+			return nil, fmt.Errorf("storage: account snapshots: %w", err)
+		}
+		snap.Time = fromMillis(ms)
+		index[snap.ID] = len(snapshots)
+		snapshots = append(snapshots, snap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: account snapshots: %w", err)
+	}
+	rows.Close()
+	if len(snapshots) == 0 {
+		return snapshots, nil
+	}
+
+	balances, err := s.tx.GetTx(ctx).QueryContext(ctx, `SELECT snapshot_id, currency, balance, locked, avg_buy_price, price
+		FROM account_balances WHERE snapshot_id BETWEEN ? AND ? ORDER BY snapshot_id, currency`,
+		snapshots[0].ID, snapshots[len(snapshots)-1].ID)
+	if err != nil {
+		return nil, fmt.Errorf("storage: account balances: %w", err)
+	}
+	defer balances.Close()
+	for balances.Next() {
+		var id int64
+		var b SnapshotBalance
+		if err := balances.Scan(&id, &b.Currency, &b.Balance.Balance, &b.Locked, &b.AvgBuyPrice, &b.Price); err != nil {
+			return nil, fmt.Errorf("storage: account balances: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			snapshots[i].Balances = append(snapshots[i].Balances, b)
+		}
+	}
+	if err := balances.Err(); err != nil {
+		return nil, fmt.Errorf("storage: account balances: %w", err)
+	}
+	return snapshots, nil
+}
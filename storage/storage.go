@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"raccoon/utils/db"
+	"raccoon/utils/db/tx"
+)
+
+// DriverSQLite : 기본 저장소 드라이버. 별도 서버 없이 파일 하나로 동작
+const DriverSQLite = "sqlite3"
+
+var ErrNotFound = errors.New("storage: not found")
+
+// Storage : 주문, 체결, 계좌 스냅샷, 전략 신호, 보호 청산을 저장하고 조회하는 저장소.
+// 쓰기/조회 함수는 ctx 에 db.Transaction 의 트랜잭션이 있으면 그 안에서 실행됩니다.
+type Storage struct {
+	database *db.Database
+	tx       tx.TxExtension
+}
+
+// Open : path 의 SQLite 파일을 열고 스키마를 최신 버전으로 올립니다. 파일이 없으면 새로 만듭니다.
+// path 가 ":memory:" 면 메모리 DB (테스트용)
+func Open(path string) (*Storage, error) {
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_foreign_keys=on", path)
+	if path != ":memory:" {
+		dsn += "&_journal_mode=WAL"
+	}
+	sqlDB, err := sql.Open(DriverSQLite, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("storage: open %s: %w", path, err)
+	}
+	// SQLite 는 쓰기 잠금이 DB 하나라서 연결 하나로 직렬화 (메모리 DB 도 연결마다 따로 생기지 않음)
+	sqlDB.SetMaxOpenConns(1)
+
+	s := New(&db.Database{DbForJet: sqlDB})
+	if err := s.Migrate(context.Background()); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New : 이미 연결된 DB 로 저장소를 만듭니다. 스키마는 Migrate 로 올려야 합니다.
+func New(database *db.Database) *Storage {
+	return &Storage{
+		database: database,
+		tx:       tx.TxExtension{Database: database},
+	}
+}
+
+func (s *Storage) Close() error {
+	return s.database.DbForJet.Close()
+}
+
+// Transaction : block 안의 쓰기를 한 트랜잭션으로 묶습니다. block 에는 트랜잭션이 담긴 ctx 가 전달됩니다.
+func (s *Storage) Transaction(ctx context.Context, block func(ctx context.Context) error) error {
+	err, _ := db.Transaction(func(ctx context.Context) (error, struct{}) {
+		return block(ctx), struct{}{}
+	}).Run(ctx, s.database.DbForJet)
+	return err
+}
+
+// queryRow : 첫 행을 dest 에 읽습니다. 행이 없으면 ErrNotFound
+func (s *Storage) queryRow(ctx context.Context, query string, args []any, dest ...any) error {
+	rows, err := s.tx.GetTx(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return ErrNotFound
+	}
+	return rows.Scan(dest...)
+}
+
+// toMillis / fromMillis : 시각은 유닉스 밀리초로 저장 (0 은 시각 없음)
+func toMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// nullString : 빈 문자열은 NULL 로 저장 (UNIQUE 제약에서 빈 값끼리 겹치지 않도록)
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
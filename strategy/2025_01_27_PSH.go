@@ -10,6 +10,13 @@ import (
 	"raccoon/utils/tools"
 )
 
+func init() {
+	Register("psh", "일봉 월별 추세 기반 매매",
+		func(orderFeed *feed.OrderFeedSubscription) interfaces.Strategy {
+			return NewPSHStrategy(orderFeed)
+		})
+}
+
 type PSHStrategy struct {
 	orderFeed *feed.OrderFeedSubscription
 }
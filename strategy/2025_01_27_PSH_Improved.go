@@ -8,36 +8,100 @@ import (
 	"raccoon/utils/log"
 )
 
+// 기본 파라미터. Parameters()/SetParameters()로 조정 가능
 const (
 	defaultTradeFraction = 0.5
 
-	stopLossPercent   = 0.08
-	takeProfitPercent = 0.4
+	defaultStopLossPercent   = 0.08
+	defaultTakeProfitPercent = 0.4
 
-	rsiOverboughtThreshold = 70.0
-	rsiOversoldThreshold   = 30.0
+	defaultRSIOverbought = 70.0
+	defaultRSIOversold   = 30.0
 
-	adxTrendThreshold = 25.0
+	defaultADXTrendThreshold = 25.0
 
 	minimumKRW = 5000.0
 )
 
+const (
+	ParamTradeFraction     = "trade_fraction"
+	ParamStopLossPercent   = "stop_loss_percent"
+	ParamTakeProfitPercent = "take_profit_percent"
+	ParamRSIOverbought     = "rsi_overbought"
+	ParamRSIOversold       = "rsi_oversold"
+	ParamADXTrendThreshold = "adx_trend_threshold"
+)
+
+func init() {
+	Register("improved_psh", "월별 추세 + RSI/ADX/볼린저 밴드 기반 분할 매매 (손절/익절 포함)",
+		func(orderFeed *feed.OrderFeedSubscription) interfaces.Strategy {
+			return NewImprovedPSHStrategy(orderFeed)
+		})
+}
+
 type ImprovedPSHStrategy struct {
 	orderFeed     *feed.OrderFeedSubscription
 	tradeFraction float64
+
+	stopLossPercent   float64
+	takeProfitPercent float64
+	rsiOverbought     float64
+	rsiOversold       float64
+	adxTrendThreshold float64
 }
 
 func NewImprovedPSHStrategy(orderFeed *feed.OrderFeedSubscription, tradeFraction ...float64) *ImprovedPSHStrategy {
-	if len(tradeFraction) == 0 {
-		return &ImprovedPSHStrategy{
-			orderFeed:     orderFeed,
-			tradeFraction: defaultTradeFraction,
-		}
+	s := &ImprovedPSHStrategy{
+		orderFeed:         orderFeed,
+		tradeFraction:     defaultTradeFraction,
+		stopLossPercent:   defaultStopLossPercent,
+		takeProfitPercent: defaultTakeProfitPercent,
+		rsiOverbought:     defaultRSIOverbought,
+		rsiOversold:       defaultRSIOversold,
+		adxTrendThreshold: defaultADXTrendThreshold,
 	}
-	return &ImprovedPSHStrategy{
-		orderFeed:     orderFeed,
-		tradeFraction: tradeFraction[0],
+	if len(tradeFraction) > 0 {
+		s.tradeFraction = tradeFraction[0]
 	}
+	return s
+}
+
+// Parameters : 최적화 가능한 파라미터와 탐색 범위
+func (s *ImprovedPSHStrategy) Parameters() []model.ParamSpec {
+	return []model.ParamSpec{
+		{Name: ParamTradeFraction, Description: "매수/매도 시 사용할 잔고 비율", Type: model.ParamTypeFloat,
+			Default: defaultTradeFraction, Min: 0.1, Max: 1, Step: 0.1},
+		{Name: ParamStopLossPercent, Description: "평균매입가 대비 손절 비율", Type: model.ParamTypeFloat,
+			Default: defaultStopLossPercent, Min: 0.02, Max: 0.2, Step: 0.02},
+		{Name: ParamTakeProfitPercent, Description: "평균매입가 대비 익절 비율", Type: model.ParamTypeFloat,
+			Default: defaultTakeProfitPercent, Min: 0.05, Max: 0.6, Step: 0.05},
+		{Name: ParamRSIOverbought, Description: "RSI 과매수 기준", Type: model.ParamTypeInt,
+			Default: defaultRSIOverbought, Min: 60, Max: 85, Step: 5},
+		{Name: ParamRSIOversold, Description: "RSI 과매도 기준", Type: model.ParamTypeInt,
+			Default: defaultRSIOversold, Min: 15, Max: 40, Step: 5},
+		{Name: ParamADXTrendThreshold, Description: "강한 추세로 볼 ADX 기준", Type: model.ParamTypeInt,
+			Default: defaultADXTrendThreshold, Min: 15, Max: 40, Step: 5},
+	}
+}
+
+// SetParameters : 주어진 파라미터만 변경. 범위를 벗어나거나 모르는 이름이면 아무것도 바꾸지 않고 에러
+func (s *ImprovedPSHStrategy) SetParameters(params model.ParamSet) error {
+	return model.ApplyParams(s.Parameters(), params, func(name string, v float64) {
+		switch name {
+		case ParamTradeFraction:
+			s.tradeFraction = v
+		case ParamStopLossPercent:
+			s.stopLossPercent = v
+		case ParamTakeProfitPercent:
+			s.takeProfitPercent = v
+		case ParamRSIOverbought:
+			s.rsiOverbought = v
+		case ParamRSIOversold:
+			s.rsiOversold = v
+		case ParamADXTrendThreshold:
+			s.adxTrendThreshold = v
+		}
+	})
 }
 
 func (s *ImprovedPSHStrategy) GetName() string {
@@ -220,16 +284,16 @@ func (s *ImprovedPSHStrategy) OnCandle(df *model.Dataframe, broker interfaces.Br
 	// --- 리스크 관리 (손절/익절) 조건 확인 --- //
 	// 보유 포지션이 있을 때만 적용
 	if coinAmt > 0 {
-		if stopLossTriggered(avgBuyPrice, closePrice) {
+		if s.stopLossTriggered(avgBuyPrice, closePrice) {
 			sellQuantity := coinAmt * s.tradeFraction
 			s.executeSell(df.Pair, sellQuantity)
-			log.Infof("[PSHStrategy] 손절 신호: 현재가격 %.2f <= 평균매입가 %.2f (%.2f%% 손실)", closePrice, avgBuyPrice, stopLossPercent*100)
+			log.Infof("[PSHStrategy] 손절 신호: 현재가격 %.2f <= 평균매입가 %.2f (%.2f%% 손실)", closePrice, avgBuyPrice, s.stopLossPercent*100)
 			return
 		}
-		if takeProfitTriggered(avgBuyPrice, closePrice) {
+		if s.takeProfitTriggered(avgBuyPrice, closePrice) {
 			sellQuantity := coinAmt * s.tradeFraction
 			s.executeSell(df.Pair, sellQuantity)
-			log.Infof("[PSHStrategy] 익절 신호: 현재가격 %.2f >= 평균매입가 %.2f (%.2f%% 상승)", closePrice, avgBuyPrice, takeProfitPercent*100)
+			log.Infof("[PSHStrategy] 익절 신호: 현재가격 %.2f >= 평균매입가 %.2f (%.2f%% 상승)", closePrice, avgBuyPrice, s.takeProfitPercent*100)
 			return
 		}
 	}
@@ -240,13 +304,13 @@ func (s *ImprovedPSHStrategy) OnCandle(df *model.Dataframe, broker interfaces.Br
 	normalSell := false
 
 	currentTrend := indicator.TrendType(int(trendSeries[i]))
-	strongTrend := adxSeries[i] >= adxTrendThreshold
+	strongTrend := adxSeries[i] >= s.adxTrendThreshold
 
 	switch currentTrend {
 	case indicator.Bullish:
 		if isGoldenCross(shortMA, longMA, i) &&
 			isMACDCrossover(macd, macdSignal, i) &&
-			rsiSeries[i] < rsiOverboughtThreshold &&
+			rsiSeries[i] < s.rsiOverbought &&
 			closePrice > openPrice &&
 			isIncreasingVolume(df.Volume, i) &&
 			strongTrend {
@@ -256,17 +320,17 @@ func (s *ImprovedPSHStrategy) OnCandle(df *model.Dataframe, broker interfaces.Br
 			strongBuy = true
 		}
 		if isDeathCross(shortMA, longMA, i) ||
-			rsiSeries[i] > rsiOverboughtThreshold ||
+			rsiSeries[i] > s.rsiOverbought ||
 			isMACDDeathCross(macd, macdSignal, i) {
 			normalSell = true
 		}
-		if isDecreasingHigh(df.High, i) && closePrice < openPrice && rsiSeries[i] > (rsiOversoldThreshold+10) {
+		if isDecreasingHigh(df.High, i) && closePrice < openPrice && rsiSeries[i] > (s.rsiOversold+10) {
 			normalSell = true
 		}
 
 	case indicator.Bearish:
 		if isDeathCross(shortMA, longMA, i) &&
-			rsiSeries[i] > rsiOverboughtThreshold &&
+			rsiSeries[i] > s.rsiOverbought &&
 			isMACDDeathCross(macd, macdSignal, i) {
 			normalSell = true
 		}
@@ -274,22 +338,22 @@ func (s *ImprovedPSHStrategy) OnCandle(df *model.Dataframe, broker interfaces.Br
 			strongSell = true
 		}
 		if isGoldenCross(shortMA, longMA, i) &&
-			rsiSeries[i] < rsiOversoldThreshold &&
+			rsiSeries[i] < s.rsiOversold &&
 			closePrice <= bbLow[i] {
 			normalBuy = true
 		}
-		if isIncreasingLow(df.Low, i) && closePrice > openPrice && rsiSeries[i] < rsiOversoldThreshold {
+		if isIncreasingLow(df.Low, i) && closePrice > openPrice && rsiSeries[i] < s.rsiOversold {
 			normalBuy = true
 		}
 
 	case indicator.Sideways:
-		if rsiSeries[i] < rsiOversoldThreshold && closePrice <= (bbLow[i]+(bbUp[i]-bbLow[i])/2) {
+		if rsiSeries[i] < s.rsiOversold && closePrice <= (bbLow[i]+(bbUp[i]-bbLow[i])/2) {
 			normalBuy = true
 			if williamsR[i] < -80 && stochRSI_K[i] < 20 {
 				strongBuy = true
 			}
 		}
-		if rsiSeries[i] > rsiOverboughtThreshold && closePrice >= (bbUp[i]-(bbUp[i]-bbLow[i])/2) {
+		if rsiSeries[i] > s.rsiOverbought && closePrice >= (bbUp[i]-(bbUp[i]-bbLow[i])/2) {
 			normalSell = true
 			if williamsR[i] > -20 && stochRSI_K[i] > 80 {
 				strongSell = true
@@ -410,11 +474,11 @@ func isMACDDeathCross(macd, signal []float64, idx int) bool {
 }
 
 // 손절 조건: 현재가격이 평균매입가 대비 일정 비율 이하인 경우
-func stopLossTriggered(avgBuyPrice, currentPrice float64) bool {
-	return currentPrice <= avgBuyPrice*(1-stopLossPercent)
+func (s *ImprovedPSHStrategy) stopLossTriggered(avgBuyPrice, currentPrice float64) bool {
+	return currentPrice <= avgBuyPrice*(1-s.stopLossPercent)
 }
 
 // 익절 조건: 현재가격이 평균매입가 대비 일정 비율 이상인 경우
-func takeProfitTriggered(avgBuyPrice, currentPrice float64) bool {
-	return currentPrice >= avgBuyPrice*(1+takeProfitPercent)
+func (s *ImprovedPSHStrategy) takeProfitTriggered(avgBuyPrice, currentPrice float64) bool {
+	return currentPrice >= avgBuyPrice*(1+s.takeProfitPercent)
 }
@@ -6,6 +6,7 @@ import (
 	"raccoon/model"
 	"raccoon/utils/log"
 	"raccoon/webserver"
+	"sync"
 	"time"
 )
 
@@ -15,6 +16,12 @@ type Controller struct {
 	Broker    interfaces.Broker
 	WebServer interfaces.WebServer
 	started   bool
+
+	highFrequency interfaces.HighFrequencyStrategy // Strategy 가 미완성 봉도 받는 경우
+	marketData    interfaces.MarketDataStrategy    // Strategy 가 실시간 호가/체결도 받는 경우
+
+	// 캔들 피드와 시세 피드가 다른 고루틴에서 들어오므로 전략 호출을 직렬화
+	mu sync.Mutex
 }
 
 func NewStrategyController(pair string, strategy interfaces.Strategy, broker interfaces.Broker) *Controller {
@@ -22,13 +29,27 @@ func NewStrategyController(pair string, strategy interfaces.Strategy, broker int
 		Pair:     pair,
 		Metadata: make(map[string]model.Series[float64]),
 	}
+	hf, _ := HighFrequency(strategy)
+	md, _ := MarketData(strategy)
 	return &Controller{
-		Strategy:  strategy,
-		Dataframe: dataframe,
-		Broker:    broker,
+		Strategy:      strategy,
+		Dataframe:     dataframe,
+		Broker:        broker,
+		highFrequency: hf,
+		marketData:    md,
 	}
 }
 
+// HighFrequency : 미완성 봉(OnPartialCandle)까지 전달해야 하는 전략인지
+func (c *Controller) HighFrequency() bool {
+	return c.highFrequency != nil
+}
+
+// MarketData : 실시간 호가/체결(OnBook, OnTrade)까지 전달해야 하는 전략인지
+func (c *Controller) MarketData() bool {
+	return c.marketData != nil
+}
+
 func (c *Controller) Start() {
 	c.started = true
 }
@@ -60,6 +81,9 @@ func (c *Controller) updateDataFrame(candle model.Candle) {
 }
 
 func (c *Controller) OnCandle(candle model.Candle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if len(c.Dataframe.Time) > 0 && candle.Time.Before(c.Dataframe.Time[len(c.Dataframe.Time)-1]) {
 		log.Errorf("late candle received: %#v", candle)
 		return
@@ -76,14 +100,71 @@ func (c *Controller) OnCandle(candle model.Candle) {
 
 			results, timestamp := makeChartIndicators(&sample, chartIndics)
 			if c.WebServer != nil && len(results) > 0 {
-				c.WebServer.OnIndicators(timestamp, results)
+				c.WebServer.OnIndicators(c.Dataframe.Pair, timestamp, results)
 			}
 		}
 	}
 }
 
+// OnPartialCandle : 진행 중인 봉을 HighFrequencyStrategy 에 전달합니다.
+// 완성된 봉 WarmupPeriod-1 개 뒤에 진행 중인 봉을 붙인 사본을 넘기며, Dataframe 자체는 바꾸지 않습니다.
 func (c *Controller) OnPartialCandle(candle model.Candle) {
-	//TODO 파셜 받았을떄 해야함
+	if c.highFrequency == nil || !c.started || candle.Time.IsZero() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.Dataframe.Time)
+	if n > 0 && !candle.Time.After(c.Dataframe.Time[n-1]) {
+		return // 이미 마감된 봉
+	}
+	warmup := c.Strategy.WarmupPeriod()
+	if n < warmup-1 {
+		return
+	}
+
+	view := partialView(c.Dataframe.Sample(max(warmup-1, 0)), candle)
+	c.highFrequency.OnPartialCandle(&view, c.Broker)
+}
+
+// OnBook : 최우선 호가를 MarketDataStrategy 에 전달합니다.
+func (c *Controller) OnBook(book model.Book) {
+	if c.marketData == nil || !c.started {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.marketData.OnBook(book, c.Broker)
+}
+
+// OnTrade : 거래소 체결을 MarketDataStrategy 에 전달합니다.
+func (c *Controller) OnTrade(trade model.TradePrint) {
+	if c.marketData == nil || !c.started {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.marketData.OnTrade(trade, c.Broker)
+}
+
+// partialView : sample 을 복사하고 마지막 행에 진행 중인 봉을 추가
+func partialView(sample model.Dataframe, candle model.Candle) model.Dataframe {
+	n := len(sample.Time)
+	view := model.Dataframe{
+		Pair:       sample.Pair,
+		Close:      append(append(make(model.Series[float64], 0, n+1), sample.Close...), candle.Close),
+		Open:       append(append(make(model.Series[float64], 0, n+1), sample.Open...), candle.Open),
+		High:       append(append(make(model.Series[float64], 0, n+1), sample.High...), candle.High),
+		Low:        append(append(make(model.Series[float64], 0, n+1), sample.Low...), candle.Low),
+		Volume:     append(append(make(model.Series[float64], 0, n+1), sample.Volume...), candle.Volume),
+		Time:       append(append(make([]time.Time, 0, n+1), sample.Time...), candle.Time),
+		LastUpdate: candle.Time,
+		Metadata:   make(map[string]model.Series[float64]),
+	}
+	for key, values := range sample.Metadata {
+		view.Metadata[key] = append(append(make(model.Series[float64], 0, n+1), values...), candle.Metadata[key])
+	}
+	return view
 }
 
 func makeChartIndicators(sample *model.Dataframe, chartIndics []indicator.ChartIndicator) ([]webserver.IndicatorValue, time.Time) {
@@ -0,0 +1,141 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/model"
+)
+
+var ErrUnknownStrategy = errors.New("unknown strategy")
+
+// Factory : 주문 피드를 받아 전략 인스턴스를 만듭니다.
+type Factory func(orderFeed *feed.OrderFeedSubscription) interfaces.Strategy
+
+// Definition : 레지스트리에 등록된 전략과 그 파라미터 스키마
+type Definition struct {
+	Name        string
+	Description string
+	Timeframe   string            // 기본 timeframe
+	Params      []model.ParamSpec // TunableStrategy 가 아니면 비어있음
+	New         Factory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Definition)
+)
+
+// Register : name 으로 전략을 등록합니다. 보통 전략 파일의 init() 에서 호출하며, 이름이 겹치면 panic
+func Register(name, description string, factory Factory) {
+	if name == "" || factory == nil {
+		panic("strategy: Register requires a name and a factory")
+	}
+
+	// 주문 피드 없이 한 번 만들어 기본 timeframe 과 파라미터 스키마를 기록
+	probe := factory(nil)
+	def := Definition{
+		Name:        name,
+		Description: description,
+		Timeframe:   probe.Timeframe(),
+		New:         factory,
+	}
+	if tunable, ok := probe.(interfaces.TunableStrategy); ok {
+		def.Params = tunable.Parameters()
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("strategy: Register called twice for " + name)
+	}
+	registry[name] = def
+}
+
+// Lookup : 이름으로 등록된 전략을 찾습니다.
+func Lookup(name string) (Definition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	def, ok := registry[name]
+	return def, ok
+}
+
+// Names : 등록된 전략 이름 (정렬)
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List : 등록된 전략 정의 (이름순)
+func List() []Definition {
+	names := Names()
+	defs := make([]Definition, 0, len(names))
+	for _, name := range names {
+		def, _ := Lookup(name)
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// New : 이름과 파라미터로 전략을 만듭니다. params 는 기본값 위에 덮어쓰며, 범위 밖이거나 모르는 이름이면 에러
+func New(name string, orderFeed *feed.OrderFeedSubscription, params model.ParamSet) (interfaces.Strategy, error) {
+	def, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownStrategy, name, strings.Join(Names(), ", "))
+	}
+
+	strat := def.New(orderFeed)
+	if len(params) == 0 {
+		return strat, nil
+	}
+	tunable, ok := strat.(interfaces.TunableStrategy)
+	if !ok {
+		return nil, fmt.Errorf("strategy %q has no parameters", name)
+	}
+	if err := tunable.SetParameters(params); err != nil {
+		return nil, err
+	}
+	return strat, nil
+}
+
+// NewTunable : 최적화/워크포워드처럼 파라미터를 바꿔가며 돌릴 전략을 만듭니다.
+func NewTunable(name string, orderFeed *feed.OrderFeedSubscription) (interfaces.TunableStrategy, error) {
+	strat, err := New(name, orderFeed, nil)
+	if err != nil {
+		return nil, err
+	}
+	tunable, ok := strat.(interfaces.TunableStrategy)
+	if !ok {
+		return nil, fmt.Errorf("strategy %q has no parameters", name)
+	}
+	return tunable, nil
+}
+
+// WriteRegistry : 등록된 전략과 기본 파라미터를 사람이 읽기 좋은 표로 출력합니다.
+func WriteRegistry(w io.Writer) error {
+	for _, def := range List() {
+		if _, err := fmt.Fprintf(w, "%s (timeframe=%s)\n  %s\n", def.Name, def.Timeframe, def.Description); err != nil {
+			return err
+		}
+		for _, p := range def.Params {
+			if _, err := fmt.Fprintf(w, "  - %-22s %-5s default=%-8v range=[%v, %v] %s\n",
+				p.Name, p.Type, p.Default, p.Min, p.Max, p.Description); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package strategy
+
+import "raccoon/interfaces"
+
+// timeframeStrategy : 전략의 기본 timeframe 대신 지정한 timeframe 으로 실행
+type timeframeStrategy struct {
+	interfaces.Strategy
+	timeframe string
+}
+
+// tunableTimeframeStrategy : TunableStrategy 를 감싸도 파라미터 조정이 가능하도록 유지
+type tunableTimeframeStrategy struct {
+	interfaces.TunableStrategy
+	timeframe string
+}
+
+// WithTimeframe : timeframe 이 비어있거나 기본값과 같으면 전략을 그대로 반환합니다.
+// 감싼 전략이 TunableStrategy 면 반환값도 TunableStrategy 입니다.
+func WithTimeframe(s interfaces.Strategy, timeframe string) interfaces.Strategy {
+	if timeframe == "" || timeframe == s.Timeframe() {
+		return s
+	}
+	if tunable, ok := s.(interfaces.TunableStrategy); ok {
+		return &tunableTimeframeStrategy{TunableStrategy: tunable, timeframe: timeframe}
+	}
+	return &timeframeStrategy{Strategy: s, timeframe: timeframe}
+}
+
+func (s *timeframeStrategy) Timeframe() string {
+	return s.timeframe
+}
+
+func (s *timeframeStrategy) Unwrap() interfaces.Strategy {
+	return s.Strategy
+}
+
+func (s *tunableTimeframeStrategy) Timeframe() string {
+	return s.timeframe
+}
+
+func (s *tunableTimeframeStrategy) Unwrap() interfaces.Strategy {
+	return s.TunableStrategy
+}
+
+// HighFrequency : s 가 HighFrequencyStrategy 면 반환합니다. WithTimeframe 등으로 감싼 전략은 풀어서 확인
+func HighFrequency(s interfaces.Strategy) (interfaces.HighFrequencyStrategy, bool) {
+	return unwrap[interfaces.HighFrequencyStrategy](s)
+}
+
+// OrderManaged : s 가 OrderManagedStrategy 면 반환합니다. 감싼 전략은 풀어서 확인
+func OrderManaged(s interfaces.Strategy) (interfaces.OrderManagedStrategy, bool) {
+	return unwrap[interfaces.OrderManagedStrategy](s)
+}
+
+// MarketData : s 가 MarketDataStrategy 면 반환합니다. 감싼 전략은 풀어서 확인
+func MarketData(s interfaces.Strategy) (interfaces.MarketDataStrategy, bool) {
+	return unwrap[interfaces.MarketDataStrategy](s)
+}
+
+// unwrap : s 또는 s 가 감싼 전략 중 T 를 구현한 첫 번째 전략
+func unwrap[T any](s interfaces.Strategy) (T, bool) {
+	for s != nil {
+		if t, ok := s.(T); ok {
+			return t, true
+		}
+		wrapper, ok := s.(interface{ Unwrap() interfaces.Strategy })
+		if !ok {
+			break
+		}
+		s = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
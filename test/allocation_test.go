@@ -0,0 +1,122 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/allocation"
+	"raccoon/consumer"
+	"raccoon/exchange"
+	"raccoon/model"
+)
+
+// TestAllocator_PairBudgets : 두 종목이 같은 KRW 잔고를 중복으로 쓰지 못하고, 매도 대금은 해당 종목 예산으로 돌아와야 함
+func TestAllocator_PairBudgets(t *testing.T) {
+	broker := exchange.NewBackTestBroker("KRW-XRP", 1_000_000)
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now, 1000, 1000, 1000, 1000))
+	broker.OnCandle(newBacktestCandle("KRW-BTC", now, 100_000, 100_000, 100_000, 100_000))
+
+	allocator := allocation.NewAllocator(broker, allocation.EqualWeights([]string{"KRW-XRP", "KRW-BTC"}))
+	allocator.Fund(1_000_000)
+	require.InDelta(t, 500_000, allocator.Available("KRW-XRP"), 1e-6)
+	require.InDelta(t, 500_000, allocator.Available("KRW-BTC"), 1e-6)
+
+	var executed []model.Order
+	var failed []error
+	xrp := consumer.NewOrderFeedConsumerBroker(allocator.Broker("KRW-XRP"))
+	xrp.AddOrderExecutedCallback(allocator.OnOrderExecuted)
+	xrp.AddOrderExecutedCallback(func(order model.Order, err error) {
+		if err != nil {
+			failed = append(failed, err)
+			return
+		}
+		executed = append(executed, order)
+	})
+
+	// XRP 예산 안의 매수는 통과
+	xrp.OnOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 400_000})
+	require.Len(t, executed, 1)
+	require.InDelta(t, 100_000, allocator.Available("KRW-XRP"), 1)
+
+	// 실제 잔고(60만원)는 충분해도 XRP 예산을 넘는 매수는 거절
+	xrp.OnOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 200_000})
+	require.Len(t, failed, 1)
+	require.True(t, errors.Is(failed[0], allocation.ErrAllocationExceeded))
+	require.InDelta(t, 100_000, allocator.Available("KRW-XRP"), 1)
+
+	// 전략이 보는 KRW는 종목 예산으로 제한
+	_, quote, _, err := allocator.Broker("KRW-XRP").Position("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, 100_000, quote, 1)
+	_, quote, _, err = allocator.Broker("KRW-BTC").Position("KRW-BTC")
+	require.NoError(t, err)
+	require.InDelta(t, 500_000, quote, 1e-6)
+
+	// BTC는 자기 예산 전부를 쓸 수 있음
+	_, err = allocator.Broker("KRW-BTC").CreateOrderMarket(model.SideTypeBuy, "KRW-BTC", 500_000)
+	require.NoError(t, err)
+	require.InDelta(t, 0, allocator.Available("KRW-BTC"), 1)
+
+	// 다른 종목 브로커로는 주문 불가
+	_, err = allocator.Broker("KRW-BTC").CreateOrderMarket(model.SideTypeBuy, "KRW-XRP", 10_000)
+	require.True(t, errors.Is(err, allocation.ErrUnknownPair))
+
+	// 매도 대금(수수료 차감)은 XRP 예산으로 복귀
+	coin, _, _, err := broker.Position("KRW-XRP")
+	require.NoError(t, err)
+	xrp.OnOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeSell, Type: model.OrderTypeMarket, Quantity: coin})
+	require.Len(t, executed, 2)
+	require.InDelta(t, 100_000+coin*1000*(1-allocator.FeeRate), allocator.Available("KRW-XRP"), 1)
+	require.InDelta(t, 0, allocator.Available("KRW-BTC"), 1)
+	require.LessOrEqual(t, allocator.Available("KRW-XRP")+allocator.Available("KRW-BTC"), broker.Cash()+1e-6)
+}
+
+// TestAllocator_SharedPairStrategies : 같은 종목을 거래하는 두 전략은 자기가 산 수량만 보고, 손익도 따로 집계되어야 함
+func TestAllocator_SharedPairStrategies(t *testing.T) {
+	broker := exchange.NewBackTestBroker("KRW-XRP", 1_000_000)
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now, 1000, 1000, 1000, 1000))
+
+	allocator := allocation.NewAllocator(broker, map[string]float64{"current": 3, "candidate": 1})
+	allocator.Fund(1_000_000)
+	require.InDelta(t, 750_000, allocator.Available("current"), 1e-6)
+	require.InDelta(t, 250_000, allocator.Available("candidate"), 1e-6)
+
+	current := consumer.NewOrderFeedConsumerBroker(allocator.Broker("current", "KRW-XRP"))
+	current.AddOrderExecutedCallback(allocator.OnOrderExecuted)
+	candidate := consumer.NewOrderFeedConsumerBroker(allocator.Broker("candidate", "KRW-XRP"))
+	candidate.AddOrderExecutedCallback(allocator.OnOrderExecuted)
+
+	current.OnOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 300_000, Strategy: "current"})
+	candidate.OnOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 100_000, Strategy: "candidate"})
+
+	coinCurrent, _, _, err := allocator.Broker("current", "KRW-XRP").Position("KRW-XRP")
+	require.NoError(t, err)
+	coinCandidate, _, avg, err := allocator.Broker("candidate", "KRW-XRP").Position("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, 300_000/1.0005/1000, coinCurrent, 1e-3)
+	require.InDelta(t, 100_000/1.0005/1000, coinCandidate, 1e-3)
+	require.InDelta(t, 1000, avg, 1e-6)
+
+	// 가격이 10% 오른 뒤 candidate 만 청산
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now.Add(time.Minute), 1100, 1100, 1100, 1100))
+	candidate.OnOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeSell, Type: model.OrderTypeMarket, Quantity: coinCandidate, Strategy: "candidate"})
+
+	coinCandidate, _, _, err = allocator.Broker("candidate", "KRW-XRP").Position("KRW-XRP")
+	require.NoError(t, err)
+	require.Zero(t, coinCandidate)
+	coinAfter, _, _, err := allocator.Broker("current", "KRW-XRP").Position("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, coinCurrent, coinAfter, 1e-9)
+
+	stats := allocator.Stats("candidate")
+	require.Equal(t, 2, stats.Trades)
+	require.InDelta(t, 250_000, stats.Funded, 1e-6)
+	require.InDelta(t, stats.Cash, stats.Equity, 1e-6)
+	require.Greater(t, stats.PnL, 9_000.0)
+	require.Less(t, stats.PnL, 10_000.0)
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/exchange"
+	"raccoon/model"
+)
+
+func newBacktestCandle(pair string, t time.Time, open, high, low, close float64) model.Candle {
+	return model.Candle{
+		Pair:     pair,
+		Time:     t,
+		Open:     open,
+		High:     high,
+		Low:      low,
+		Close:    close,
+		Volume:   1000,
+		Complete: true,
+	}
+}
+
+// TestBacktestBroker_MarketOrders : 시장가 매수/매도 시 수수료, 평균 매입가, 잔고가 Upbit과 같은 방식으로 계산되는지 검증
+func TestBacktestBroker_MarketOrders(t *testing.T) {
+	broker := exchange.NewBackTestBroker("KRW-XRP", 1_000_000)
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now, 1000, 1000, 1000, 1000))
+
+	// 10만원 시장가 매수 => 수수료를 제외한 금액(99950)이 주문금액
+	order, err := broker.CreateOrderMarket(model.SideTypeBuy, "KRW-XRP", 100_000)
+	require.NoError(t, err)
+	require.Equal(t, model.OrderStatusTypeDone, order.Status)
+	require.Equal(t, model.OrderTypePrice, order.Type)
+	require.InDelta(t, 99.95, order.Quantity, 1e-9)
+
+	coin, krw, avg, err := broker.Position("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, 99.95, coin, 1e-9)
+	require.InDelta(t, 1_000_000-99_950*(1+broker.FeeRate), krw, 1e-6)
+	require.InDelta(t, 1000, avg, 1e-9)
+
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now.Add(time.Minute), 1000, 1100, 1000, 1100))
+	order, err = broker.CreateOrderMarket(model.SideTypeSell, "KRW-XRP", coin)
+	require.NoError(t, err)
+	require.InDelta(t, 1100, order.Price, 1e-9)
+
+	coin, krw, avg, _ = broker.Position("KRW-XRP")
+	require.Zero(t, coin)
+	require.Zero(t, avg)
+	expected := 1_000_000 - 99_950*(1+broker.FeeRate) + 99.95*1100*(1-broker.FeeRate)
+	require.InDelta(t, expected, krw, 1e-6)
+	require.InDelta(t, expected, broker.Equity(), 1e-6)
+}
+
+// TestBacktestBroker_MinTotal : 최소 주문금액 미만 주문은 거절
+func TestBacktestBroker_MinTotal(t *testing.T) {
+	broker := exchange.NewBackTestBroker("KRW-XRP", 1_000_000)
+	broker.OnCandle(newBacktestCandle("KRW-XRP", time.Now(), 1000, 1000, 1000, 1000))
+
+	_, err := broker.CreateOrderMarket(model.SideTypeBuy, "KRW-XRP", 4000)
+	require.True(t, errors.Is(err, exchange.ErrBacktestMinTotal))
+
+	_, err = broker.CreateOrderLimit(model.SideTypeBuy, "KRW-XRP", 1, 900)
+	require.True(t, errors.Is(err, exchange.ErrBacktestMinTotal))
+}
+
+// TestBacktestBroker_LimitOrderLifecycle : 지정가 주문이 잔고를 묶고, 이후 캔들에서 체결/취소되는지 검증
+func TestBacktestBroker_LimitOrderLifecycle(t *testing.T) {
+	broker := exchange.NewBackTestBroker("KRW-XRP", 1_000_000)
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now, 1000, 1000, 1000, 1000))
+
+	buy, err := broker.CreateOrderLimit(model.SideTypeBuy, "KRW-XRP", 100, 950)
+	require.NoError(t, err)
+	require.Equal(t, model.OrderStatusTypeWait, buy.Status)
+
+	account, _ := broker.Account()
+	require.InDelta(t, 95_000*(1+broker.FeeRate), account.Balances[0].Locked, 1e-6)
+
+	open, _ := broker.OpenOrders("KRW-XRP", 10)
+	require.Len(t, open, 1)
+
+	// 저가가 지정가에 닿지 않으면 미체결
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now.Add(time.Minute), 1000, 1010, 960, 990))
+	got, _ := broker.Order("KRW-XRP", buy.ExchangeID, false)
+	require.Equal(t, model.OrderStatusTypeWait, got.Status)
+
+	// 저가가 지정가를 터치 => 지정가에 체결
+	broker.OnCandle(newBacktestCandle("KRW-XRP", now.Add(2*time.Minute), 990, 995, 940, 960))
+	got, _ = broker.Order("KRW-XRP", buy.ExchangeID, false)
+	require.Equal(t, model.OrderStatusTypeDone, got.Status)
+	require.InDelta(t, 950, got.Price, 1e-9)
+
+	account, _ = broker.Account()
+	require.InDelta(t, 0, account.Balances[0].Locked, 1e-6)
+	require.InDelta(t, 1_000_000-95_000*(1+broker.FeeRate), account.Balances[0].Balance, 1e-6)
+
+	// 매도 지정가 주문 후 취소 => 묶인 코인이 돌아와야 함
+	sell, err := broker.CreateOrderLimit(model.SideTypeSell, "KRW-XRP", 100, 2000)
+	require.NoError(t, err)
+	account, _ = broker.Account()
+	require.InDelta(t, 100, account.Balances[1].Locked, 1e-9)
+
+	require.NoError(t, broker.Cancel(sell, false))
+	got, _ = broker.Order("KRW-XRP", sell.ExchangeID, false)
+	require.Equal(t, model.OrderStatusTypeCanceled, got.Status)
+	account, _ = broker.Account()
+	require.InDelta(t, 100, account.Balances[1].Balance, 1e-9)
+	require.InDelta(t, 0, account.Balances[1].Locked, 1e-9)
+
+	open, _ = broker.OpenOrders("KRW-XRP", 10)
+	require.Empty(t, open)
+}
+
+// TestBacktestBroker_BestAndIOC : 최유리 주문은 즉시 체결, 즉시 체결 불가능한 IOC 지정가는 취소
+func TestBacktestBroker_BestAndIOC(t *testing.T) {
+	broker := exchange.NewBackTestBroker("KRW-XRP", 1_000_000)
+	broker.OnCandle(newBacktestCandle("KRW-XRP", time.Now(), 1000, 1000, 1000, 1000))
+
+	_, err := broker.CreateOrderBest(model.SideTypeBuy, "KRW-XRP", 50_000)
+	require.Error(t, err)
+
+	best, err := broker.CreateOrderBest(model.SideTypeBuy, "KRW-XRP", 50_000, model.TimeInForceIOC)
+	require.NoError(t, err)
+	require.Equal(t, model.OrderStatusTypeDone, best.Status)
+	require.InDelta(t, 50, best.Quantity, 1e-9)
+
+	ioc, err := broker.CreateOrderLimit(model.SideTypeBuy, "KRW-XRP", 10, 900, model.TimeInForceIOC)
+	require.NoError(t, err)
+	require.Equal(t, model.OrderStatusTypeCanceled, ioc.Status)
+
+	_, krw, _, _ := broker.Position("KRW-XRP")
+	require.InDelta(t, 1_000_000-50_000*(1+broker.FeeRate), krw, 1e-6)
+}
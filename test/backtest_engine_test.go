@@ -0,0 +1,150 @@
+package test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/backtest"
+	"raccoon/exchange"
+	"raccoon/feed"
+	"raccoon/indicator"
+	"raccoon/interfaces"
+	"raccoon/mocks"
+	"raccoon/model"
+)
+
+// momentumStrategy : 직전 봉보다 오르면 매수, 내리면 전량 매도하는 테스트용 전략
+type momentumStrategy struct {
+	orderFeed *feed.OrderFeedSubscription
+}
+
+func (s *momentumStrategy) GetName() string   { return "momentum" }
+func (s *momentumStrategy) Timeframe() string { return "1m" }
+func (s *momentumStrategy) WarmupPeriod() int { return 3 }
+func (s *momentumStrategy) Indicators(df *model.Dataframe) []indicator.ChartIndicator {
+	return nil
+}
+
+func (s *momentumStrategy) OnCandle(df *model.Dataframe, broker interfaces.Broker) {
+	last, prev := df.Close.Last(0), df.Close.Last(1)
+	coin, krw, _, err := broker.Position(df.Pair)
+	if err != nil {
+		return
+	}
+	if last > prev && coin == 0 {
+		s.orderFeed.Publish(model.Order{Pair: df.Pair, Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: krw / 2})
+	} else if last < prev && coin > 0 {
+		s.orderFeed.Publish(model.Order{Pair: df.Pair, Side: model.SideTypeSell, Type: model.OrderTypeMarket, Quantity: coin})
+	}
+}
+
+func newEngineFeeder(start time.Time, n int) *mocks.MockDataFeeder {
+	feeder := mocks.NewMockDataFeeder()
+	for i := 0; i < n; i++ {
+		price := 1000 + 50*math.Sin(float64(i)/3)
+		feeder.Add("KRW-XRP", "1m", newBacktestCandle("KRW-XRP", start.Add(time.Duration(i)*time.Minute),
+			price, price+5, price-5, price+1))
+	}
+	return feeder
+}
+
+func runEngine(t *testing.T, feeder interfaces.DataFeeder, start, end time.Time) *backtest.Result {
+	engine := backtest.NewEngine(feeder, 1_000_000)
+	engine.AddStrategy("KRW-XRP", &momentumStrategy{orderFeed: engine.OrderFeed()})
+	result, err := engine.Run(start, end)
+	require.NoError(t, err)
+	return result
+}
+
+// TestBacktestEngine_FillsOnSignalCandle : 주문은 신호를 만든 봉의 마감 시각/종가로 체결되어야 함
+func TestBacktestEngine_FillsOnSignalCandle(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	feeder := newEngineFeeder(base, 60)
+	start := base.Add(10 * time.Minute)
+	end := base.Add(60 * time.Minute)
+
+	result := runEngine(t, feeder, start, end)
+	require.NotEmpty(t, result.Orders)
+	require.Len(t, result.Equity, 50)
+
+	closeByTime := make(map[time.Time]float64)
+	for _, c := range feeder.Candles["KRW-XRP_1m"] {
+		closeByTime[c.Time.Add(time.Minute)] = c.Close
+	}
+	for _, order := range result.Orders {
+		closePrice, ok := closeByTime[order.UpdatedAt]
+		require.True(t, ok, "order filled off candle close: %v", order.UpdatedAt)
+		require.False(t, order.UpdatedAt.Before(start.Add(time.Minute)), "order filled during warmup")
+		if order.Side == model.SideTypeSell {
+			require.InDelta(t, closePrice, order.Price, 1e-9)
+		}
+	}
+	require.InDelta(t, result.Equity[len(result.Equity)-1].Equity, result.FinalEquity, 1e-6)
+}
+
+// TestBacktestEngine_Deterministic : 같은 입력이면 주문과 평가금액 곡선이 완전히 같아야 함
+func TestBacktestEngine_Deterministic(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	start, end := base.Add(10*time.Minute), base.Add(200*time.Minute)
+
+	first := runEngine(t, newEngineFeeder(base, 200), start, end)
+	for i := 0; i < 5; i++ {
+		again := runEngine(t, newEngineFeeder(base, 200), start, end)
+		require.Equal(t, first.Orders, again.Orders)
+		require.Equal(t, first.Equity, again.Equity)
+	}
+}
+
+// closeBuyer : 첫 마감 봉에서 한 번 매수하고 그 봉의 종가를 기록하는 테스트용 전략
+type closeBuyer struct {
+	orderFeed *feed.OrderFeedSubscription
+	timeframe string
+	seen      float64
+}
+
+func (s *closeBuyer) GetName() string   { return "close-buyer" }
+func (s *closeBuyer) Timeframe() string { return s.timeframe }
+func (s *closeBuyer) WarmupPeriod() int { return 1 }
+func (s *closeBuyer) Indicators(df *model.Dataframe) []indicator.ChartIndicator {
+	return nil
+}
+
+func (s *closeBuyer) OnCandle(df *model.Dataframe, broker interfaces.Broker) {
+	if s.seen > 0 || s.orderFeed == nil {
+		return
+	}
+	s.seen = df.Close.Last(0)
+	s.orderFeed.Publish(model.Order{Pair: df.Pair, Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 100_000})
+}
+
+// TestBacktestEngine_DriverFirstOnSharedClose : 긴 봉 전략을 먼저 등록해도 같은 마감 시각에는 체결용 짧은 봉이 먼저 처리되어
+// 긴 봉 신호의 주문이 그 시각 종가로 체결되어야 함 (직전 짧은 봉 종가로 체결되면 미래참조)
+func TestBacktestEngine_DriverFirstOnSharedClose(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	feeder := mocks.NewMockDataFeeder()
+	for i := 0; i < 30; i++ {
+		price := 1000 + 10*float64(i)
+		feeder.Add("KRW-XRP", "1m", newBacktestCandle("KRW-XRP", base.Add(time.Duration(i)*time.Minute), price, price+5, price-5, price))
+	}
+	for k := 0; k < 6; k++ {
+		open, closePrice := 1000+10*float64(5*k), 1000+10*float64(5*k+4)
+		feeder.Add("KRW-XRP", "5m", newBacktestCandle("KRW-XRP", base.Add(time.Duration(5*k)*time.Minute), open, closePrice+5, open-5, closePrice))
+	}
+
+	engine := backtest.NewEngine(feeder, 1_000_000)
+	slow := &closeBuyer{orderFeed: engine.OrderFeed(), timeframe: "5m"}
+	engine.AddStrategy("KRW-XRP", slow)
+	engine.AddStrategy("KRW-XRP", &closeBuyer{timeframe: "1m"})
+
+	start, end := base.Add(10*time.Minute), base.Add(30*time.Minute)
+	result, err := engine.Run(start, end)
+	require.NoError(t, err)
+	require.Len(t, result.Orders, 1)
+
+	order := result.Orders[0]
+	require.True(t, order.UpdatedAt.Equal(start.Add(5*time.Minute)), "filled at %v", order.UpdatedAt)
+	require.InDelta(t, slow.seen, order.Price, 1e-9, "5m 신호는 같은 시각 1m 종가로 체결")
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/backtest"
+	"raccoon/exchange"
+	"raccoon/model"
+)
+
+func newReportResult() *backtest.Result {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, exchange.KSTLocation)
+	at := func(d int) time.Time { return start.AddDate(0, 0, d) }
+
+	return &backtest.Result{
+		Start:       start,
+		End:         at(4),
+		InitialKRW:  100,
+		FinalEquity: 130,
+		Equity: []backtest.EquityPoint{
+			{Time: at(1), Equity: 120, Cash: 0},
+			{Time: at(2), Equity: 90, Cash: 0},
+			{Time: at(3), Equity: 110, Cash: 110},
+			{Time: at(4), Equity: 130, Cash: 130},
+		},
+		Orders: []model.Order{
+			{Pair: "KRW-XRP", Side: model.SideTypeBuy, Price: 10, Quantity: 10, UpdatedAt: at(0)},
+			{Pair: "KRW-XRP", Side: model.SideTypeSell, Price: 9, Quantity: 5, UpdatedAt: at(2)},
+			{Pair: "KRW-XRP", Side: model.SideTypeSell, Price: 13, Quantity: 5, UpdatedAt: at(3)},
+		},
+		Candles: map[string][]model.Candle{
+			"KRW-XRP": {
+				{Pair: "KRW-XRP", Time: at(0), Open: 10, Close: 12},
+				{Pair: "KRW-XRP", Time: at(1), Open: 12, Close: 8},
+				{Pair: "KRW-XRP", Time: at(2), Open: 8, Close: 11},
+				{Pair: "KRW-XRP", Time: at(3), Open: 11, Close: 15},
+			},
+		},
+	}
+}
+
+// TestBacktestReport_Metrics : 손으로 계산한 값과 리포트 지표 비교 (수수료 0)
+func TestBacktestReport_Metrics(t *testing.T) {
+	report := backtest.NewReport(newReportResult())
+
+	require.InDelta(t, 0.3, report.TotalReturn, 1e-9)
+	require.InDelta(t, 0.25, report.MaxDrawdown, 1e-9)                        // 120 -> 90
+	require.Equal(t, 72*time.Hour, time.Duration(report.MaxDrawdownDuration)) // 120 고점 -> 130 회복
+	require.Greater(t, report.AnnualizedReturn, report.TotalReturn)
+	require.InDelta(t, report.AnnualizedReturn/report.MaxDrawdown, report.Calmar, 1e-9)
+	require.Greater(t, report.Sharpe, 0.0)
+	require.Greater(t, report.Sortino, 0.0)
+
+	// 손실 5, 이익 15
+	require.Equal(t, 2, report.Trades)
+	require.InDelta(t, 0.5, report.WinRate, 1e-9)
+	require.InDelta(t, 3.0, float64(report.ProfitFactor), 1e-9)
+	require.Equal(t, 60*time.Hour, time.Duration(report.AvgHoldingTime))
+	require.InDelta(t, 0.5, report.Exposure, 1e-9)
+
+	require.Len(t, report.Benchmarks, 1)
+	require.InDelta(t, 0.5, report.Benchmarks[0].TotalReturn, 1e-9)
+	require.InDelta(t, 1.0/3, report.Benchmarks[0].MaxDrawdown, 1e-9) // 12 -> 8
+	require.Equal(t, []float64{120, 80, 110, 150}, report.BenchmarkCurve("KRW-XRP"))
+}
+
+// TestBacktestReport_JSONAndHTML : JSON 직렬화/역직렬화와 HTML 렌더링
+func TestBacktestReport_JSONAndHTML(t *testing.T) {
+	report := backtest.NewReport(newReportResult())
+
+	data, err := report.JSON()
+	require.NoError(t, err)
+
+	var decoded backtest.Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.InDelta(t, report.Sharpe, decoded.Sharpe, 1e-9)
+	require.Equal(t, report.MaxDrawdownDuration, decoded.MaxDrawdownDuration)
+	require.Len(t, decoded.ClosedTrades, 2)
+	require.Contains(t, string(data), `"max_drawdown_duration": "72h0m0s"`)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteHTML(&buf))
+	html := buf.String()
+	require.Contains(t, html, "echarts.min.js")
+	require.Contains(t, html, "Sharpe")
+	require.Contains(t, html, "Buy&Hold KRW-XRP")
+}
+
+// TestBacktestReport_ProfitFactorWithoutLosses : 손실 거래가 없으면 Profit Factor 는 +Inf 이고 JSON/HTML 에서 "∞" 로 표시
+func TestBacktestReport_ProfitFactorWithoutLosses(t *testing.T) {
+	result := newReportResult()
+	result.Orders[1].Price = 11 // 첫 매도도 이익
+
+	report := backtest.NewReport(result)
+	require.InDelta(t, 1.0, report.WinRate, 1e-9)
+	require.True(t, math.IsInf(float64(report.ProfitFactor), 1))
+
+	data, err := report.JSON()
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"profit_factor": "∞"`)
+	var decoded backtest.Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, math.IsInf(float64(decoded.ProfitFactor), 1))
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteHTML(&buf))
+	require.Contains(t, buf.String(), "∞")
+
+	// 이익 거래가 없으면 0
+	result.Orders[1].Price, result.Orders[2].Price = 9, 9
+	require.Zero(t, float64(backtest.NewReport(result).ProfitFactor))
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/exchange"
+	"raccoon/model"
+)
+
+// TestCandleAggregator_BufferBounded : 1초봉을 계속 넣어도 buffer 는 1초봉이면 비어 있고, 분봉이면 한 구간 이하로 유지
+func TestCandleAggregator_BufferBounded(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	second, err := exchange.NewCandleAggregator("KRW-XRP", "1s")
+	require.NoError(t, err)
+	minute, err := exchange.NewCandleAggregator("KRW-XRP", "1m")
+	require.NoError(t, err)
+
+	for i := 0; i < 3600; i++ {
+		c := model.Candle{Pair: "KRW-XRP", Time: start.Add(time.Duration(i) * time.Second), Open: 1, High: 1, Low: 1, Close: 1, Volume: 1}
+		partial, _, isFinal := second.Push(c)
+		require.True(t, isFinal, "1초봉은 바로 완성")
+		require.True(t, partial.Time.Equal(c.Time))
+		minute.Push(c)
+
+		require.Zero(t, second.Buffered(), "1초봉은 buffer 에 쌓지 않음")
+		require.LessOrEqual(t, minute.Buffered(), 61)
+	}
+
+	// 이미 마감된 구간의 늦은 1초봉도 다음 마감 때 제거
+	buffered := minute.Buffered()
+	minute.Push(model.Candle{Pair: "KRW-XRP", Time: start, Close: 1, Volume: 1})
+	require.Equal(t, buffered+1, minute.Buffered())
+	minute.Push(model.Candle{Pair: "KRW-XRP", Time: start.Add(time.Hour + time.Minute), Close: 1, Volume: 1})
+	require.Equal(t, 1, minute.Buffered())
+
+	_, err = exchange.NewCandleAggregator("KRW-XRP", "7m")
+	require.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/cache"
+	"raccoon/exchange"
+	"raccoon/model"
+)
+
+func newCacheCandles(start time.Time, n int, skip ...int) []model.Candle {
+	skipped := make(map[int]bool)
+	for _, i := range skip {
+		skipped[i] = true
+	}
+	var candles []model.Candle
+	for i := 0; i < n; i++ {
+		if skipped[i] {
+			continue
+		}
+		price := 1000 + float64(i)
+		candles = append(candles, newBacktestCandle("KRW-XRP", start.Add(time.Duration(i)*time.Minute), price, price+1, price-1, price))
+	}
+	return candles
+}
+
+// TestCandleStore_MissingAndGaps : 저장한 구간은 다시 받지 않고, 구간 안의 빈 봉은 gap으로 잡혀야 함
+func TestCandleStore_MissingAndGaps(t *testing.T) {
+	store, err := cache.NewCandleStore(t.TempDir())
+	require.NoError(t, err)
+
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	first := cache.Range{Start: base, End: base.Add(10 * time.Minute)}
+	require.NoError(t, store.Save("KRW-XRP", "1m", first, newCacheCandles(base, 10, 3, 4)))
+
+	second := cache.Range{Start: base.Add(20 * time.Minute), End: base.Add(30 * time.Minute)}
+	require.NoError(t, store.Save("KRW-XRP", "1m", second, newCacheCandles(base, 30)))
+
+	// 범위 밖 캔들은 저장되지 않음
+	loaded, err := store.Load("KRW-XRP", "1m", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, loaded, 8+10)
+
+	missing, err := store.Missing("KRW-XRP", "1m", cache.Range{Start: base.Add(-5 * time.Minute), End: base.Add(40 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, missing, 3)
+	require.True(t, missing[0].Start.Equal(base.Add(-5*time.Minute)) && missing[0].End.Equal(base))
+	require.True(t, missing[1].Start.Equal(base.Add(10*time.Minute)) && missing[1].End.Equal(base.Add(20*time.Minute)))
+	require.True(t, missing[2].Start.Equal(base.Add(30*time.Minute)) && missing[2].End.Equal(base.Add(40*time.Minute)))
+
+	gaps, err := store.Gaps("KRW-XRP", "1m", cache.Range{Start: base, End: base.Add(30 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, gaps, 1)
+	require.True(t, gaps[0].Start.Equal(base.Add(3*time.Minute)) && gaps[0].End.Equal(base.Add(5*time.Minute)))
+
+	// 사이 구간을 채우면 coverage가 하나로 합쳐짐
+	middle := cache.Range{Start: base.Add(10 * time.Minute), End: base.Add(20 * time.Minute)}
+	require.NoError(t, store.Save("KRW-XRP", "1m", middle, newCacheCandles(base, 30)))
+	coverage, err := store.Coverage("KRW-XRP", "1m")
+	require.NoError(t, err)
+	require.Len(t, coverage, 1)
+
+	// 주봉 이상은 캐시 대상이 아님
+	_, ok := cache.Cacheable("1w")
+	require.False(t, ok)
+	require.Error(t, store.Save("KRW-XRP", "1w", first, nil))
+}
+
+// TestCacheFeeder_LastQuoteShortestTimeframe : 1분봉 캐시가 없어도 캐시된 가장 짧은 봉의 종가를 현재가로 사용
+func TestCacheFeeder_LastQuoteShortestTimeframe(t *testing.T) {
+	store, err := cache.NewCandleStore(t.TempDir())
+	require.NoError(t, err)
+	feeder := cache.NewFeeder(store)
+
+	_, err = feeder.LastQuote("KRW-XRP")
+	require.ErrorIs(t, err, cache.ErrNoCachedCandles)
+
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	hourly := []model.Candle{newBacktestCandle("KRW-XRP", base, 1000, 1010, 990, 1005)}
+	require.NoError(t, store.Save("KRW-XRP", "1h", cache.Range{Start: base, End: base.Add(time.Hour)}, hourly))
+	last, err := feeder.LastQuote("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, 1005, last, 1e-9)
+
+	quarter := []model.Candle{newBacktestCandle("KRW-XRP", base.Add(45*time.Minute), 1002, 1004, 1001, 1003)}
+	require.NoError(t, store.Save("KRW-XRP", "15m", cache.Range{Start: base, End: base.Add(time.Hour)}, quarter))
+	timeframes, err := store.Timeframes("krw-xrp")
+	require.NoError(t, err)
+	require.Equal(t, []string{"15m", "1h"}, timeframes)
+	last, err = feeder.LastQuote("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, 1003, last, 1e-9)
+}
+
+// TestCacheFeeder_OfflineBacktest : 캐시만으로 API 키 없이 백테스트가 돌아가야 함
+func TestCacheFeeder_OfflineBacktest(t *testing.T) {
+	dir := t.TempDir()
+	store, err := cache.NewCandleStore(dir)
+	require.NoError(t, err)
+
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	candles := newEngineFeeder(base, 120).Candles["KRW-XRP_1m"]
+	require.NoError(t, store.Save("KRW-XRP", "1m", cache.Range{Start: base, End: base.Add(120 * time.Minute)}, candles))
+
+	reopened, err := cache.NewCandleStore(dir)
+	require.NoError(t, err)
+	feeder := cache.NewFeeder(reopened)
+
+	last, err := feeder.LastQuote("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, candles[len(candles)-1].Close, last, 1e-9)
+
+	fromCache := runEngine(t, feeder, base.Add(10*time.Minute), base.Add(120*time.Minute))
+	fromMemory := runEngine(t, newEngineFeeder(base, 120), base.Add(10*time.Minute), base.Add(120*time.Minute))
+	require.NotEmpty(t, fromCache.Orders)
+	require.Equal(t, len(fromMemory.Orders), len(fromCache.Orders))
+	require.InDelta(t, fromMemory.FinalEquity, fromCache.FinalEquity, 1e-6)
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/cli"
+	"raccoon/data"
+	"raccoon/exchange"
+	"raccoon/model"
+)
+
+// TestCLI_BacktestAndReport : 파일 캔들로 backtest 를 돌리고 저장된 리포트를 report 로 다시 읽음
+func TestCLI_BacktestAndReport(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	dir := t.TempDir()
+	candlePath := filepath.Join(dir, "xrp.csv")
+	require.NoError(t, data.SaveFile(candlePath, newEngineFeeder(base, 240).Candles["KRW-XRP_1m"]))
+
+	prefix := filepath.Join(dir, "report")
+	var stdout, stderr bytes.Buffer
+	code := cli.Run([]string{
+		"backtest", "-data", candlePath, "-pair", "krw-xrp", "-timeframe", "1m",
+		"-params", "trade_fraction=0.5", "-start", "2025-01-01 09:30", "-end", "2025-01-01 13:00",
+		"-krw", "1000000", "-out", prefix,
+	}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Contains(t, stdout.String(), "improved_psh-KRW-XRP")
+	require.Contains(t, stdout.String(), "trade_fraction=0.5")
+	require.FileExists(t, prefix+".json")
+	require.FileExists(t, prefix+".html")
+
+	html := filepath.Join(dir, "again.html")
+	stdout.Reset()
+	code = cli.Run([]string{"report", "-html", html, prefix + ".json"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Contains(t, stdout.String(), "2025-01-01 ~ 2025-01-01")
+	info, err := os.Stat(html)
+	require.NoError(t, err)
+	require.Positive(t, info.Size())
+}
+
+// TestCLI_UsageErrors : 잘못된 명령/플래그는 종료 코드 2
+func TestCLI_UsageErrors(t *testing.T) {
+	cases := [][]string{
+		{"bogus"},
+		{"backtest", "-start", "2025-01-01", "-params", "trade_fraction"},
+		{"backtest", "-start", "yesterday"},
+		{"backtest", "-start", "2025-01-01", "-strategy", "nope"},
+		{"report"},
+	}
+	for _, args := range cases {
+		var stdout, stderr bytes.Buffer
+		require.Equal(t, 2, cli.Run(args, &stdout, &stderr), "%v", args)
+		require.NotEmpty(t, stderr.String(), "%v", args)
+	}
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, cli.Run([]string{"strategies"}, &stdout, &stderr))
+	require.Contains(t, stdout.String(), "improved_psh")
+}
+
+// TestCLI_BacktestValidate : -validate 는 전략 대신 일봉 지표 신호 검증을 돌리고 최종 잔고를 출력
+func TestCLI_BacktestValidate(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	candles := make([]model.Candle, 0, 180)
+	for i := 0; i < 180; i++ {
+		price := 1000 + 300*math.Sin(float64(i)/15) + float64(i)
+		candles = append(candles, newBacktestCandle("KRW-XRP", base.AddDate(0, 0, i), price, price*1.02, price*0.98, price))
+	}
+	dir := t.TempDir()
+	candlePath := filepath.Join(dir, "xrp_1d.csv")
+	require.NoError(t, data.SaveFile(candlePath, candles))
+
+	var stdout, stderr bytes.Buffer
+	code := cli.Run([]string{
+		"backtest", "-validate", "-data", candlePath, "-pair", "KRW-XRP",
+		"-start", "2024-01-01", "-end", "2024-07-01", "-krw", "1000000",
+	}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Contains(t, stdout.String(), "Validate: KRW-XRP-1d")
+	require.Contains(t, stdout.String(), "총 매매 신호 수")
+	require.Contains(t, stdout.String(), "최종 잔고")
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/bot"
+	"raccoon/config"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// TestConfig_LoadExample : 저장소의 예시 설정이 그대로 로드되고 기본값이 채워져야 함
+func TestConfig_LoadExample(t *testing.T) {
+	cfg, err := config.Load("../config.example.yaml")
+	require.NoError(t, err)
+
+	require.Equal(t, config.ExchangeUpbit, cfg.Exchange.Name)
+	require.Len(t, cfg.Strategies, 2)
+	require.Equal(t, []string{"KRW-XRP"}, cfg.Pairs())
+	require.Equal(t, "15m", cfg.Strategies[1].Timeframe)
+	require.InDelta(t, 0.3, cfg.Strategies[1].Params["trade_fraction"], 1e-9)
+	require.Equal(t, ":3030", cfg.WebServer.Address)
+	require.InDelta(t, 200_000, cfg.Risk.MaxOrderKRW, 1e-9)
+
+	specs, err := bot.StrategySpecs(cfg)
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	require.InDelta(t, 0.03, specs[0].Exits.StopLoss, 1e-9)
+	require.InDelta(t, 0.02, specs[0].Exits.TrailingStop, 1e-9)
+	strat := specs[1].New(nil)
+	require.Equal(t, "15m", strat.Timeframe())
+}
+
+// TestConfig_JSONDefaults : JSON 도 지원하고, 비어있는 이름/비중/로그 레벨은 기본값으로 채움
+func TestConfig_JSONDefaults(t *testing.T) {
+	path := writeConfig(t, "bot.json", `{"strategies": [{"type": "improved_psh", "pair": "krw-btc"}]}`)
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	s := cfg.Strategies[0]
+	require.Equal(t, "KRW-BTC", s.Pair)
+	require.Equal(t, "improved_psh-KRW-BTC", s.Name)
+	require.InDelta(t, 1, s.Weight, 1e-9)
+	require.Equal(t, config.DefaultLogLevel, cfg.Log.Level)
+	require.Equal(t, config.DefaultAccessKeyEnv, cfg.Exchange.AccessKeyEnv)
+	require.Equal(t, config.ModeLive, cfg.Mode)
+	require.InDelta(t, config.DefaultPaperKRW, cfg.Paper.InitialKRW, 1e-9)
+	require.Equal(t, config.DefaultJournalPath, cfg.Journal.Path)
+	require.Equal(t, config.DefaultStoragePath, cfg.Storage.Path)
+	require.Equal(t, config.DefaultKillSwitchPath, cfg.KillSwitch.Path)
+}
+
+// TestConfig_ValidationErrors : 잘못된 항목은 필드 경로와 함께 한 번에 모두 보고
+func TestConfig_ValidationErrors(t *testing.T) {
+	path := writeConfig(t, "bot.yaml", `
+mode: demo
+exchange:
+  name: binance
+strategies:
+  - name: a
+    type: improved_psh
+    pair: XRP
+    timeframe: 7m
+  - name: a
+    type: improved_psh
+    pair: KRW-XRP
+    exits:
+      stop_loss: 1.2
+notifier:
+  type: slack
+log:
+  level: loud
+risk:
+  max_order_equity_rate: 1.5
+portfolio:
+  cost_method: lifo
+`)
+	_, err := config.Load(path)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, config.ErrInvalidConfig))
+	for _, field := range []string{
+		"mode", "exchange.name", "strategies[0].pair", "strategies[0].timeframe", "strategies[1].name",
+		"strategies[1].exits.stop_loss", "notifier.type", "log.level", "risk.max_order_equity_rate", "portfolio.cost_method",
+	} {
+		require.Contains(t, err.Error(), field)
+	}
+
+	_, err = config.Load(writeConfig(t, "typo.yaml", "strategy:\n  - type: improved_psh\n"))
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+	require.Contains(t, err.Error(), "strategy")
+}
+
+// TestConfig_StrategySpecErrors : 모르는 전략 종류나 범위를 벗어난 파라미터는 실행 전에 거절
+func TestConfig_StrategySpecErrors(t *testing.T) {
+	cfg := config.Default([]string{"KRW-XRP", "KRW-BTC"})
+	cfg.Strategies[0].Type = "unknown"
+	cfg.Strategies[1].Params = map[string]float64{"trade_fraction": 5}
+	require.NoError(t, cfg.Validate())
+
+	_, err := bot.StrategySpecs(cfg)
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+	require.Contains(t, err.Error(), `strategies[0]: type: unknown strategy "unknown"`)
+	require.Contains(t, err.Error(), "strategies[1]: params:")
+}
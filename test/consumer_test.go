@@ -1,9 +1,12 @@
 package test
 
 import (
+	"fmt"
 	"testing"
 
 	"raccoon/consumer"
+	"raccoon/exchange"
+	"raccoon/interfaces"
 	"raccoon/model"
 
 	"raccoon/mocks" // 방금 만든 mock_exchange.go가 들어있는 package
@@ -57,3 +60,124 @@ func TestOrderFeedConsumerBroker_BuyMarketPrice(t *testing.T) {
 // 만약 MockExchange의 mockKrw, mockCoin를 Getter로 꺼내고 싶다면
 // (지금 예시엔 필드가 소문자(m.mockKrw)라 외부 접근 불가)
 // 별도 Getter 함수 만들어 쓰면 됩니다.
+
+// TestOrderFeedConsumerBroker_ConfirmByExchange
+//   - private 스트림으로 체결을 확인하는 모드에서는 REST 응답만으로 콜백이 호출되지 않고,
+//     myOrder done 이벤트를 받은 뒤에 체결가/체결량으로 콜백되는지 검증
+func TestOrderFeedConsumerBroker_ConfirmByExchange(t *testing.T) {
+	mockEx := &mocks.MockExchange{MockKrw: 100000}
+	ofc := consumer.NewOrderFeedConsumerBroker(mockEx)
+	ofc.ConfirmByExchange(true)
+
+	var executed []model.Order
+	ofc.AddOrderExecutedCallback(func(order model.Order, err error) {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		executed = append(executed, order)
+	})
+
+	ofc.OnOrder(model.Order{Pair: "KRW-DOGE", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 50000})
+	if len(executed) != 0 {
+		t.Fatalf("callback must wait for exchange confirmation, got %d calls", len(executed))
+	}
+
+	// 체결 중간 이벤트(trade)는 무시
+	ofc.OnExecution(model.UpbitMyOrderMessage{Code: "KRW-DOGE", State: "trade", AskBid: "BID", Price: 99, Volume: 100})
+	if len(executed) != 0 {
+		t.Fatalf("trade event must not trigger callback, got %d calls", len(executed))
+	}
+
+	ofc.OnExecution(model.UpbitMyOrderMessage{
+		Code:           "KRW-DOGE",
+		State:          "done",
+		AskBid:         "BID",
+		AvgPrice:       99.5,
+		ExecutedVolume: 502.5,
+	})
+	if len(executed) != 1 {
+		t.Fatalf("want 1 confirmed execution, got %d", len(executed))
+	}
+	if executed[0].Price != 99.5 || executed[0].Quantity != 502.5 || executed[0].Side != model.SideTypeBuy {
+		t.Errorf("unexpected confirmed order: %+v", executed[0])
+	}
+}
+
+// earlyEventBroker : 주문 응답을 돌려주기 전에 private 스트림 이벤트가 먼저 도착하는 상황을 흉내내는 브로커
+type earlyEventBroker struct {
+	*exchange.BacktestBroker
+	before func(order model.Order)
+}
+
+func (b *earlyEventBroker) CreateOrderMarket(side model.SideType, pair string, quantity float64) (model.Order, error) {
+	order, err := b.BacktestBroker.CreateOrderMarket(side, pair, quantity)
+	if err == nil && b.before != nil {
+		b.before(order)
+	}
+	return order, err
+}
+
+func doneMessage(order model.Order) model.UpbitMyOrderMessage {
+	return model.UpbitMyOrderMessage{
+		UUID: order.ExchangeID, Code: order.Pair, State: "done", AskBid: "BID",
+		AvgPrice: 1000, ExecutedVolume: 10,
+	}
+}
+
+// TestExecutionRouter_RoutesBySubmitter
+//   - 같은 종목을 거래하는 두 브로커가 라우터를 공유하면 각자 낸 주문의 종료 이벤트만 받고,
+//     주인 없는 이벤트(수동 주문 등)가 많이 쌓여도 REST 응답보다 먼저 온 자기 주문 이벤트는 버려지지 않는지 검증
+func TestExecutionRouter_RoutesBySubmitter(t *testing.T) {
+	router := consumer.NewExecutionRouter()
+	shared := newJournalBroker()
+
+	newBroker := func(broker interfaces.Broker, executed *[]model.Order) *consumer.OrderFeedConsumerBroker {
+		ofc := consumer.NewOrderFeedConsumerBroker(broker)
+		ofc.ConfirmByExchange(true)
+		ofc.SetExecutionRouter(router)
+		ofc.AddOrderExecutedCallback(func(order model.Order, err error) {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			*executed = append(*executed, order)
+		})
+		return ofc
+	}
+
+	var first, second []model.Order
+	early := &earlyEventBroker{BacktestBroker: shared}
+	a := newBroker(early, &first)
+	b := newBroker(shared, &second)
+
+	// a 의 주문: 응답 전에 수동 주문 이벤트 150개와 자기 주문 종료 이벤트가 먼저 도착
+	var aOrder model.Order
+	early.before = func(order model.Order) {
+		aOrder = order
+		for i := 0; i < 150; i++ {
+			router.OnExecution(model.UpbitMyOrderMessage{UUID: fmt.Sprintf("manual-%d", i), Code: "KRW-XRP", State: "done"})
+		}
+		router.OnExecution(doneMessage(order))
+	}
+	if err := a.HandleOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 10000, Strategy: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || first[0].ExchangeID != aOrder.ExchangeID || first[0].Strategy != "a" {
+		t.Fatalf("early done event for own order must be delivered once, got %+v", first)
+	}
+
+	// b 의 주문 이벤트는 b 에게만
+	early.before = nil
+	if err := b.HandleOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 10000, Strategy: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	orders := shared.Orders()
+	bOrder := orders[len(orders)-1]
+	router.OnExecution(doneMessage(bOrder))
+	router.OnExecution(doneMessage(aOrder)) // 이미 확정된 주문이 다시 와도 무시
+	if len(first) != 1 {
+		t.Fatalf("broker a must not receive other events, got %d", len(first))
+	}
+	if len(second) != 1 || second[0].ExchangeID != bOrder.ExchangeID || second[0].Strategy != "b" {
+		t.Fatalf("broker b must receive only its own order, got %+v", second)
+	}
+}
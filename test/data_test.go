@@ -0,0 +1,117 @@
+package test
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/data"
+	"raccoon/exchange"
+	"raccoon/model"
+)
+
+// TestData_ReadCSV : 컬럼 별칭, 시각 포맷, 추가 컬럼 -> Metadata 매핑
+func TestData_ReadCSV(t *testing.T) {
+	input := `Timestamp,Open,High,Low,Close,Volume,funding_rate,note
+2025-01-01 09:01:00,101,103,100,102,10,0.0001,b
+2025-01-01 09:00:00,100,102,99,101,12,-0.0002,a
+`
+	candles, err := data.ReadCSV(strings.NewReader(input), data.Options{Pair: "BINANCE-BTCUSDT"})
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+
+	// 시간순 정렬, 타임존 없는 시각은 KST 로 해석
+	first := candles[0]
+	require.True(t, first.Time.Equal(time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)))
+	require.Equal(t, "BINANCE-BTCUSDT", first.Pair)
+	require.Equal(t, 101.0, first.Close)
+	require.Equal(t, 12.0, first.Volume)
+	require.True(t, first.Complete)
+	require.Equal(t, map[string]float64{"funding_rate": -0.0002}, first.Metadata)
+
+	_, err = data.ReadCSV(strings.NewReader("time,open,high,low\n1,1,1,1\n"), data.Options{})
+	require.ErrorIs(t, err, data.ErrMissingColumn)
+}
+
+// TestData_RoundTrip : CSV / JSONL 로 내보낸 뒤 다시 읽으면 같은 캔들이어야 함
+func TestData_RoundTrip(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	df := &model.Dataframe{
+		Pair:     "KRW-XRP",
+		Time:     []time.Time{base, base.Add(time.Minute), base.Add(2 * time.Minute)},
+		Open:     []float64{1, 2, 3},
+		High:     []float64{2, 3, 4},
+		Low:      []float64{0.5, 1.5, 2.5},
+		Close:    []float64{1.5, 2.5, 3.5},
+		Volume:   []float64{10, 20, 30},
+		Metadata: map[string]model.Series[float64]{"ema": {math.NaN(), 2.1, 3.1}},
+	}
+
+	var csvBuf, jsonBuf bytes.Buffer
+	require.NoError(t, data.WriteDataframeCSV(&csvBuf, df))
+	require.NoError(t, data.WriteDataframeJSONL(&jsonBuf, df))
+
+	fromCSV, err := data.ReadCSV(&csvBuf, data.Options{})
+	require.NoError(t, err)
+	fromJSON, err := data.ReadJSONL(&jsonBuf, data.Options{})
+	require.NoError(t, err)
+
+	for _, candles := range [][]model.Candle{fromCSV, fromJSON} {
+		require.Len(t, candles, 3)
+		for i, c := range candles {
+			require.Equal(t, "KRW-XRP", c.Pair)
+			require.True(t, c.Time.Equal(df.Time[i]))
+			require.Equal(t, df.Close[i], c.Close)
+			require.Equal(t, df.Volume[i], c.Volume)
+		}
+		require.InDelta(t, 3.1, candles[2].Metadata["ema"], 1e-12)
+	}
+	// JSON 은 NaN 을 표현할 수 없으므로 빠짐
+	_, ok := fromJSON[0].Metadata["ema"]
+	require.False(t, ok)
+}
+
+// TestData_FeederBacktest : 파일에서 읽은 캔들로 백테스트 엔진을 돌릴 수 있어야 함
+func TestData_FeederBacktest(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	candles := newEngineFeeder(base, 120).Candles["KRW-XRP_1m"]
+
+	path := filepath.Join(t.TempDir(), "xrp.csv")
+	require.NoError(t, data.SaveFile(path, candles))
+
+	feeder := data.NewFeeder()
+	require.NoError(t, feeder.LoadFile("KRW-XRP", "1m", path, data.Options{}))
+
+	last, err := feeder.LastQuote("KRW-XRP")
+	require.NoError(t, err)
+	require.InDelta(t, candles[len(candles)-1].Close, last, 1e-9)
+
+	fromFile := runEngine(t, feeder, base.Add(10*time.Minute), base.Add(120*time.Minute))
+	fromMemory := runEngine(t, newEngineFeeder(base, 120), base.Add(10*time.Minute), base.Add(120*time.Minute))
+	require.Equal(t, len(fromMemory.Orders), len(fromFile.Orders))
+	require.InDelta(t, fromMemory.FinalEquity, fromFile.FinalEquity, 1e-6)
+}
+
+// TestData_FeederLastQuoteShortestTimeframe : 여러 타임프레임을 불러오면 가장 짧은 타임프레임의 마지막 종가를 씀
+func TestData_FeederLastQuoteShortestTimeframe(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	feeder := data.NewFeeder()
+	feeder.Add("KRW-XRP", "1h", []model.Candle{
+		newBacktestCandle("KRW-XRP", base, 1000, 1100, 900, 1050),
+		newBacktestCandle("KRW-XRP", base.Add(2*time.Hour), 1050, 2100, 1000, 2000), // 1분봉보다 늦게 시작한 긴 봉
+	})
+	minutes := newEngineFeeder(base, 120).Candles["KRW-XRP_1m"]
+	feeder.Add("KRW-XRP", "1m", minutes)
+
+	last, err := feeder.LastQuote("krw-xrp")
+	require.NoError(t, err)
+	require.InDelta(t, minutes[len(minutes)-1].Close, last, 1e-9)
+
+	_, err = feeder.LastQuote("KRW-BTC")
+	require.ErrorIs(t, err, data.ErrNoData)
+}
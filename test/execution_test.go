@@ -0,0 +1,238 @@
+package test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/consumer"
+	"raccoon/exchange"
+	"raccoon/execution"
+	"raccoon/journal"
+	"raccoon/model"
+	"raccoon/utils/tools"
+)
+
+// bookBroker : 최우선 호가를 정해줄 수 있는 백테스트 브로커 (chase 재호가 확인용)
+type bookBroker struct {
+	*exchange.BacktestBroker
+	bid, ask float64
+}
+
+func (b *bookBroker) BestQuote(pair string) (float64, float64, error) {
+	return b.bid, b.ask, nil
+}
+
+// executionResult : 자식 주문 콜백 모음
+type executionResult struct {
+	filled []model.Order
+	errs   []error
+}
+
+// newExecutionConsumer : broker 로 자식 주문을 내는 주문 피드 소비자와 시뮬레이션 시계로 도는 실행기
+func newExecutionConsumer(broker *exchange.BacktestBroker, quotes execution.Quoter, clock *tools.SimulatedClock, result *executionResult) (*consumer.OrderFeedConsumerBroker, *execution.Executor) {
+	executor := execution.NewExecutor(quotes)
+	executor.SetClock(clock)
+	executor.FeeRate = broker.FeeRate
+
+	c := consumer.NewOrderFeedConsumerBroker(broker)
+	c.SetExecutor(executor)
+	c.AddOrderExecutedCallback(func(order model.Order, err error) {
+		if err != nil {
+			result.errs = append(result.errs, err)
+			return
+		}
+		result.filled = append(result.filled, order)
+	})
+	return c, executor
+}
+
+func xrpCandle(at time.Time, low, high float64) model.Candle {
+	return model.Candle{Pair: "KRW-XRP", Time: at, Open: low, Close: low, Low: low, High: high}
+}
+
+// TestExecution_TWAPSlicesOverTime : TWAP 은 기간을 조각 수로 나눈 시각마다 남은 금액을 나눠 시장가로 매수
+func TestExecution_TWAPSlicesOverTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := tools.NewSimulatedClock(start)
+	broker := newJournalBroker()
+	var result executionResult
+	c, executor := newExecutionConsumer(broker, broker, clock, &result)
+
+	order := buyKRW(300_000)
+	order.Execution = &model.Execution{Algo: model.ExecutionTWAP, Slices: 3, Duration: 3 * time.Minute}
+	require.NoError(t, c.HandleOrder(order))
+	require.Empty(t, result.filled, "Submit 은 등록만 함")
+	require.Len(t, executor.Active(), 1)
+
+	executor.Step()
+	require.Len(t, result.filled, 1)
+	executor.Step()
+	require.Len(t, result.filled, 1, "다음 조각 시각 전에는 내지 않음")
+
+	clock.Set(start.Add(time.Minute))
+	broker.OnCandle(xrpCandle(start.Add(time.Minute), 1010, 1010))
+	executor.Step()
+	require.Len(t, result.filled, 2)
+	require.InDelta(t, 1010, result.filled[1].Price, 1e-9)
+
+	progress := executor.Active()
+	require.Len(t, progress, 1)
+	require.Equal(t, 2, progress[0].Children)
+	require.InDelta(t, 100_000, progress[0].Remaining, 1)
+
+	clock.Set(start.Add(2 * time.Minute))
+	executor.Step()
+	require.Len(t, result.filled, 3)
+	require.Empty(t, executor.Active())
+	require.Empty(t, result.errs)
+
+	spent := 0.0
+	for _, child := range result.filled {
+		require.Equal(t, "psh", child.Strategy)
+		require.Equal(t, model.SideTypeBuy, child.Side)
+		spent += child.Price * child.Quantity * (1 + broker.FeeRate)
+	}
+	require.InDelta(t, 300_000, spent, 1)
+	require.InDelta(t, 700_000, broker.Cash(), 1)
+}
+
+// TestExecution_IcebergLimitSlices : iceberg 는 지정가 조각을 하나씩 걸고, 앞 조각이 체결돼야 다음 조각을 냄
+func TestExecution_IcebergLimitSlices(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := tools.NewSimulatedClock(start)
+	broker := newJournalBroker()
+	_, err := broker.CreateOrderMarket(model.SideTypeBuy, "KRW-XRP", 500_000)
+	require.NoError(t, err)
+	var result executionResult
+	c, executor := newExecutionConsumer(broker, broker, clock, &result)
+
+	order := sellQty(300)
+	order.Execution = &model.Execution{Algo: model.ExecutionIceberg, Slices: 3, Limit: 1010}
+	require.NoError(t, c.HandleOrder(order))
+
+	for i := 1; i <= 3; i++ {
+		executor.Step()
+		open, err := broker.OpenOrders("KRW-XRP", 10)
+		require.NoError(t, err)
+		require.Len(t, open, 1, "한 번에 한 조각만 걸림")
+		require.InDelta(t, 100, open[0].Quantity, 1e-9)
+		require.InDelta(t, 1010, open[0].Price, 1e-9)
+		require.Len(t, result.filled, i-1)
+
+		at := start.Add(time.Duration(i) * time.Minute)
+		clock.Set(at)
+		broker.OnCandle(xrpCandle(at, 1000, 1020))
+	}
+	executor.Step()
+	require.Len(t, result.filled, 3)
+	require.Empty(t, executor.Active())
+	for _, child := range result.filled {
+		require.Equal(t, model.OrderTypeLimit, child.Type)
+		require.InDelta(t, 1010, child.Price, 1e-9)
+		require.InDelta(t, 100, child.Quantity, 1e-9)
+	}
+}
+
+// TestExecution_ChaseRepricesThenFallsBack : chase 는 최우선 호가가 바뀌면 지정가를 다시 걸고, 제한 시간이 지나면 최유리 IOC 로 마무리
+func TestExecution_ChaseRepricesThenFallsBack(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := tools.NewSimulatedClock(start)
+	broker := &bookBroker{BacktestBroker: newJournalBroker(), bid: 990, ask: 1000}
+	var result executionResult
+	c, executor := newExecutionConsumer(broker.BacktestBroker, broker, clock, &result)
+
+	order := buyKRW(100_000)
+	order.Execution = &model.Execution{Algo: model.ExecutionChase, Interval: 10 * time.Second, Timeout: time.Minute}
+	require.NoError(t, c.HandleOrder(order))
+
+	openPrice := func() float64 {
+		open, err := broker.OpenOrders("KRW-XRP", 10)
+		require.NoError(t, err)
+		require.Len(t, open, 1)
+		return open[0].Price
+	}
+
+	executor.Step()
+	require.InDelta(t, 990, openPrice(), 1e-9)
+
+	// 호가가 그대로면 다시 걸지 않음
+	clock.Set(start.Add(10 * time.Second))
+	executor.Step()
+	require.InDelta(t, 990, openPrice(), 1e-9)
+	require.Empty(t, result.errs)
+
+	// 최우선 매수 호가가 오르면 취소 후 새 호가로
+	broker.bid = 995
+	clock.Set(start.Add(20 * time.Second))
+	executor.Step()
+	require.InDelta(t, 995, openPrice(), 1e-9)
+	require.Len(t, result.errs, 1, "취소된 지정가는 체결 없이 끝남")
+
+	// 제한 시간이 지나면 남은 금액을 최유리 주문으로
+	clock.Set(start.Add(time.Minute))
+	executor.Step()
+	open, err := broker.OpenOrders("KRW-XRP", 10)
+	require.NoError(t, err)
+	require.Empty(t, open)
+	require.Len(t, result.filled, 1)
+	require.Equal(t, model.OrderTypeBest, result.filled[0].Type)
+	require.Empty(t, executor.Active())
+	require.InDelta(t, 900_000, broker.Cash(), 1)
+}
+
+// TestExecution_Rejects : 잘못된 실행 방식이나 실행기가 없는 소비자는 주문을 거절하고 콜백으로 알림
+func TestExecution_Rejects(t *testing.T) {
+	clock := tools.NewSimulatedClock(time.Now())
+	broker := newJournalBroker()
+	var result executionResult
+	c, executor := newExecutionConsumer(broker, broker, clock, &result)
+
+	order := buyKRW(100_000)
+	order.Execution = &model.Execution{Algo: model.ExecutionTWAP, Slices: 0, Duration: time.Minute}
+	err := c.HandleOrder(order)
+	require.True(t, errors.Is(err, execution.ErrInvalidExecution), "got %v", err)
+
+	order.Execution = &model.Execution{Algo: "vwap"}
+	require.ErrorIs(t, c.HandleOrder(order), execution.ErrInvalidExecution)
+	require.Empty(t, executor.Active())
+	require.Len(t, result.errs, 2)
+
+	plain := consumer.NewOrderFeedConsumerBroker(broker)
+	order.Execution = &model.Execution{Algo: model.ExecutionChase, Interval: time.Second}
+	require.Error(t, plain.HandleOrder(order))
+	require.InDelta(t, 1_000_000, broker.Cash(), 1e-9)
+}
+
+// TestExecution_ChaseFallbackJournaled : 저널을 쓰면 chase 의 최유리 IOC 주문도 기록한 identifier 로 나감
+func TestExecution_ChaseFallbackJournaled(t *testing.T) {
+	j, err := journal.Open(filepath.Join(t.TempDir(), "raccoon.journal"))
+	require.NoError(t, err)
+	defer j.Close()
+
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := tools.NewSimulatedClock(start)
+	broker := &bookBroker{BacktestBroker: newJournalBroker(), bid: 990, ask: 1000}
+	var result executionResult
+	c, executor := newExecutionConsumer(broker.BacktestBroker, broker, clock, &result)
+	c.SetJournal(j)
+
+	order := buyKRW(100_000)
+	order.Execution = &model.Execution{Algo: model.ExecutionChase, Interval: 10 * time.Second, Timeout: time.Minute}
+	require.NoError(t, c.HandleOrder(order))
+	executor.Step()
+
+	clock.Set(start.Add(time.Minute))
+	executor.Step()
+	require.Len(t, result.filled, 1)
+	best := result.filled[0]
+	require.Equal(t, model.OrderTypeBest, best.Type)
+	require.NotEmpty(t, best.Identifier)
+	found, err := broker.Order("KRW-XRP", best.Identifier, true)
+	require.NoError(t, err)
+	require.Equal(t, best.ExchangeID, found.ExchangeID)
+	require.Empty(t, j.Open(), "filled best order should close the journal record")
+}
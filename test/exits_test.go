@@ -0,0 +1,155 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/exits"
+	"raccoon/model"
+	"raccoon/storage"
+	"raccoon/utils/tools"
+)
+
+// exitCandle : 1초봉 (저가/고가만 사용)
+func exitCandle(low, high float64) model.Candle {
+	return model.Candle{Pair: "KRW-XRP", Open: low, Close: high, Low: low, High: high}
+}
+
+// newExitManager : 발동한 청산 매도를 sold 에 모으는 관리자
+func newExitManager(t *testing.T, store exits.Store, sold *[]model.Order) *exits.Manager {
+	m, err := exits.NewManager(store, func(order model.Order) {
+		*sold = append(*sold, order)
+	})
+	require.NoError(t, err)
+	m.SetPolicy("psh", exits.Policy{StopLoss: 0.03, TakeProfit: 0.05})
+	return m
+}
+
+// TestExits_OneCancelsOther : 매수 체결에 손절/익절이 붙고, 한쪽이 봉 안에서 닿으면 한 번만 매도한 뒤 둘 다 사라짐
+func TestExits_OneCancelsOther(t *testing.T) {
+	var sold []model.Order
+	m := newExitManager(t, nil, &sold)
+
+	m.OnOrderExecuted(filledOrder(model.SideTypeBuy, 1000, 10), nil)
+	legs := m.Exits("krw-xrp")
+	require.Len(t, legs, 2)
+	require.Equal(t, exits.KindStopLoss, legs[0].Kind)
+	require.InDelta(t, 970, legs[0].Trigger, 1e-9)
+	require.Equal(t, exits.KindTakeProfit, legs[1].Kind)
+	require.InDelta(t, 1050, legs[1].Trigger, 1e-9)
+	require.Equal(t, legs[0].Group, legs[1].Group)
+
+	// 추가 매수는 평균 진입가로 다시 붙임
+	m.OnOrderExecuted(filledOrder(model.SideTypeBuy, 1100, 10), nil)
+	legs = m.Exits("KRW-XRP")
+	require.Len(t, legs, 2)
+	require.InDelta(t, 20, legs[0].Quantity, 1e-9)
+	require.InDelta(t, 1050*0.97, legs[0].Trigger, 1e-9)
+
+	// 전략이 일부 매도하면 남은 수량만 보호
+	m.OnOrderExecuted(filledOrder(model.SideTypeSell, 1040, 5), nil)
+	require.InDelta(t, 15, m.Exits("KRW-XRP")[0].Quantity, 1e-9)
+
+	m.OnCandle(exitCandle(1030, 1060))
+	require.Empty(t, sold)
+
+	// 봉 마감 전 급락 (1초봉 저가가 손절가 아래)
+	m.OnCandle(exitCandle(1010, 1030))
+	require.Len(t, sold, 1)
+	require.Equal(t, model.SideTypeSell, sold[0].Side)
+	require.Equal(t, model.OrderTypeMarket, sold[0].Type)
+	require.Equal(t, "psh", sold[0].Strategy)
+	require.InDelta(t, 15, sold[0].Quantity, 1e-9)
+
+	// 결과를 기다리는 동안 익절 쪽은 발동하지 않음
+	m.OnCandle(exitCandle(1100, 1200))
+	require.Len(t, sold, 1)
+
+	m.OnOrderExecuted(filledOrder(model.SideTypeSell, 1010, 15), nil)
+	require.Empty(t, m.Exits(""))
+
+	// 청산 정책이 없는 전략의 매수에는 붙이지 않음
+	other := filledOrder(model.SideTypeBuy, 1000, 1)
+	other.Strategy = "manual"
+	m.OnOrderExecuted(other, nil)
+	require.Empty(t, m.Exits(""))
+}
+
+// TestExits_TrailingStop : 트레일링 손절가는 최고가가 오른 만큼 따라 오르고 내려가지 않음
+func TestExits_TrailingStop(t *testing.T) {
+	var sold []model.Order
+	m := newExitManager(t, nil, &sold)
+	m.SetPolicy("psh", exits.Policy{TrailingStop: 0.02})
+	m.OnOrderExecuted(filledOrder(model.SideTypeBuy, 1000, 10), nil)
+
+	m.OnCandle(exitCandle(990, 1100))
+	require.InDelta(t, 1080, m.Exits("KRW-XRP")[0].Trigger, 1e-9)
+	m.OnCandle(exitCandle(1085, 1090))
+	require.InDelta(t, 1080, m.Exits("KRW-XRP")[0].Trigger, 1e-9)
+	require.Empty(t, sold)
+
+	m.OnCandle(exitCandle(1079, 1090))
+	require.Len(t, sold, 1)
+	require.InDelta(t, 10, sold[0].Quantity, 1e-9)
+}
+
+// TestExits_PersistAcrossRestart : 저장소에 남은 청산(트레일링 진행 포함)은 재시작 후에도 발동
+func TestExits_PersistAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raccoon.db")
+	store, err := storage.Open(path)
+	require.NoError(t, err)
+
+	var sold []model.Order
+	m := newExitManager(t, store, &sold)
+	m.SetPolicy("psh", exits.Policy{StopLoss: 0.1, TrailingStop: 0.02})
+	m.OnOrderExecuted(filledOrder(model.SideTypeBuy, 1000, 10), nil)
+	m.OnCandle(exitCandle(1000, 1200))
+	require.NoError(t, store.Close())
+
+	// 재시작
+	store, err = storage.Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+	m = newExitManager(t, store, &sold)
+	legs := m.Exits("KRW-XRP")
+	require.Len(t, legs, 2)
+	require.Equal(t, exits.KindTrailingStop, legs[1].Kind)
+	require.InDelta(t, 1180, legs[1].Trigger, 1e-9)
+	require.InDelta(t, 1200, legs[1].High, 1e-9)
+
+	m.OnCandle(exitCandle(1150, 1190))
+	require.Len(t, sold, 1)
+	m.OnOrderExecuted(filledOrder(model.SideTypeSell, 1150, 10), nil)
+
+	saved, err := store.Exits(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, saved)
+}
+
+// TestExits_RetryAfterRejection : 청산 매도가 거절되면 잠시 뒤 다시 발동하고, 계속 실패하면 청산을 지움
+func TestExits_RetryAfterRejection(t *testing.T) {
+	clock := tools.NewSimulatedClock(time.Date(2025, 1, 27, 9, 0, 0, 0, time.UTC))
+	var sold []model.Order
+	m := newExitManager(t, nil, &sold)
+	m.SetClock(clock)
+	m.OnOrderExecuted(filledOrder(model.SideTypeBuy, 1000, 10), nil)
+
+	for i := 1; i < exits.MaxRetries; i++ {
+		m.OnCandle(exitCandle(900, 950))
+		require.Len(t, sold, i)
+		m.OnOrderExecuted(sold[i-1], errors.New("insufficient funds"))
+
+		m.OnCandle(exitCandle(900, 950))
+		require.Len(t, sold, i, "must wait before retrying")
+		clock.Set(clock.Now().Add(exits.RetryInterval))
+	}
+
+	m.OnCandle(exitCandle(900, 950))
+	m.OnOrderExecuted(sold[len(sold)-1], errors.New("insufficient funds"))
+	require.Empty(t, m.Exits(""))
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/consumer"
+	"raccoon/feed"
+	"raccoon/indicator"
+	"raccoon/interfaces"
+	"raccoon/mocks"
+	"raccoon/model"
+	"raccoon/strategy"
+)
+
+// partialRecorder : 완성된 봉과 진행 중인 봉에서 받은 dataframe 을 기록하는 HighFrequencyStrategy
+type partialRecorder struct {
+	closed  []model.Dataframe
+	partial []model.Dataframe
+}
+
+func (s *partialRecorder) GetName() string   { return "partial-recorder" }
+func (s *partialRecorder) Timeframe() string { return "1m" }
+func (s *partialRecorder) WarmupPeriod() int { return 3 }
+func (s *partialRecorder) Indicators(df *model.Dataframe) []indicator.ChartIndicator {
+	return nil
+}
+func (s *partialRecorder) OnCandle(df *model.Dataframe, broker interfaces.Broker) {
+	s.closed = append(s.closed, *df)
+}
+func (s *partialRecorder) OnPartialCandle(df *model.Dataframe, broker interfaces.Broker) {
+	s.partial = append(s.partial, *df)
+}
+
+// TestController_PartialCandleDispatch : 진행 중인 봉은 마지막 행으로 붙은 사본으로 전달되고, 원본 Dataframe 은 그대로
+func TestController_PartialCandleDispatch(t *testing.T) {
+	rec := &partialRecorder{}
+	strat := strategy.WithTimeframe(rec, "5m") // 감싸도 HighFrequencyStrategy 로 인식해야 함
+	ctrl := strategy.NewStrategyController("KRW-XRP", strat, &mocks.MockExchange{})
+	require.True(t, ctrl.HighFrequency())
+
+	dataFeed := feed.NewDataFeed(mocks.NewMockDataFeeder())
+	dataFeed.Subscribe("KRW-XRP", "5m", consumer.NewDataFeedConsumerStrategy(ctrl).OnCandle, !ctrl.HighFrequency())
+
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	candle := func(i int, close float64, complete bool) model.Candle {
+		return model.Candle{Pair: "KRW-XRP", Time: base.Add(time.Duration(i) * 5 * time.Minute),
+			Open: 100, High: close, Low: 90, Close: close, Complete: complete}
+	}
+	dataFeed.Preload("KRW-XRP", "5m", []model.Candle{candle(0, 100, true), candle(1, 101, true), candle(2, 102, true)})
+	ctrl.Start()
+
+	dataFeed.Publish("KRW-XRP", "5m", candle(3, 95, false))
+	dataFeed.Publish("KRW-XRP", "5m", candle(3, 93, false))
+	dataFeed.Publish("KRW-XRP", "5m", candle(2, 80, false)) // 이미 마감된 봉은 무시
+	dataFeed.Publish("KRW-XRP", "5m", model.Candle{})       // 피드 에러 시 빈 봉
+
+	require.Len(t, rec.partial, 2)
+	view := rec.partial[1]
+	require.Len(t, view.Close, 3)
+	require.Equal(t, []float64{101, 102, 93}, []float64(view.Close))
+	require.Equal(t, base.Add(15*time.Minute), view.Time[2])
+	require.Len(t, ctrl.Dataframe.Close, 3, "partial candles must not be appended to the controller dataframe")
+	require.InDelta(t, 102, ctrl.Dataframe.Close[2], 1e-9)
+
+	// 봉이 마감되면 평소처럼 OnCandle
+	dataFeed.Publish("KRW-XRP", "5m", candle(3, 94, true))
+	require.Len(t, ctrl.Dataframe.Close, 4)
+	require.InDelta(t, 94, rec.closed[len(rec.closed)-1].Close.Last(0), 1e-9)
+}
+
+// TestController_PartialCandleIgnoredForRegularStrategy : 일반 전략은 진행 중인 봉을 받지 않음
+func TestController_PartialCandleIgnoredForRegularStrategy(t *testing.T) {
+	strat := &momentumStrategy{}
+	ctrl := strategy.NewStrategyController("KRW-XRP", strat, &mocks.MockExchange{})
+	require.False(t, ctrl.HighFrequency())
+
+	ctrl.Start()
+	ctrl.OnPartialCandle(model.Candle{Pair: "KRW-XRP", Time: time.Now(), Close: 1})
+	require.Empty(t, ctrl.Dataframe.Close)
+}
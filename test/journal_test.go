@@ -0,0 +1,120 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/consumer"
+	"raccoon/exchange"
+	"raccoon/journal"
+	"raccoon/model"
+)
+
+func newJournalBroker() *exchange.BacktestBroker {
+	broker := exchange.NewBackTestBroker("KRW-XRP", 1_000_000)
+	broker.OnCandle(model.Candle{Pair: "KRW-XRP", Time: time.Now(), Open: 1000, High: 1000, Low: 1000, Close: 1000})
+	return broker
+}
+
+// TestJournal_ReconcileAfterCrash : 응답 기록 전에 죽은 주문은 identifier 로 찾아 기록하고, 보내지 못한 의도는 다시 보내지 않음
+func TestJournal_ReconcileAfterCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raccoon.journal")
+	broker := newJournalBroker()
+
+	j, err := journal.Open(path)
+	require.NoError(t, err)
+
+	// 1) 주문은 나갔지만 결과를 기록하기 전에 죽음
+	sent, err := j.Intent(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Strategy: "psh"})
+	require.NoError(t, err)
+	_, err = broker.CreateOrderMarketIdentified(sent, model.SideTypeBuy, "KRW-XRP", 100_000)
+	require.NoError(t, err)
+
+	// 2) 의도만 기록하고 주문 전에 죽음
+	lost, err := j.Intent(model.Order{Pair: "KRW-XRP", Side: model.SideTypeSell, Strategy: "psh"})
+	require.NoError(t, err)
+
+	// 3) 정상적으로 끝난 주문은 재시작 후 남지 않음
+	done, err := j.Intent(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy})
+	require.NoError(t, err)
+	order, err := broker.CreateOrderMarketIdentified(done, model.SideTypeBuy, "KRW-XRP", 100_000)
+	require.NoError(t, err)
+	require.NoError(t, j.Submitted(done, order))
+	require.NoError(t, j.Close())
+
+	// 재시작
+	j, err = journal.Open(path)
+	require.NoError(t, err)
+	defer j.Close()
+	open := j.Open()
+	require.Len(t, open, 2)
+	require.Equal(t, sent, open[0].Identifier)
+	require.Equal(t, journal.StatusPending, open[0].Status)
+
+	changed, err := j.Reconcile(broker)
+	require.NoError(t, err)
+	require.Len(t, changed, 2)
+	require.Equal(t, journal.StatusDone, changed[0].Status)
+	require.NotEmpty(t, changed[0].Order.ExchangeID)
+	require.Equal(t, lost, changed[1].Identifier)
+	require.Equal(t, journal.StatusAbandoned, changed[1].Status)
+	require.Empty(t, j.Open())
+	require.Len(t, broker.Orders(), 2, "reconcile must not send orders")
+}
+
+// TestJournal_IgnoresTornLastLine : 기록 도중 죽어 잘린 마지막 줄은 버리고 나머지는 복원
+func TestJournal_IgnoresTornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raccoon.journal")
+	j, err := journal.Open(path)
+	require.NoError(t, err)
+	id, err := j.Intent(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy})
+	require.NoError(t, err)
+	require.NoError(t, j.Close())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":2,"identifier":"` + id + `","status":"sub`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	j, err = journal.Open(path)
+	require.NoError(t, err)
+	defer j.Close()
+	record, ok := j.Record(id)
+	require.True(t, ok)
+	require.Equal(t, journal.StatusPending, record.Status)
+}
+
+// TestOrderFeedConsumerBroker_Journal : 주문 전에 의도를 기록하고 같은 identifier 로 주문
+func TestOrderFeedConsumerBroker_Journal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raccoon.journal")
+	j, err := journal.Open(path)
+	require.NoError(t, err)
+	defer j.Close()
+
+	broker := newJournalBroker()
+	consumerBroker := consumer.NewOrderFeedConsumerBroker(broker)
+	consumerBroker.SetJournal(j)
+	var executed []model.Order
+	consumerBroker.AddOrderExecutedCallback(func(order model.Order, err error) {
+		if err == nil {
+			executed = append(executed, order)
+		}
+	})
+
+	require.NoError(t, consumerBroker.HandleOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 100_000}))
+	require.Len(t, executed, 1)
+	require.NotEmpty(t, executed[0].Identifier)
+	found, err := broker.Order("KRW-XRP", executed[0].Identifier, true)
+	require.NoError(t, err)
+	require.Equal(t, executed[0].ExchangeID, found.ExchangeID)
+	require.Empty(t, j.Open(), "filled market order should close the journal record")
+
+	// 거래소가 거절한 주문은 rejected 로 끝나고 남지 않음
+	require.Error(t, consumerBroker.HandleOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 10}))
+	require.Empty(t, j.Open())
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/consumer"
+	"raccoon/risk"
+)
+
+// TestKillSwitch_PersistsAcrossRestart : 켜진 상태는 파일에 남아 재시작 후에도 모든 주문을 거절하고, 끄면 다시 허용
+func TestKillSwitch_PersistsAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raccoon.killswitch")
+	k, err := risk.OpenKillSwitch(path)
+	require.NoError(t, err)
+	require.False(t, k.Engaged())
+	require.NoError(t, k.Engage("telegram", "exchange maintenance", true))
+
+	// 재시작
+	k, err = risk.OpenKillSwitch(path)
+	require.NoError(t, err)
+	state := k.State()
+	require.True(t, state.Engaged)
+	require.Equal(t, "telegram", state.Source)
+	require.Equal(t, "exchange maintenance", state.Reason)
+	require.True(t, state.Flatten)
+
+	broker := newJournalBroker()
+	m := risk.NewManager()
+	m.SetKillSwitch(k)
+	c := consumer.NewOrderFeedConsumerBroker(broker)
+	c.SetRiskManager(m)
+
+	requireRejected(t, c.HandleOrder(buyKRW(100_000)), "kill_switch")
+	requireRejected(t, c.HandleOrder(sellQty(1)), "kill_switch")
+	require.Empty(t, broker.Orders())
+
+	require.NoError(t, k.Release("web"))
+	k, err = risk.OpenKillSwitch(path)
+	require.NoError(t, err)
+	require.False(t, k.Engaged())
+	m.SetKillSwitch(k)
+	require.NoError(t, c.HandleOrder(buyKRW(100_000)))
+	require.Len(t, broker.Orders(), 1)
+}
+
+// TestKillSwitch_CorruptFile : 상태 파일을 읽지 못하면 꺼진 것으로 가정하지 않고 에러
+func TestKillSwitch_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raccoon.killswitch")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+	_, err := risk.OpenKillSwitch(path)
+	require.Error(t, err)
+
+	k, err := risk.OpenKillSwitch("")
+	require.NoError(t, err)
+	require.NoError(t, k.Engage("signal", "user defined signal 1", false))
+	require.True(t, k.Engaged())
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/mocks"
+	"raccoon/model"
+	"raccoon/strategy"
+)
+
+// fakeMarketFeeder : 테스트에서 직접 메시지를 넣는 공개 시세 스트림
+type fakeMarketFeeder struct {
+	books   map[string]chan model.UpbitOrderbookMessage
+	trades  map[string]chan model.UpbitTradeMessage
+	tickers map[string]chan model.UpbitTickerMessage
+}
+
+func newFakeMarketFeeder() *fakeMarketFeeder {
+	return &fakeMarketFeeder{
+		books:   make(map[string]chan model.UpbitOrderbookMessage),
+		trades:  make(map[string]chan model.UpbitTradeMessage),
+		tickers: make(map[string]chan model.UpbitTickerMessage),
+	}
+}
+
+func (f *fakeMarketFeeder) OrderbookSubscription(pair string) chan model.UpbitOrderbookMessage {
+	f.books[pair] = make(chan model.UpbitOrderbookMessage)
+	return f.books[pair]
+}
+
+func (f *fakeMarketFeeder) TradeSubscription(pair string) chan model.UpbitTradeMessage {
+	f.trades[pair] = make(chan model.UpbitTradeMessage)
+	return f.trades[pair]
+}
+
+func (f *fakeMarketFeeder) TickerSubscription(pair string) chan model.UpbitTickerMessage {
+	f.tickers[pair] = make(chan model.UpbitTickerMessage)
+	return f.tickers[pair]
+}
+
+// TestMarketFeed_TopOfBookAndTrades : 호가/체결 메시지가 최우선 호가/체결로 변환되어 구독자에게 가고 마지막 값이 남음
+func TestMarketFeed_TopOfBookAndTrades(t *testing.T) {
+	feeder := newFakeMarketFeeder()
+	market := feed.NewMarketFeed(feeder)
+
+	books := make(chan model.Book, 1)
+	trades := make(chan model.TradePrint, 1)
+	market.SubscribeBook("krw-xrp", func(book model.Book) { books <- book })
+	market.SubscribeTrade("KRW-XRP", func(trade model.TradePrint) { trades <- trade })
+	market.SubscribeBook("KRW-XRP", nil)   // 조회만
+	market.SubscribeTicker("KRW-XRP", nil) // 조회만
+	market.Start()
+	defer market.Stop()
+	require.Len(t, feeder.books, 1, "같은 종목은 한 번만 구독")
+
+	_, ok := market.Book("KRW-XRP")
+	require.False(t, ok)
+
+	at := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	feeder.books["KRW-XRP"] <- model.UpbitOrderbookMessage{
+		Type: "orderbook", Code: "KRW-XRP", Timestamp: at.UnixMilli(), TotalBidSize: 5000, TotalAskSize: 3000,
+		OrderbookUnits: []model.OrderbookUnit{
+			{AskPrice: 1001, BidPrice: 999, AskSize: 120, BidSize: 80},
+			{AskPrice: 1002, BidPrice: 998, AskSize: 500, BidSize: 700},
+		},
+	}
+	book := <-books
+	require.Equal(t, "KRW-XRP", book.Pair)
+	require.True(t, book.Time.Equal(at))
+	require.InDelta(t, 999, book.BidPrice, 1e-9)
+	require.InDelta(t, 1001, book.AskPrice, 1e-9)
+	require.InDelta(t, 80, book.BidSize, 1e-9)
+	require.InDelta(t, 2, book.Spread(), 1e-9)
+	require.InDelta(t, 1000, book.Mid(), 1e-9)
+	require.InDelta(t, 0.002, book.SpreadRate(), 1e-12)
+
+	feeder.trades["KRW-XRP"] <- model.UpbitTradeMessage{
+		Type: "trade", Code: "KRW-XRP", TradeTimestamp: at.UnixMilli(), TradePrice: 1001, TradeVolume: 12.5, AskBid: "BID", SequentialID: 42,
+	}
+	trade := <-trades
+	require.Equal(t, model.SideTypeBuy, trade.Side)
+	require.InDelta(t, 1001, trade.Price, 1e-9)
+	require.InDelta(t, 12.5, trade.Quantity, 1e-9)
+	require.Equal(t, int64(42), trade.SequentialID)
+
+	feeder.tickers["KRW-XRP"] <- model.UpbitTickerMessage{Type: "ticker", Code: "KRW-XRP", TradePrice: 1001, SignedChangeRate: -0.01, Timestamp: at.UnixMilli()}
+	require.Eventually(t, func() bool {
+		ticker, ok := market.Ticker("krw-xrp")
+		return ok && ticker.Price == 1001 && ticker.ChangeRate == -0.01
+	}, time.Second, 5*time.Millisecond)
+
+	last, ok := market.Book("krw-xrp")
+	require.True(t, ok)
+	require.Equal(t, book, last)
+	lastTrade, ok := market.LastTrade("KRW-XRP")
+	require.True(t, ok)
+	require.Equal(t, trade, lastTrade)
+}
+
+// bookRecorder : 최우선 호가와 체결을 기록하는 MarketDataStrategy
+type bookRecorder struct {
+	partialRecorder
+	books  []model.Book
+	trades []model.TradePrint
+}
+
+func (s *bookRecorder) OnBook(book model.Book, broker interfaces.Broker) {
+	s.books = append(s.books, book)
+}
+
+func (s *bookRecorder) OnTrade(trade model.TradePrint, broker interfaces.Broker) {
+	s.trades = append(s.trades, trade)
+}
+
+// TestController_MarketDataDispatch : 시작한 뒤에만 MarketDataStrategy 에 호가/체결을 전달하고, 일반 전략은 받지 않음
+func TestController_MarketDataDispatch(t *testing.T) {
+	rec := &bookRecorder{}
+	ctrl := strategy.NewStrategyController("KRW-XRP", strategy.WithTimeframe(rec, "5m"), &mocks.MockExchange{})
+	require.True(t, ctrl.MarketData())
+
+	book := model.Book{Pair: "KRW-XRP", BidPrice: 999, AskPrice: 1001}
+	ctrl.OnBook(book)
+	require.Empty(t, rec.books, "시작 전에는 전달하지 않음")
+
+	ctrl.Start()
+	ctrl.OnBook(book)
+	ctrl.OnTrade(model.TradePrint{Pair: "KRW-XRP", Price: 1000, Quantity: 1, Side: model.SideTypeSell})
+	require.Equal(t, []model.Book{book}, rec.books)
+	require.Len(t, rec.trades, 1)
+
+	regular := strategy.NewStrategyController("KRW-XRP", &momentumStrategy{}, &mocks.MockExchange{})
+	require.False(t, regular.MarketData())
+	regular.Start()
+	regular.OnBook(book) // 무시
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/model"
+	"raccoon/oms"
+)
+
+func myOrderMessage(state, tradeUUID string, price, avgPrice, volume, executed, paidFee float64) model.UpbitMyOrderMessage {
+	return model.UpbitMyOrderMessage{
+		Type:           "myOrder",
+		Code:           "KRW-XRP",
+		UUID:           "order-1",
+		AskBid:         "BID",
+		OrderType:      "limit",
+		State:          state,
+		TradeUUID:      tradeUUID,
+		Price:          price,
+		AvgPrice:       avgPrice,
+		Volume:         volume,
+		ExecutedVolume: executed,
+		PaidFee:        paidFee,
+		TradeFee:       paidFee,
+		OrderTimestamp: 1700000000000,
+		Timestamp:      1700000000000,
+	}
+}
+
+// TestOMS_PartialFillsFromStream : wait → trade → trade → done 순서로 이벤트를 내고 부분 체결을 누적
+func TestOMS_PartialFillsFromStream(t *testing.T) {
+	m := oms.NewManager(newJournalBroker())
+	var events []model.OrderEvent
+	m.Subscribe(func(event model.OrderEvent) {
+		events = append(events, event)
+	})
+
+	m.OnExecution(myOrderMessage("wait", "", 1000, 0, 10, 0, 0))
+	m.OnExecution(myOrderMessage("trade", "t-1", 1000, 1000, 4, 4, 2))
+	m.OnExecution(myOrderMessage("trade", "t-1", 1000, 1000, 4, 4, 2)) // 같은 체결이 다시 와도 한 번만 기록
+	m.OnExecution(myOrderMessage("trade", "t-2", 1010, 1006, 6, 10, 3.03))
+
+	order, ok := m.Order("order-1")
+	require.True(t, ok)
+	require.Equal(t, model.OrderStatusTypeTrade, order.Status)
+	require.Len(t, order.Fills, 2)
+	require.InDelta(t, 10, order.ExecutedQuantity, 1e-9)
+	require.InDelta(t, 1006, order.AvgPrice, 1e-9)
+	require.InDelta(t, 5.03, order.PaidFee, 1e-9)
+	require.Len(t, m.OpenOrders("krw-xrp"), 1)
+
+	done := myOrderMessage("done", "", 1006, 1006, 10, 10, 5.03)
+	m.OnExecution(done)
+	m.OnExecution(done) // 종료 후 늦게 온 메시지는 무시
+
+	types := make([]model.OrderEventType, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	require.Equal(t, []model.OrderEventType{
+		model.OrderEventNew, model.OrderEventTrade, model.OrderEventTrade, model.OrderEventDone,
+	}, types)
+	require.Equal(t, "t-2", events[2].Fill.TradeID)
+	require.Empty(t, m.OpenOrders(""))
+
+	order, _ = m.Order("order-1")
+	require.Equal(t, model.OrderStatusTypeDone, order.Status)
+	require.InDelta(t, 10, order.Quantity, 1e-9)
+}
+
+// TestOMS_PollFillsRestingLimitOrder : 스트림 없이도 조회로 지정가 주문의 체결을 따라감
+func TestOMS_PollFillsRestingLimitOrder(t *testing.T) {
+	broker := newJournalBroker()
+	m := oms.NewManager(broker)
+	var events []model.OrderEvent
+	m.Subscribe(func(event model.OrderEvent) {
+		events = append(events, event)
+	})
+
+	order, err := broker.CreateOrderLimit(model.SideTypeBuy, "KRW-XRP", 50, 900)
+	require.NoError(t, err)
+	order.Strategy = "psh"
+	m.Track(order)
+
+	m.Poll()
+	require.Len(t, m.OpenOrders("KRW-XRP"), 1)
+	require.Len(t, events, 1)
+
+	broker.OnCandle(model.Candle{Pair: "KRW-XRP", Time: time.Now(), Open: 950, High: 960, Low: 890, Close: 920})
+	m.Poll()
+
+	require.Empty(t, m.OpenOrders("KRW-XRP"))
+	orders := m.StrategyOrders("psh")
+	require.Len(t, orders, 1)
+	require.Equal(t, model.OrderStatusTypeDone, orders[0].Status)
+	require.Len(t, orders[0].Fills, 1)
+	require.InDelta(t, 50, orders[0].ExecutedQuantity, 1e-9)
+	require.Equal(t, model.OrderEventDone, events[len(events)-1].Type)
+}
+
+// TestOMS_EventsKeepOrderAcrossGoroutines : 조회와 스트림이 동시에 갱신해도 trade 이벤트가 done 보다 먼저 전달되어야 함
+func TestOMS_EventsKeepOrderAcrossGoroutines(t *testing.T) {
+	m := oms.NewManager(newJournalBroker())
+	inTrade := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var types []model.OrderEventType
+	m.Subscribe(func(event model.OrderEvent) {
+		if event.Type == model.OrderEventTrade {
+			close(inTrade)
+			<-release // trade 를 전달하는 도중에 done 이 들어옴
+		}
+		mu.Lock()
+		types = append(types, event.Type)
+		mu.Unlock()
+	})
+	m.OnExecution(myOrderMessage("wait", "", 1000, 0, 10, 0, 0))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.OnExecution(myOrderMessage("trade", "t-1", 1000, 1000, 10, 10, 5))
+	}()
+	<-inTrade
+	go func() {
+		defer wg.Done()
+		m.OnExecution(myOrderMessage("done", "", 1000, 1000, 10, 10, 5))
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, []model.OrderEventType{
+		model.OrderEventNew, model.OrderEventTrade, model.OrderEventDone,
+	}, types)
+}
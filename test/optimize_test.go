@@ -0,0 +1,189 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/exchange"
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/optimize"
+	"raccoon/strategy"
+)
+
+// tunableMomentum : 매수 비율과 최소 상승폭을 파라미터로 받는 momentumStrategy
+type tunableMomentum struct {
+	momentumStrategy
+	fraction  float64
+	threshold float64
+}
+
+func (s *tunableMomentum) Parameters() []model.ParamSpec {
+	return []model.ParamSpec{
+		{Name: "fraction", Type: model.ParamTypeFloat, Default: 0.5, Min: 0.2, Max: 1, Step: 0.2},
+		{Name: "threshold", Type: model.ParamTypeInt, Default: 0, Min: 0, Max: 4, Step: 2},
+	}
+}
+
+func (s *tunableMomentum) SetParameters(params model.ParamSet) error {
+	return model.ApplyParams(s.Parameters(), params, func(name string, v float64) {
+		switch name {
+		case "fraction":
+			s.fraction = v
+		case "threshold":
+			s.threshold = v
+		}
+	})
+}
+
+func (s *tunableMomentum) OnCandle(df *model.Dataframe, broker interfaces.Broker) {
+	last, prev := df.Close.Last(0), df.Close.Last(1)
+	coin, krw, _, err := broker.Position(df.Pair)
+	if err != nil {
+		return
+	}
+	if last > prev+s.threshold && coin == 0 {
+		s.orderFeed.Publish(model.Order{Pair: df.Pair, Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: krw * s.fraction})
+	} else if last < prev && coin > 0 {
+		s.orderFeed.Publish(model.Order{Pair: df.Pair, Side: model.SideTypeSell, Type: model.OrderTypeMarket, Quantity: coin})
+	}
+}
+
+func newTunableMomentum(orderFeed *feed.OrderFeedSubscription) interfaces.TunableStrategy {
+	return &tunableMomentum{momentumStrategy: momentumStrategy{orderFeed: orderFeed}, fraction: 0.5}
+}
+
+func TestParamSpec_Values(t *testing.T) {
+	spec := model.ParamSpec{Name: "x", Type: model.ParamTypeFloat, Min: 0.1, Max: 0.5, Step: 0.1}
+	require.Equal(t, []float64{0.1, 0.2, 0.3, 0.4, 0.5}, spec.Values())
+	require.Error(t, spec.Validate(0.6))
+
+	grid := optimize.Grid(newTunableMomentum(nil).Parameters())
+	require.Len(t, grid, 5*3)
+
+	r1 := optimize.Random(newTunableMomentum(nil).Parameters(), 6, 42)
+	r2 := optimize.Random(newTunableMomentum(nil).Parameters(), 6, 42)
+	require.Equal(t, r1, r2)
+	require.Len(t, optimize.Random(newTunableMomentum(nil).Parameters(), 100, 1), 15)
+}
+
+func TestImprovedPSH_SetParameters(t *testing.T) {
+	s := strategy.NewImprovedPSHStrategy(feed.NewSyncOrderFeed())
+	require.NoError(t, s.SetParameters(model.ParamSet{strategy.ParamStopLossPercent: 0.1}))
+	require.Error(t, s.SetParameters(model.ParamSet{strategy.ParamRSIOverbought: 99}))
+	require.Error(t, s.SetParameters(model.ParamSet{"unknown": 1}))
+}
+
+// TestOptimizer_GridSearch : 병렬 결과가 개별 실행 결과와 같고, 점수순으로 정렬되어야 함
+func TestOptimizer_GridSearch(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+	start, end := base.Add(10*time.Minute), base.Add(300*time.Minute)
+
+	opt := optimize.NewOptimizer(newTunableMomentum, optimize.Settings{
+		Pair:       "KRW-XRP",
+		Start:      start,
+		End:        end,
+		InitialKRW: 1_000_000,
+		Feeder:     newEngineFeeder(base, 300),
+		Metric:     optimize.MetricTotalReturn,
+		Workers:    4,
+		TopN:       3,
+	})
+
+	result, err := opt.GridSearch()
+	require.NoError(t, err)
+	require.Len(t, result.Trials, 15)
+	require.Len(t, result.Top, 3)
+	for i := 1; i < len(result.Trials); i++ {
+		require.GreaterOrEqual(t, result.Trials[i-1].Score, result.Trials[i].Score)
+	}
+
+	best := result.Top[0]
+	again := opt.Evaluate(best.Params, start, end)
+	require.NoError(t, again.Err)
+	require.InDelta(t, best.Score, again.Score, 1e-12)
+
+	// fraction 5개 값 + threshold 3개 값
+	require.Len(t, result.Sensitivity, 8)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteTable(&buf))
+	require.Contains(t, buf.String(), "Sensitivity")
+}
+
+func TestWalkForward_Windows(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, exchange.KSTLocation)
+	end := start.AddDate(0, 0, 10)
+
+	rolling := optimize.WalkForwardSettings{Mode: optimize.WalkForwardRolling, InSample: 72 * time.Hour, OutOfSample: 48 * time.Hour}
+	windows, err := rolling.Windows(start, end)
+	require.NoError(t, err)
+	require.Len(t, windows, 4) // OOS: 3~5, 5~7, 7~9, 9~10일
+	require.True(t, windows[1].InStart.Equal(start.AddDate(0, 0, 2)))
+	require.True(t, windows[3].OutEnd.Equal(end))
+	for i := 1; i < len(windows); i++ {
+		require.True(t, windows[i].OutStart.Equal(windows[i-1].OutEnd))
+	}
+
+	anchored := rolling
+	anchored.Mode = optimize.WalkForwardAnchored
+	windows, err = anchored.Windows(start, end)
+	require.NoError(t, err)
+	require.Len(t, windows, 4)
+	require.True(t, windows[3].InStart.Equal(start))
+	require.True(t, windows[3].InEnd.Equal(start.AddDate(0, 0, 9)))
+
+	_, err = rolling.Windows(start, start.AddDate(0, 0, 2))
+	require.ErrorIs(t, err, optimize.ErrInvalidWindow)
+}
+
+// TestOptimizer_WalkForward : out-of-sample equity 가 구간별 수익률을 복리로 이어붙인 값이어야 함
+func TestOptimizer_WalkForward(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, exchange.KSTLocation)
+
+	opt := optimize.NewOptimizer(newTunableMomentum, optimize.Settings{
+		Pair:       "KRW-XRP",
+		Start:      base.Add(10 * time.Minute),
+		End:        base.Add(490 * time.Minute),
+		InitialKRW: 1_000_000,
+		Feeder:     newEngineFeeder(base, 490),
+		Metric:     optimize.MetricTotalReturn,
+		Workers:    4,
+	})
+
+	result, err := opt.WalkForward(optimize.WalkForwardSettings{
+		Mode:        optimize.WalkForwardRolling,
+		InSample:    120 * time.Minute,
+		OutOfSample: 60 * time.Minute,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Windows, 6)
+	require.NotNil(t, result.OutOfSample)
+
+	growth := 1.0
+	var orders, trades int
+	for _, w := range result.Windows {
+		require.NoError(t, w.Err)
+		require.NotEmpty(t, w.Params)
+		growth *= 1 + w.OutOfSample.TotalReturn
+		orders += len(w.OutOfSample.Source().Orders)
+		trades += w.OutOfSample.Trades
+	}
+	require.InDelta(t, growth-1, result.OutOfSample.TotalReturn, 1e-9)
+	require.True(t, result.OutOfSample.Start.Equal(result.Windows[0].OutStart))
+
+	// 거래 지표도 구간별 주문을 이어붙여 계산 (구간 끝에 남은 포지션은 정리 매도로 추가)
+	require.Positive(t, trades)
+	require.GreaterOrEqual(t, len(result.OutOfSample.Source().Orders), orders)
+	require.GreaterOrEqual(t, result.OutOfSample.Trades, trades)
+	require.LessOrEqual(t, result.OutOfSample.Trades-trades, len(result.Windows))
+	require.Positive(t, result.OutOfSample.WinRate)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteTable(&buf))
+	require.Contains(t, buf.String(), "walk-forward efficiency")
+}
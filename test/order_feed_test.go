@@ -1,6 +1,7 @@
 package test
 
 import (
+	"errors"
 	"raccoon/feed"
 	"raccoon/model"
 	"testing"
@@ -183,3 +184,174 @@ func TestOrderFeedSubscription_Stop(t *testing.T) {
 		// 기대한 대로 주문을 받지 않음
 	}
 }
+
+// TestOrderFeedSubscription_StrategyRouting : WithStrategy 핸들로 낸 주문은 전략 태그가 붙고, 해당 전략 구독자에게만 전달되어야 함
+func TestOrderFeedSubscription_StrategyRouting(t *testing.T) {
+	ofs := feed.NewSyncOrderFeed()
+	defer ofs.Stop()
+
+	var all, onlyA, onlyB []model.Order
+	ofs.Subscribe("KRW-BTC", func(order model.Order) { all = append(all, order) })
+	ofs.SubscribeStrategy("KRW-BTC", "a", func(order model.Order) { onlyA = append(onlyA, order) })
+	ofs.SubscribeStrategy("KRW-BTC", "b", func(order model.Order) { onlyB = append(onlyB, order) })
+
+	ofs.WithStrategy("a").Publish(model.Order{Pair: "KRW-BTC", Side: model.SideTypeBuy})
+	ofs.WithStrategy("b").Publish(model.Order{Pair: "KRW-BTC", Side: model.SideTypeSell})
+	ofs.Publish(model.Order{Pair: "KRW-BTC", Side: model.SideTypeSell})
+
+	if len(all) != 3 {
+		t.Fatalf("expected 3 orders for pair subscriber, got %d", len(all))
+	}
+	if len(onlyA) != 1 || onlyA[0].Strategy != "a" || onlyA[0].Side != model.SideTypeBuy {
+		t.Errorf("strategy a received %+v", onlyA)
+	}
+	if len(onlyB) != 1 || onlyB[0].Strategy != "b" {
+		t.Errorf("strategy b received %+v", onlyB)
+	}
+}
+
+// TestOrderFeedSubscription_StrategyHandleStop : 전략 핸들의 Stop 은 공유 피드를 멈추지 않아야 함
+func TestOrderFeedSubscription_StrategyHandleStop(t *testing.T) {
+	ofs := feed.NewOrderFeed()
+	received := make(chan model.Order, 10)
+	ofs.Subscribe("KRW-BTC", func(order model.Order) { received <- order })
+	ofs.Start()
+	defer ofs.Stop()
+
+	a, b := ofs.WithStrategy("a"), ofs.WithStrategy("b")
+	a.Stop()
+	b.Publish(model.Order{Pair: "KRW-BTC", Side: model.SideTypeBuy})
+
+	select {
+	case order := <-received:
+		if order.Strategy != "b" {
+			t.Errorf("unexpected order %+v", order)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("order not delivered after a strategy handle was stopped")
+	}
+}
+
+// TestOrderFeedSubscription_OrderedExactlyOnce : 발행 순서대로 FeedID 가 증가하고, 재발행된 주문은 다시 전달되지 않아야 함
+func TestOrderFeedSubscription_OrderedExactlyOnce(t *testing.T) {
+	ofs := feed.NewOrderFeed()
+	received := make(chan model.Order, 100)
+	ofs.Subscribe("KRW-ETH", func(order model.Order) { received <- order })
+	ofs.Start()
+	defer ofs.Stop()
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		ofs.Publish(model.Order{Pair: "KRW-ETH", Price: float64(i)})
+	}
+
+	var first model.Order
+	for i := 0; i < n; i++ {
+		select {
+		case order := <-received:
+			if order.FeedID != int64(i+1) || order.Price != float64(i) {
+				t.Fatalf("order %d: got FeedID %d price %.0f", i, order.FeedID, order.Price)
+			}
+			if i == 0 {
+				first = order
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("did not receive order %d", i)
+		}
+	}
+
+	// 이미 전달된 주문을 다시 발행해도 중복 전달되지 않음
+	ofs.Publish(first)
+	select {
+	case order := <-received:
+		t.Errorf("duplicate order delivered: FeedID %d", order.FeedID)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestOrderFeedSubscription_SlowSubscriber : 느린 구독자가 있어도 다른 구독자와 Publish 는 막히지 않아야 함
+func TestOrderFeedSubscription_SlowSubscriber(t *testing.T) {
+	ofs := feed.NewOrderFeed()
+	release := make(chan struct{})
+	fast := make(chan model.Order, 200)
+	ofs.Subscribe("KRW-BTC", func(order model.Order) { <-release })
+	ofs.Subscribe("KRW-BTC", func(order model.Order) { fast <- order })
+	ofs.Start()
+	defer ofs.Stop()
+	defer close(release)
+
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 200; i++ { // 예전 채널 버퍼(100)보다 많이 발행
+			ofs.Publish(model.Order{Pair: "KRW-BTC"})
+		}
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked by a slow subscriber")
+	}
+	for i := 0; i < 200; i++ {
+		select {
+		case <-fast:
+		case <-time.After(time.Second):
+			t.Fatalf("fast subscriber received only %d orders", i)
+		}
+	}
+}
+
+// TestOrderFeedSubscription_AckReject : ack 구독자의 처리 결과가 OnAck 로 전달되어야 함
+func TestOrderFeedSubscription_AckReject(t *testing.T) {
+	ofs := feed.NewSyncOrderFeed()
+	defer ofs.Stop()
+
+	type result struct {
+		feedID int64
+		err    error
+	}
+	var results []result
+	ofs.OnAck(func(order model.Order, err error) { results = append(results, result{order.FeedID, err}) })
+	ofs.Subscribe("KRW-BTC", func(order model.Order) {}) // 일반 구독자는 ack 대상이 아님
+	ofs.SubscribeAck("KRW-BTC", "", func(order model.Order) error {
+		if order.Quantity <= 0 {
+			return errors.New("invalid quantity")
+		}
+		return nil
+	})
+
+	ofs.Publish(model.Order{Pair: "KRW-BTC", Quantity: 1})
+	ofs.Publish(model.Order{Pair: "KRW-BTC", Quantity: 0})
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 acks, got %d", len(results))
+	}
+	if results[0].feedID != 1 || results[0].err != nil {
+		t.Errorf("first order should be acked: %+v", results[0])
+	}
+	if results[1].feedID != 2 || results[1].err == nil {
+		t.Errorf("second order should be rejected: %+v", results[1])
+	}
+}
+
+// TestOrderFeedSubscription_SyncNestedPublish : 동기 피드에서 구독자가 다시 발행한 주문도 순서대로 모든 구독자에게 전달
+func TestOrderFeedSubscription_SyncNestedPublish(t *testing.T) {
+	ofs := feed.NewSyncOrderFeed()
+	defer ofs.Stop()
+
+	var first, second []int64
+	ofs.Subscribe("KRW-BTC", func(order model.Order) {
+		first = append(first, order.FeedID)
+		if order.FeedID == 1 {
+			ofs.Publish(model.Order{Pair: "KRW-BTC"})
+		}
+	})
+	ofs.Subscribe("KRW-BTC", func(order model.Order) { second = append(second, order.FeedID) })
+
+	ofs.Publish(model.Order{Pair: "KRW-BTC"})
+
+	if len(first) != 2 || len(second) != 2 || second[0] != 1 || second[1] != 2 {
+		t.Errorf("unexpected delivery: first=%v second=%v", first, second)
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/exchange"
+	"raccoon/mocks"
+	"raccoon/model"
+	"raccoon/notification"
+)
+
+// TestPaperBroker_FillsAtLiveQuote : 시장가는 주문 시점의 LastQuote 로 가상 잔고에서 체결되고 Paper 로 표시
+func TestPaperBroker_FillsAtLiveQuote(t *testing.T) {
+	feeder := mocks.NewMockDataFeeder()
+	feeder.Quotes["KRW-XRP"] = 1000
+	paper := exchange.NewPaperBroker(feeder, 1_000_000)
+
+	order, err := paper.CreateOrderMarket(model.SideTypeBuy, "KRW-XRP", 100_000)
+	require.NoError(t, err)
+	require.True(t, order.Paper)
+	require.True(t, strings.HasPrefix(order.ExchangeID, "paper-"))
+	require.InDelta(t, 1000, order.Price, 1e-9)
+	require.InDelta(t, 99.95, order.Quantity, 1e-9)
+
+	// 현재가가 바뀌면 다음 주문은 새 가격으로
+	feeder.Quotes["KRW-XRP"] = 1100
+	order, err = paper.CreateOrderMarket(model.SideTypeSell, "KRW-XRP", 99.95)
+	require.NoError(t, err)
+	require.InDelta(t, 1100, order.Price, 1e-9)
+
+	_, krw, _, err := paper.Position("KRW-XRP")
+	require.NoError(t, err)
+	fee := 0.0005
+	require.InDelta(t, 1_000_000-99_950*(1+fee)+99.95*1100*(1-fee), krw, 1e-6)
+
+	found, err := paper.Order("KRW-XRP", order.ExchangeID, false)
+	require.NoError(t, err)
+	require.True(t, found.Paper)
+
+	// 시세를 못 받으면 주문하지 않음
+	_, err = paper.CreateOrderMarket(model.SideTypeBuy, "KRW-BTC", 100_000)
+	require.Error(t, err)
+}
+
+// TestPaperBroker_LimitOrderOnLiveCandle : 지정가는 실시간 캔들의 현재가(종가)가 닿을 때 체결
+func TestPaperBroker_LimitOrderOnLiveCandle(t *testing.T) {
+	feeder := mocks.NewMockDataFeeder()
+	feeder.Quotes["KRW-XRP"] = 1000
+	paper := exchange.NewPaperBroker(feeder, 1_000_000)
+
+	order, err := paper.CreateOrderLimit(model.SideTypeBuy, "KRW-XRP", 100, 950)
+	require.NoError(t, err)
+	require.Equal(t, model.OrderStatusTypeWait, order.Status)
+
+	// 미완성 봉의 저가가 닿았어도 현재가가 지정가 위면 대기
+	now := time.Now()
+	paper.OnCandle(model.Candle{Pair: "KRW-XRP", Time: now, Open: 1000, High: 1000, Low: 940, Close: 990})
+	open, err := paper.OpenOrders("KRW-XRP", 0)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	require.True(t, open[0].Paper)
+
+	paper.OnCandle(model.Candle{Pair: "KRW-XRP", Time: now, Open: 1000, High: 1000, Low: 940, Close: 945})
+	filled, err := paper.Order("KRW-XRP", order.ExchangeID, false)
+	require.NoError(t, err)
+	require.Equal(t, model.OrderStatusTypeDone, filled.Status)
+	require.InDelta(t, 945, filled.Price, 1e-9)
+}
+
+type recordingNotifier struct {
+	messages []string
+	orders   []model.Order
+}
+
+func (r *recordingNotifier) SendNotification(message string) error {
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *recordingNotifier) OrderNotifier(order model.Order, err error) {
+	r.orders = append(r.orders, order)
+}
+
+// TestPaperNotifier_MarksMessages : 모의투자 알림은 실패 주문까지 모두 표시
+func TestPaperNotifier_MarksMessages(t *testing.T) {
+	inner := &recordingNotifier{}
+	n := notification.NewPaperNotifier(inner)
+
+	require.NoError(t, n.SendNotification("Raccoon started"))
+	n.OrderNotifier(model.Order{Pair: "KRW-XRP"}, nil)
+
+	require.Equal(t, []string{notification.PaperPrefix + "Raccoon started"}, inner.messages)
+	require.True(t, inner.orders[0].Paper)
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/consumer"
+	"raccoon/model"
+	"raccoon/portfolio"
+)
+
+func filledOrder(side model.SideType, price, quantity float64) model.Order {
+	return model.Order{
+		Pair:      "KRW-XRP",
+		Side:      side,
+		Status:    model.OrderStatusTypeDone,
+		Price:     price,
+		Quantity:  quantity,
+		Strategy:  "psh",
+		UpdatedAt: time.Now(),
+	}
+}
+
+// TestAccountant_CostMethods : 같은 체결이라도 FIFO/이동평균에 따라 실현·미실현 손익이 나뉘고, 합계는 같음
+func TestAccountant_CostMethods(t *testing.T) {
+	cases := []struct {
+		method     portfolio.CostMethod
+		realized   float64
+		profit     float64
+		unrealized float64
+	}{
+		{portfolio.CostFIFO, 8000, 0.8, 2000},
+		{portfolio.CostAverage, 3000, 0.2, 7000},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.method), func(t *testing.T) {
+			broker := newJournalBroker()
+			a := portfolio.NewAccountant(tc.method, broker)
+			a.FeeRate = 0
+
+			a.Apply(filledOrder(model.SideTypeBuy, 1000, 10))
+			a.Apply(filledOrder(model.SideTypeBuy, 2000, 10))
+			sell := a.Apply(filledOrder(model.SideTypeSell, 1800, 10))
+			require.InDelta(t, tc.realized, sell.ProfitValue, 1e-6)
+			require.InDelta(t, tc.profit, sell.Profit, 1e-9)
+
+			broker.OnCandle(model.Candle{Pair: "KRW-XRP", Time: time.Now(), Open: 2200, High: 2200, Low: 2200, Close: 2200})
+			pos := a.Position("psh", "krw-xrp")
+			require.InDelta(t, 10, pos.Quantity, 1e-9)
+			require.InDelta(t, 2200, pos.Price, 1e-9)
+			require.InDelta(t, tc.unrealized, pos.Unrealized, 1e-6)
+
+			summary := a.Summary("")
+			require.InDelta(t, 10000, summary.PnL, 1e-6)
+			require.InDelta(t, tc.realized, summary.TodayRealized, 1e-6)
+			require.Equal(t, 1, summary.Trades)
+
+			daily := a.Daily("psh")
+			require.Len(t, daily, 1)
+			require.InDelta(t, tc.realized, daily[0].Realized, 1e-6)
+		})
+	}
+}
+
+// TestAccountant_Fees : 매수 수수료는 원가에, 매도 수수료는 실현 손익에서 빠짐. 수수료가 없으면 FeeRate 로 추정
+func TestAccountant_Fees(t *testing.T) {
+	a := portfolio.NewAccountant(portfolio.CostAverage, nil)
+
+	buy := filledOrder(model.SideTypeBuy, 1000, 10)
+	buy.PaidFee = 5
+	a.Apply(buy)
+	sell := a.Apply(filledOrder(model.SideTypeSell, 1100, 10)) // 수수료 추정: 11000 * 0.0005 = 5.5
+
+	require.InDelta(t, 11000-5.5-10005, sell.ProfitValue, 1e-6)
+	summary := a.Summary("psh")
+	require.InDelta(t, 10.5, summary.Fees, 1e-9)
+	require.Zero(t, summary.Unrealized)
+	require.Empty(t, a.Summary("other").Trades)
+}
+
+// TestOrderFeedConsumerBroker_Accountant : 콜백은 손익이 채워진 체결 주문을 받음
+func TestOrderFeedConsumerBroker_Accountant(t *testing.T) {
+	broker := newJournalBroker()
+	a := portfolio.NewAccountant(portfolio.CostFIFO, broker)
+	c := consumer.NewOrderFeedConsumerBroker(broker)
+	c.SetAccountant(a)
+	var executed []model.Order
+	c.AddOrderExecutedCallback(func(order model.Order, err error) {
+		require.NoError(t, err)
+		executed = append(executed, order)
+	})
+
+	require.NoError(t, c.HandleOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 100_000, Strategy: "psh"}))
+	broker.OnCandle(model.Candle{Pair: "KRW-XRP", Time: time.Now(), Open: 1100, High: 1100, Low: 1100, Close: 1100})
+	require.NoError(t, c.HandleOrder(model.Order{Pair: "KRW-XRP", Side: model.SideTypeSell, Type: model.OrderTypeMarket, Quantity: executed[0].ExecutedQuantity, Strategy: "psh"}))
+
+	require.Len(t, executed, 2)
+	require.Zero(t, executed[0].ProfitValue)
+	require.Greater(t, executed[1].ProfitValue, 0.0)
+	require.InDelta(t, executed[1].ProfitValue, a.Summary("psh").Realized, 1e-9)
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/consumer"
+	"raccoon/exchange"
+	"raccoon/mocks"
+	"raccoon/model"
+	"raccoon/portfolio"
+	"raccoon/risk"
+	"raccoon/utils/tools"
+)
+
+func buyKRW(amount float64) model.Order {
+	return model.Order{Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: amount, Strategy: "psh"}
+}
+
+func sellQty(quantity float64) model.Order {
+	return model.Order{Pair: "KRW-XRP", Side: model.SideTypeSell, Type: model.OrderTypeMarket, Quantity: quantity, Strategy: "psh"}
+}
+
+// quoteCountingBroker : 현재가 조회 횟수를 세는 백테스트 브로커
+type quoteCountingBroker struct {
+	*exchange.BacktestBroker
+	quotes int
+}
+
+func (b *quoteCountingBroker) LastQuote(pair string) (float64, error) {
+	b.quotes++
+	return b.BacktestBroker.LastQuote(pair)
+}
+
+func requireRejected(t *testing.T, err error, check string) {
+	t.Helper()
+	require.True(t, errors.Is(err, risk.ErrRejected), "expected rejection, got %v", err)
+	var rejection *risk.Rejection
+	require.True(t, errors.As(err, &rejection))
+	require.Equal(t, check, rejection.Check)
+	require.NotEmpty(t, rejection.Reason)
+}
+
+// TestRisk_Checks : 검사마다 한도를 넘는 주문만 거절하고, 포지션을 줄이는 매도는 막지 않음
+func TestRisk_Checks(t *testing.T) {
+	t.Run("min_order_total", func(t *testing.T) {
+		feeder := mocks.NewMockDataFeeder()
+		feeder.Assets["KRW-XRP"] = model.AssetInfo{MinPrice: 5000}
+		feeder.Quotes["KRW-XRP"] = 1000
+		check := risk.MinOrderTotal(feeder)
+
+		requireRejected(t, check.Check(buyKRW(4000)), "min_order_total")
+		require.NoError(t, check.Check(buyKRW(5000)))
+		requireRejected(t, check.Check(sellQty(2)), "min_order_total")
+		require.NoError(t, check.Check(sellQty(10)))
+		require.NoError(t, check.Check(model.Order{Pair: "KRW-BTC", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Price: 1}), "unknown minimum passes")
+	})
+
+	t.Run("max_position", func(t *testing.T) {
+		broker := newJournalBroker()
+		_, err := broker.CreateOrderMarket(model.SideTypeBuy, "KRW-XRP", 100_000)
+		require.NoError(t, err)
+		asset, _, _, err := broker.Position("KRW-XRP")
+		require.NoError(t, err)
+		held := asset * 1000
+
+		check := risk.MaxPositionValue(150_000, broker)
+		requireRejected(t, check.Check(buyKRW(150_000-held+100)), "max_position")
+		require.NoError(t, check.Check(buyKRW(150_000-held-100)))
+		require.NoError(t, check.Check(sellQty(asset)))
+	})
+
+	t.Run("max_order_equity_rate", func(t *testing.T) {
+		broker := newJournalBroker()
+		check := risk.MaxOrderEquityRate(0.1, broker)
+		requireRejected(t, check.Check(buyKRW(150_000)), "max_order_equity_rate")
+		require.NoError(t, check.Check(buyKRW(100_000)))
+	})
+
+	t.Run("max_orders_per_minute", func(t *testing.T) {
+		clock := tools.NewSimulatedClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+		m := risk.NewManager(risk.MaxOrdersPerMinute(2, clock))
+		for i := 0; i < 2; i++ {
+			require.NoError(t, m.Validate(buyKRW(10_000)))
+			m.Submitted(buyKRW(10_000))
+			clock.Set(clock.Now().Add(20 * time.Second))
+		}
+		requireRejected(t, m.Validate(sellQty(1)), "max_orders_per_minute")
+
+		clock.Set(clock.Now().Add(30 * time.Second)) // 첫 주문이 1분 창을 벗어남
+		require.NoError(t, m.Validate(buyKRW(10_000)))
+	})
+
+	t.Run("max_daily_loss", func(t *testing.T) {
+		broker := &quoteCountingBroker{BacktestBroker: newJournalBroker()}
+		a := portfolio.NewAccountant(portfolio.CostAverage, broker)
+		a.FeeRate = 0
+		check := risk.MaxDailyLoss(3000, a)
+		require.NoError(t, check.Check(buyKRW(10_000)))
+
+		a.Apply(filledOrder(model.SideTypeBuy, 1000, 10))
+		a.Apply(filledOrder(model.SideTypeSell, 500, 10))
+		requireRejected(t, check.Check(buyKRW(10_000)), "max_daily_loss")
+		require.NoError(t, check.Check(sellQty(1)))
+		require.Zero(t, broker.quotes, "daily loss check must not fetch quotes")
+	})
+}
+
+// TestOrderFeedConsumerBroker_RiskRejection : 거절된 주문은 거래소로 가지 않고, 사유가 콜백 에러로 전달됨
+func TestOrderFeedConsumerBroker_RiskRejection(t *testing.T) {
+	broker := newJournalBroker()
+	clock := tools.NewSimulatedClock(time.Now())
+	c := consumer.NewOrderFeedConsumerBroker(broker)
+	m := risk.NewManager(risk.MaxOrderEquityRate(0.5, broker))
+	m.Add(risk.MaxOrdersPerMinute(1, clock))
+	c.SetRiskManager(m)
+	require.Equal(t, []string{"max_order_equity_rate", "max_orders_per_minute"}, m.Checks())
+
+	var errs []error
+	c.AddOrderExecutedCallback(func(order model.Order, err error) {
+		errs = append(errs, err)
+	})
+
+	// 첫 번째로 거절한 검사의 사유가 전달됨
+	err := c.HandleOrder(buyKRW(600_000))
+	requireRejected(t, err, "max_order_equity_rate")
+
+	require.NoError(t, c.HandleOrder(buyKRW(100_000)))
+	requireRejected(t, c.HandleOrder(buyKRW(100_000)), "max_orders_per_minute")
+
+	require.Len(t, errs, 3)
+	require.Error(t, errs[0])
+	require.NoError(t, errs[1])
+	require.Error(t, errs[2])
+	require.Len(t, broker.Orders(), 1, "rejected orders must not reach the broker")
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/model"
+	"raccoon/oms"
+	"raccoon/storage"
+)
+
+// TestStorage_RecordsOrderLifecycleAcrossRestart : 주문 관리자의 이벤트로 주문/체결을 기록하고 다시 열어도 남아있음
+func TestStorage_RecordsOrderLifecycleAcrossRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "raccoon.db")
+	store, err := storage.Open(path)
+	require.NoError(t, err)
+
+	broker := newJournalBroker()
+	recorder := storage.NewRecorder(store, broker, false)
+	m := oms.NewManager(broker)
+	m.Subscribe(recorder.OnOrderEvent)
+
+	m.OnExecution(myOrderMessage("wait", "", 1000, 0, 10, 0, 0))
+	m.OnExecution(myOrderMessage("trade", "t-1", 1000, 1000, 4, 4, 2))
+	m.OnExecution(myOrderMessage("trade", "t-2", 1010, 1006, 6, 10, 3.03))
+	m.OnExecution(myOrderMessage("done", "", 1006, 1006, 10, 10, 5.03))
+
+	recorder.OnSignal(model.Order{FeedID: 1, Strategy: "psh", Pair: "KRW-XRP", Side: model.SideTypeBuy, Type: model.OrderTypeLimit, Quantity: 10})
+	require.NoError(t, recorder.Snapshot())
+	require.NoError(t, store.Close())
+
+	// 재시작
+	store, err = storage.Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+	version, err := store.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	order, err := store.Order(ctx, "order-1")
+	require.NoError(t, err)
+	require.Equal(t, model.OrderStatusTypeDone, order.Status)
+	require.InDelta(t, 10, order.ExecutedQuantity, 1e-9)
+	require.InDelta(t, 1006, order.AvgPrice, 1e-9)
+	require.Len(t, order.Fills, 2)
+	require.Equal(t, "t-1", order.Fills[0].TradeID)
+
+	orders, err := store.Orders(ctx, storage.Filter{Pair: "krw-xrp"})
+	require.NoError(t, err)
+	require.Len(t, orders, 1, "status updates must not duplicate the order")
+
+	trades, err := store.Trades(ctx, storage.Filter{Pair: "KRW-XRP"})
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+	require.Equal(t, model.SideTypeBuy, trades[1].Side)
+
+	signals, err := store.Signals(ctx, storage.Filter{Strategy: "psh"})
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	require.Equal(t, int64(1), signals[0].FeedID)
+
+	snapshots, err := store.AccountSnapshots(ctx, storage.Filter{})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.InDelta(t, 1_000_000, snapshots[0].KRW, 1e-6)
+	require.InDelta(t, 1_000_000, snapshots[0].Equity, 1e-6)
+
+	_, err = store.Order(ctx, "missing")
+	require.True(t, errors.Is(err, storage.ErrNotFound))
+}
+
+// TestStorage_FillDedupAndLimit : 같은 체결은 한 번만 저장하고, Limit 은 최근 것부터 골라 시간 순서로 반환
+func TestStorage_FillDedupAndLimit(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.Open(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	order := model.Order{ExchangeID: "o-1", Pair: "KRW-BTC", Side: model.SideTypeSell, Type: model.OrderTypeLimit, Status: model.OrderStatusTypeTrade}
+	for i, id := range []string{"a", "b", "c", "a"} {
+		_, err := store.SaveFill(ctx, order, model.Fill{TradeID: id, Price: 100, Quantity: 1, Funds: 100, Time: base.Add(time.Duration(i) * time.Minute)})
+		require.NoError(t, err)
+	}
+
+	trades, err := store.Trades(ctx, storage.Filter{})
+	require.NoError(t, err)
+	require.Len(t, trades, 3)
+
+	recent, err := store.Trades(ctx, storage.Filter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	require.Equal(t, "b", recent[0].TradeID)
+	require.Equal(t, "c", recent[1].TradeID)
+
+	ranged, err := store.Trades(ctx, storage.Filter{From: base.Add(time.Minute), To: base.Add(2 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, ranged, 1)
+	require.Equal(t, "b", ranged[0].TradeID)
+}
+
+// TestStorage_TransactionRollback : 트랜잭션 안에서 에러가 나면 그 안의 쓰기는 모두 취소
+func TestStorage_TransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.Open(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	failure := errors.New("boom")
+	err = store.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := store.SaveOrder(ctx, model.Order{ExchangeID: "o-1", Pair: "KRW-BTC", Side: model.SideTypeBuy, Type: model.OrderTypePrice, Status: model.OrderStatusTypeWait}); err != nil {
+			return err
+		}
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	orders, err := store.Orders(ctx, storage.Filter{})
+	require.NoError(t, err)
+	require.Empty(t, orders)
+}
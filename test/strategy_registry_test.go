@@ -0,0 +1,76 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"raccoon/feed"
+	"raccoon/interfaces"
+	"raccoon/model"
+	"raccoon/optimize"
+	"raccoon/strategy"
+)
+
+// TestStrategyRegistry_Builtins : 기본 전략이 이름으로 등록되어 있고 파라미터 스키마를 노출해야 함
+func TestStrategyRegistry_Builtins(t *testing.T) {
+	require.Subset(t, strategy.Names(), []string{"improved_psh", "psh"})
+
+	def, ok := strategy.Lookup("improved_psh")
+	require.True(t, ok)
+	require.Equal(t, "5m", def.Timeframe)
+	defaults := model.DefaultParams(def.Params)
+	require.InDelta(t, 0.5, defaults[strategy.ParamTradeFraction], 1e-9)
+
+	psh, ok := strategy.Lookup("psh")
+	require.True(t, ok)
+	require.Empty(t, psh.Params)
+
+	var buf bytes.Buffer
+	require.NoError(t, strategy.WriteRegistry(&buf))
+	require.Contains(t, buf.String(), "improved_psh")
+	require.Contains(t, buf.String(), strategy.ParamStopLossPercent)
+}
+
+// TestStrategyRegistry_New : 이름 + 파라미터 맵으로 전략 생성, 잘못된 이름/파라미터는 에러
+func TestStrategyRegistry_New(t *testing.T) {
+	orderFeed := feed.NewSyncOrderFeed()
+
+	strat, err := strategy.New("improved_psh", orderFeed, model.ParamSet{strategy.ParamStopLossPercent: 0.04})
+	require.NoError(t, err)
+	params := strat.(interfaces.TunableStrategy).Parameters()
+	require.NotEmpty(t, params)
+
+	_, err = strategy.New("nope", orderFeed, nil)
+	require.True(t, errors.Is(err, strategy.ErrUnknownStrategy))
+	require.Contains(t, err.Error(), "improved_psh")
+
+	_, err = strategy.New("improved_psh", orderFeed, model.ParamSet{"unknown_param": 1})
+	require.Error(t, err)
+	_, err = strategy.New("psh", orderFeed, model.ParamSet{strategy.ParamTradeFraction: 0.5})
+	require.Error(t, err)
+
+	tunable, err := strategy.NewTunable("improved_psh", orderFeed)
+	require.NoError(t, err)
+	require.Equal(t, "PSH_Improved", tunable.GetName())
+	_, err = strategy.NewTunable("psh", orderFeed)
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		strategy.Register("psh", "duplicate", func(orderFeed *feed.OrderFeedSubscription) interfaces.Strategy {
+			return strategy.NewPSHStrategy(orderFeed)
+		})
+	})
+}
+
+// TestStrategyRegistry_OptimizerFactory : 최적화 도구도 이름으로 전략을 만들 수 있어야 함
+func TestStrategyRegistry_OptimizerFactory(t *testing.T) {
+	factory, err := optimize.FactoryByName("improved_psh")
+	require.NoError(t, err)
+	require.Equal(t, "PSH_Improved", factory(feed.NewSyncOrderFeed()).GetName())
+
+	_, err = optimize.FactoryByName("psh")
+	require.Error(t, err)
+}
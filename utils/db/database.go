@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
+	"raccoon/utils/log"
 )
 
 type Database struct {
@@ -32,47 +34,50 @@ func (transaction *TransactionChain[T]) Finally(finallyCallBack func()) *Transac
 	return transaction
 }
 
-func (transaction *TransactionChain[T]) Run(ctx context.Context, db *sql.DB) (error, T) {
-	tx, err := db.Begin()
-	ctx = context.WithValue(ctx, "tx", tx)
+// Run : block 을 트랜잭션 안에서 실행합니다. block 은 ctx 에 담긴 트랜잭션(tx.TxExtension.GetTx)으로 쿼리해야 합니다.
+// block 이 에러를 반환하거나 panic 하면 롤백하고, 아니면 커밋합니다.
+func (transaction *TransactionChain[T]) Run(ctx context.Context, db *sql.DB) (resultErr error, results T) {
+	if transaction.finallyCallBack != nil {
+		defer transaction.finallyCallBack()
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		_ = fmt.Errorf("transaction start failed")
+		return transaction.fail(fmt.Errorf("transaction start failed: %w", err))
 	}
+	ctx = context.WithValue(ctx, "tx", tx)
 
 	defer func() {
 		if r := recover(); r != nil {
 			if txErr := tx.Rollback(); txErr != nil {
-				_ = fmt.Errorf("transaction rollback failed")
+				log.Errorf("transaction rollback failed: %v", txErr)
 			}
-			if transaction.failedCallBack != nil {
-				var finalErr error
-				if err, ok := r.(error); ok {
-					finalErr = err
-				} else {
-					panic(r)
-				}
-				_, _ = transaction.failedCallBack(finalErr)
+			panicErr, ok := r.(error)
+			if !ok {
+				panic(r)
 			}
-		}
-		if transaction.finallyCallBack != nil {
-			transaction.finallyCallBack()
+			resultErr, results = transaction.fail(panicErr)
 		}
 	}()
-	err, results := transaction.block(ctx)
+
+	err, results = transaction.block(ctx)
 	if err != nil {
 		if txErr := tx.Rollback(); txErr != nil {
-			_ = fmt.Errorf("transaction rollback failed")
-		}
-		if transaction.failedCallBack != nil {
-			return transaction.failedCallBack(err)
+			log.Errorf("transaction rollback failed: %v", txErr)
 		}
+		return transaction.fail(err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		_ = fmt.Errorf("transaction commit failed")
+		return transaction.fail(fmt.Errorf("transaction commit failed: %w", err))
 	}
-	if transaction.finallyCallBack != nil {
-		transaction.finallyCallBack()
+	return nil, results
+}
+
+func (transaction *TransactionChain[T]) fail(err error) (error, T) {
+	if transaction.failedCallBack != nil {
+		return transaction.failedCallBack(err)
 	}
-	return err, results
+	var zero T
+	return err, zero
 }
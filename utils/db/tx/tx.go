@@ -4,22 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"github.com/go-jet/jet/v2/qrm"
-	"jummechu-api/main/common/db"
+	"raccoon/utils/db"
 )
 
 type TxExtension struct {
-	Postgresql *db.Database
+	Database *db.Database
 }
 
+// GetTx : db.Transaction 으로 실행 중이면 그 트랜잭션을, 아니면 DB 를 반환합니다.
 func (p TxExtension) GetTx(ctx context.Context) qrm.DB {
 	tx := ctx.Value("tx")
 	if tx != nil {
 		result, ok := tx.(*sql.Tx)
 		if !ok {
-			return p.Postgresql.DbForJet
+			return p.Database.DbForJet
 		}
 		return result
 	} else {
-		return p.Postgresql.DbForJet
+		return p.Database.DbForJet
 	}
 }
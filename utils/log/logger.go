@@ -92,3 +92,8 @@ func Debug(messages ...interface{}) {
 func Debugf(format string, messages ...interface{}) {
 	logrus.Debugf(format, messages...)
 }
+
+// ParseLevel : "debug", "info", "warn", "error" 등 문자열을 로그 레벨로 변환
+func ParseLevel(level string) (Level, error) {
+	return logrus.ParseLevel(level)
+}
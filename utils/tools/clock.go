@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock : 현재 시각 제공자. 실거래는 RealClock, 백테스트는 SimulatedClock을 사용
+type Clock interface {
+	Now() time.Time
+}
+
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// SimulatedClock : 백테스트 이벤트 루프가 직접 시각을 진행시키는 시계
+type SimulatedClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewSimulatedClock(start time.Time) *SimulatedClock {
+	return &SimulatedClock{now: start}
+}
+
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Set : 시각을 t로 이동. 과거로 되돌리는 요청은 무시합니다.
+func (c *SimulatedClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
@@ -103,12 +103,14 @@ func DfToCandles(df *model.Dataframe) []model.Candle {
 	out := make([]model.Candle, len(df.Close))
 	for i := range df.Close {
 		out[i] = model.Candle{
-			Time:   df.Time[i],
-			Open:   df.Open[i],
-			High:   df.High[i],
-			Low:    df.Low[i],
-			Close:  df.Close[i],
-			Volume: df.Volume[i],
+			Pair:     df.Pair,
+			Time:     df.Time[i],
+			Open:     df.Open[i],
+			High:     df.High[i],
+			Low:      df.Low[i],
+			Close:    df.Close[i],
+			Volume:   df.Volume[i],
+			Complete: true,
 		}
 	}
 	return out
@@ -14,9 +14,20 @@ import (
 // WebServer manages SSE clients and stores chart data.
 type WebServer struct {
 	mu           sync.RWMutex
-	candlesticks []CandleData     // 캔들 데이터 기록
-	indicators   []IndicatorEvent // 지표 이벤트 기록
-	orders       []OrderEvent     // 주문 이벤트 기록
+	candlesticks map[string][]CandleData  // 종목별 캔들 데이터 기록
+	pairs        []string                 // 캔들이 들어온 종목 (등록 순서)
+	indicators   []IndicatorEvent         // 지표 이벤트 기록
+	orders       []OrderEvent             // 주문 이벤트 기록
+	assets       *AssetEvent              // 마지막 잔고 이벤트
+	strategies   map[string]StrategyEvent // 전략별 마지막 손익 이벤트
+	strategyList []string                 // 전략 등록 순서
+	rejections   []RejectionEvent         // 최근 리스크 거절 (최대 maxRejections 건)
+	killSwitch   KillSwitch               // 설정 시 /killswitch 로 거래 중지/재개
+	killState    *KillSwitchEvent         // 마지막 킬 스위치 상태
+	paper        bool                     // 모의투자 모드. 모든 주문/전략을 PAPER 로 표시
+	books        map[string]BookEvent     // 종목별 마지막 최우선 호가
+	bookSentAt   map[string]time.Time     // 종목별 마지막 호가 전송 시각 (bookInterval 마다 한 번만 전송)
+	trades       []TradeEvent             // 최근 체결 (최대 maxTrades 건)
 
 	sseClients map[chan []byte]bool
 	sseMu      sync.Mutex
@@ -24,6 +35,7 @@ type WebServer struct {
 
 // CandleData: Chart.js Financial 플러그인은 시간 정보를 "x" 필드에 기대합니다.
 type CandleData struct {
+	Pair     string  `json:"pair"`
 	X        int64   `json:"x"` // Unix 밀리초 timestamp
 	O        float64 `json:"o"`
 	H        float64 `json:"h"`
@@ -39,11 +51,80 @@ type IndicatorValue struct {
 }
 
 type IndicatorEvent struct {
+	Pair       string           `json:"pair"`
 	Time       int64            `json:"time"`
 	Indicators []IndicatorValue `json:"indicators"`
 }
 
 type OrderEvent struct {
+	Time     int64   `json:"time"`
+	Pair     string  `json:"pair"`
+	Strategy string  `json:"strategy,omitempty"`
+	Side     string  `json:"side"`
+	Price    float64 `json:"price"`
+	Qty      float64 `json:"qty"`
+	Paper    bool    `json:"paper,omitempty"`
+}
+
+// StrategyEvent : 전략별 예산/손익 현황
+type StrategyEvent struct {
+	Time      int64   `json:"time"`
+	Name      string  `json:"name"`
+	Pair      string  `json:"pair"`
+	Timeframe string  `json:"timeframe"`
+	Funded    float64 `json:"funded"`
+	Equity    float64 `json:"equity"`
+	PnL       float64 `json:"pnl"`
+	PnLRate   float64 `json:"pnl_rate"`
+	Trades    int     `json:"trades"`
+	Paper     bool    `json:"paper,omitempty"`
+
+	// 포트폴리오 회계 기준 손익 (체결가/수수료 기준, 매도 시 실현)
+	Realized   float64 `json:"realized"`
+	Unrealized float64 `json:"unrealized"`
+	Fees       float64 `json:"fees"`
+}
+
+// RejectionEvent : 리스크 검사에서 거절된 주문
+type RejectionEvent struct {
+	Time     int64  `json:"time"`
+	Pair     string `json:"pair"`
+	Strategy string `json:"strategy,omitempty"`
+	Side     string `json:"side"`
+	Check    string `json:"check"`
+	Reason   string `json:"reason"`
+	Paper    bool   `json:"paper,omitempty"`
+}
+
+// KillSwitchEvent : 킬 스위치 상태 (켜져 있으면 모든 새 주문 거절)
+type KillSwitchEvent struct {
+	Time    int64  `json:"time"`
+	Engaged bool   `json:"engaged"`
+	Reason  string `json:"reason,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Flatten bool   `json:"flatten,omitempty"`
+}
+
+// KillSwitch : /killswitch 요청을 처리할 봇 (bot.Raccoon)
+type KillSwitch interface {
+	Halt(source, reason string, flatten bool) error
+	Resume(source string) error
+}
+
+// BookEvent : 최우선 호가와 스프레드
+type BookEvent struct {
+	Time       int64   `json:"time"`
+	Pair       string  `json:"pair"`
+	Bid        float64 `json:"bid"`
+	BidSize    float64 `json:"bid_size"`
+	Ask        float64 `json:"ask"`
+	AskSize    float64 `json:"ask_size"`
+	Spread     float64 `json:"spread"`
+	SpreadRate float64 `json:"spread_rate"`
+}
+
+// TradeEvent : 거래소 체결 한 건
+type TradeEvent struct {
 	Time  int64   `json:"time"`
 	Pair  string  `json:"pair"`
 	Side  string  `json:"side"`
@@ -51,17 +132,55 @@ type OrderEvent struct {
 	Qty   float64 `json:"qty"`
 }
 
+const (
+	// 새로 연결한 클라이언트에게 다시 보내는 거절 이벤트 수
+	maxRejections = 20
+	// 새로 연결한 클라이언트에게 다시 보내는 체결 수
+	maxTrades = 50
+	// 호가는 초당 여러 번 바뀌므로 종목마다 이 간격으로만 전송
+	bookInterval = 250 * time.Millisecond
+)
+
+// ModeEvent : 실거래/모의투자 여부. SSE 연결 시 가장 먼저 전송
+type ModeEvent struct {
+	Paper bool `json:"paper"`
+}
+
+type AssetBalance struct {
+	Currency string  `json:"currency"`
+	Balance  float64 `json:"balance"`
+	Locked   float64 `json:"locked"`
+}
+
+type AssetEvent struct {
+	Time     int64          `json:"time"`
+	Balances []AssetBalance `json:"balances"`
+}
+
 func NewWebServer() *WebServer {
 	return &WebServer{
-		candlesticks: make([]CandleData, 0),
+		candlesticks: make(map[string][]CandleData),
 		indicators:   make([]IndicatorEvent, 0),
 		orders:       make([]OrderEvent, 0),
+		strategies:   make(map[string]StrategyEvent),
+		books:        make(map[string]BookEvent),
+		bookSentAt:   make(map[string]time.Time),
 		sseClients:   make(map[chan []byte]bool),
 	}
 }
 
+// SetPaper : 모의투자 모드로 표시합니다. 이후 들어오는 주문/전략 이벤트는 모두 Paper=true
+func (ws *WebServer) SetPaper(paper bool) {
+	ws.mu.Lock()
+	ws.paper = paper
+	ws.mu.Unlock()
+
+	ws.broadcastSSE("mode", ModeEvent{Paper: paper})
+}
+
 func (ws *WebServer) OnCandle(candle model.Candle) {
 	cd := CandleData{
+		Pair:     candle.Pair,
 		X:        candle.Time.UnixMilli(), // "x" 필드에 저장
 		O:        candle.Open,
 		H:        candle.High,
@@ -71,19 +190,25 @@ func (ws *WebServer) OnCandle(candle model.Candle) {
 		Complete: candle.Complete,
 	}
 	ws.mu.Lock()
-	n := len(ws.candlesticks)
-	if n > 0 && ws.candlesticks[n-1].X == cd.X {
-		ws.candlesticks[n-1] = cd
+	candles, ok := ws.candlesticks[cd.Pair]
+	if !ok {
+		ws.pairs = append(ws.pairs, cd.Pair)
+	}
+	n := len(candles)
+	if n > 0 && candles[n-1].X == cd.X {
+		candles[n-1] = cd
 	} else {
-		ws.candlesticks = append(ws.candlesticks, cd)
+		candles = append(candles, cd)
 	}
+	ws.candlesticks[cd.Pair] = candles
 	ws.mu.Unlock()
 
 	ws.broadcastSSE("candle", cd)
 }
 
-func (ws *WebServer) OnIndicators(ts time.Time, values []IndicatorValue) {
+func (ws *WebServer) OnIndicators(pair string, ts time.Time, values []IndicatorValue) {
 	evt := IndicatorEvent{
+		Pair:       pair,
 		Time:       ts.UnixMilli(),
 		Indicators: values,
 	}
@@ -96,20 +221,194 @@ func (ws *WebServer) OnIndicators(ts time.Time, values []IndicatorValue) {
 }
 
 func (ws *WebServer) OnOrder(order model.Order) {
+	ts := order.UpdatedAt
+	if ts.IsZero() {
+		ts = time.Now()
+	}
 	evt := OrderEvent{
-		Time:  time.Now().UnixMilli(),
-		Pair:  order.Pair,
-		Side:  string(order.Side),
-		Price: order.Price,
-		Qty:   order.Quantity,
+		Time:     ts.UnixMilli(),
+		Pair:     order.Pair,
+		Strategy: order.Strategy,
+		Side:     string(order.Side),
+		Price:    order.Price,
+		Qty:      order.Quantity,
+		Paper:    order.Paper,
 	}
 	ws.mu.Lock()
+	evt.Paper = evt.Paper || ws.paper
 	ws.orders = append(ws.orders, evt)
 	ws.mu.Unlock()
 
 	ws.broadcastSSE("order", evt)
 }
 
+// OnAsset : 거래소 private 스트림(myAsset)의 잔고 변경을 기록/전송
+func (ws *WebServer) OnAsset(msg model.UpbitMyAssetMessage) {
+	evt := AssetEvent{
+		Time:     msg.Timestamp,
+		Balances: make([]AssetBalance, 0, len(msg.Assets)),
+	}
+	for _, a := range msg.Assets {
+		evt.Balances = append(evt.Balances, AssetBalance{
+			Currency: a.Currency,
+			Balance:  a.Balance,
+			Locked:   a.Locked,
+		})
+	}
+	ws.mu.Lock()
+	ws.assets = &evt
+	ws.mu.Unlock()
+
+	ws.broadcastSSE("asset", evt)
+}
+
+// OnBook : 실시간 최우선 호가를 기록하고, 종목마다 bookInterval 에 한 번 전송
+func (ws *WebServer) OnBook(book model.Book) {
+	evt := BookEvent{
+		Time:       book.Time.UnixMilli(),
+		Pair:       book.Pair,
+		Bid:        book.BidPrice,
+		BidSize:    book.BidSize,
+		Ask:        book.AskPrice,
+		AskSize:    book.AskSize,
+		Spread:     book.Spread(),
+		SpreadRate: book.SpreadRate(),
+	}
+	now := time.Now()
+	ws.mu.Lock()
+	ws.books[evt.Pair] = evt
+	send := now.Sub(ws.bookSentAt[evt.Pair]) >= bookInterval
+	if send {
+		ws.bookSentAt[evt.Pair] = now
+	}
+	ws.mu.Unlock()
+
+	if send {
+		ws.broadcastSSE("book", evt)
+	}
+}
+
+// OnTrade : 실시간 체결을 기록/전송
+func (ws *WebServer) OnTrade(trade model.TradePrint) {
+	evt := TradeEvent{
+		Time:  trade.Time.UnixMilli(),
+		Pair:  trade.Pair,
+		Side:  string(trade.Side),
+		Price: trade.Price,
+		Qty:   trade.Quantity,
+	}
+	ws.mu.Lock()
+	ws.trades = append(ws.trades, evt)
+	if len(ws.trades) > maxTrades {
+		ws.trades = ws.trades[len(ws.trades)-maxTrades:]
+	}
+	ws.mu.Unlock()
+
+	ws.broadcastSSE("trade", evt)
+}
+
+// OnStrategy : 전략별 손익 현황을 기록/전송
+func (ws *WebServer) OnStrategy(evt StrategyEvent) {
+	if evt.Time == 0 {
+		evt.Time = time.Now().UnixMilli()
+	}
+	ws.mu.Lock()
+	evt.Paper = evt.Paper || ws.paper
+	if _, ok := ws.strategies[evt.Name]; !ok {
+		ws.strategyList = append(ws.strategyList, evt.Name)
+	}
+	ws.strategies[evt.Name] = evt
+	ws.mu.Unlock()
+
+	ws.broadcastSSE("strategy", evt)
+}
+
+// OnRejection : 리스크 검사에서 거절된 주문을 기록/전송
+func (ws *WebServer) OnRejection(evt RejectionEvent) {
+	if evt.Time == 0 {
+		evt.Time = time.Now().UnixMilli()
+	}
+	ws.mu.Lock()
+	evt.Paper = evt.Paper || ws.paper
+	ws.rejections = append(ws.rejections, evt)
+	if len(ws.rejections) > maxRejections {
+		ws.rejections = ws.rejections[len(ws.rejections)-maxRejections:]
+	}
+	ws.mu.Unlock()
+
+	ws.broadcastSSE("rejection", evt)
+}
+
+// SetKillSwitch : POST /killswitch 로 거래를 중지/재개할 수 있게 합니다.
+func (ws *WebServer) SetKillSwitch(k KillSwitch) {
+	ws.mu.Lock()
+	ws.killSwitch = k
+	ws.mu.Unlock()
+}
+
+// OnKillSwitch : 킬 스위치 상태를 기록/전송
+func (ws *WebServer) OnKillSwitch(evt KillSwitchEvent) {
+	if evt.Time == 0 {
+		evt.Time = time.Now().UnixMilli()
+	}
+	ws.mu.Lock()
+	ws.killState = &evt
+	ws.mu.Unlock()
+
+	ws.broadcastSSE("killswitch", evt)
+}
+
+// killSwitchHandler : POST /killswitch {"action": "halt" | "flatten" | "resume", "reason": "..."}
+func (ws *WebServer) killSwitchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ws.mu.RLock()
+	k := ws.killSwitch
+	ws.mu.RUnlock()
+	if k == nil {
+		http.Error(w, "kill switch is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = "web"
+	}
+
+	var err error
+	switch req.Action {
+	case "halt":
+		err = k.Halt("web", req.Reason, false)
+	case "flatten":
+		err = k.Halt("web", req.Reason, true)
+	case "resume":
+		err = k.Resume("web")
+	default:
+		http.Error(w, "unknown action: "+req.Action, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ws.mu.RLock()
+	state := ws.killState
+	ws.mu.RUnlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
 func (ws *WebServer) broadcastSSE(typ string, data interface{}) {
 	ws.sseMu.Lock()
 	defer ws.sseMu.Unlock()
@@ -155,15 +454,25 @@ func (ws *WebServer) sseHandler(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	ws.mu.RLock()
+	modeMsg, _ := json.Marshal(struct {
+		Type string    `json:"type"`
+		Data ModeEvent `json:"data"`
+	}{
+		"mode", ModeEvent{Paper: ws.paper},
+	})
+	fmt.Fprintf(w, "data: %s\n\n", string(modeMsg))
+
 	// 저장된 모든 데이터(캔들, 지표, 주문)를 클라이언트로 전송
-	for _, c := range ws.candlesticks {
-		msg, _ := json.Marshal(struct {
-			Type string     `json:"type"`
-			Data CandleData `json:"data"`
-		}{
-			"candle", c,
-		})
-		fmt.Fprintf(w, "data: %s\n\n", string(msg))
+	for _, pair := range ws.pairs {
+		for _, c := range ws.candlesticks[pair] {
+			msg, _ := json.Marshal(struct {
+				Type string     `json:"type"`
+				Data CandleData `json:"data"`
+			}{
+				"candle", c,
+			})
+			fmt.Fprintf(w, "data: %s\n\n", string(msg))
+		}
 	}
 	for _, ind := range ws.indicators {
 		msg, _ := json.Marshal(struct {
@@ -183,6 +492,64 @@ func (ws *WebServer) sseHandler(w http.ResponseWriter, r *http.Request) {
 		})
 		fmt.Fprintf(w, "data: %s\n\n", string(msg))
 	}
+	for _, name := range ws.strategyList {
+		msg, _ := json.Marshal(struct {
+			Type string        `json:"type"`
+			Data StrategyEvent `json:"data"`
+		}{
+			"strategy", ws.strategies[name],
+		})
+		fmt.Fprintf(w, "data: %s\n\n", string(msg))
+	}
+	if ws.killState != nil {
+		msg, _ := json.Marshal(struct {
+			Type string          `json:"type"`
+			Data KillSwitchEvent `json:"data"`
+		}{
+			"killswitch", *ws.killState,
+		})
+		fmt.Fprintf(w, "data: %s\n\n", string(msg))
+	}
+	for _, rj := range ws.rejections {
+		msg, _ := json.Marshal(struct {
+			Type string         `json:"type"`
+			Data RejectionEvent `json:"data"`
+		}{
+			"rejection", rj,
+		})
+		fmt.Fprintf(w, "data: %s\n\n", string(msg))
+	}
+	for _, pair := range ws.pairs {
+		book, ok := ws.books[pair]
+		if !ok {
+			continue
+		}
+		msg, _ := json.Marshal(struct {
+			Type string    `json:"type"`
+			Data BookEvent `json:"data"`
+		}{
+			"book", book,
+		})
+		fmt.Fprintf(w, "data: %s\n\n", string(msg))
+	}
+	for _, tr := range ws.trades {
+		msg, _ := json.Marshal(struct {
+			Type string     `json:"type"`
+			Data TradeEvent `json:"data"`
+		}{
+			"trade", tr,
+		})
+		fmt.Fprintf(w, "data: %s\n\n", string(msg))
+	}
+	if ws.assets != nil {
+		msg, _ := json.Marshal(struct {
+			Type string     `json:"type"`
+			Data AssetEvent `json:"data"`
+		}{
+			"asset", *ws.assets,
+		})
+		fmt.Fprintf(w, "data: %s\n\n", string(msg))
+	}
 	ws.mu.RUnlock()
 	flusher.Flush()
 
@@ -334,6 +701,25 @@ func (ws *WebServer) chartHandler(w http.ResponseWriter, r *http.Request) {
         }
       });
   
+      // 여러 종목이 들어오면 선택한 종목(?pair=)만 그림. 지정이 없으면 첫 종목
+      const pairSelect = document.getElementById('pairSelect');
+      let selectedPair = new URLSearchParams(window.location.search).get('pair');
+      pairSelect.addEventListener('change', function() {
+        window.location.search = '?pair=' + encodeURIComponent(pairSelect.value);
+      });
+      function acceptPair(pair) {
+        if (!pair) return true;
+        if (!Array.from(pairSelect.options).some(o => o.value === pair)) {
+          pairSelect.add(new Option(pair, pair));
+        }
+        if (!selectedPair) selectedPair = pair;
+        pairSelect.value = selectedPair;
+        return pair === selectedPair;
+      }
+
+      // 전략별 손익 (name → 마지막 이벤트)
+      const strategyRows = {};
+
       // SSE 이벤트 처리
       const evtSource = new EventSource('/sse');
       evtSource.onmessage = function(ev) {
@@ -345,6 +731,7 @@ func (ws *WebServer) chartHandler(w http.ResponseWriter, r *http.Request) {
         switch(parsed.type) {
           case 'candle': {
             const c = parsed.data;
+            if (!acceptPair(c.pair)) break;
             // priceChart 업데이트 (candlestick)
             let dsPrice = priceChart.data.datasets[0];
             let idx = dsPrice.data.findIndex(item => item.x === c.x);
@@ -377,6 +764,7 @@ func (ws *WebServer) chartHandler(w http.ResponseWriter, r *http.Request) {
           }
           case 'indicators': {
             const iEvt = parsed.data;
+            if (!acceptPair(iEvt.pair)) break;
             const tVal = iEvt.time;
             iEvt.indicators.forEach(iv => {
               let ds = getOrCreateLineDataset(priceChart, iv.name);
@@ -393,16 +781,92 @@ func (ws *WebServer) chartHandler(w http.ResponseWriter, r *http.Request) {
           case 'order': {
             const dsOrder = priceChart.data.datasets[1];
             const od = parsed.data;
+            if (!acceptPair(od.pair)) break;
             let color = (od.side==="buy" || od.side==="bid") ? "green" : "red";
-            dsOrder.data.push({ x: od.time, y: od.price, backgroundColor: color, borderColor: color });
+            // 모의투자 주문은 속이 빈 점으로 표시
+            dsOrder.data.push({ x: od.time, y: od.price, backgroundColor: od.paper ? "white" : color, borderColor: color });
             priceChart.update();
             break;
           }
+          case 'strategy': {
+            const st = parsed.data;
+            strategyRows[st.name] = st;
+            const box = document.getElementById('strategies');
+            box.textContent = Object.values(strategyRows)
+              .map(s => (s.paper ? "[PAPER] " : "") + s.name + " [" + s.pair + " " + s.timeframe + "] PnL: " + s.pnl.toFixed(0) +
+                " KRW (" + (s.pnl_rate * 100).toFixed(2) + "%), realized: " + s.realized.toFixed(0) +
+                " / unrealized: " + s.unrealized.toFixed(0) + " KRW, fees: " + s.fees.toFixed(0) + ", trades: " + s.trades)
+              .join(" | ");
+            break;
+          }
+          case 'rejection': {
+            const rj = parsed.data;
+            const box = document.getElementById('rejections');
+            const line = document.createElement('div');
+            line.textContent = new Date(rj.time).toLocaleTimeString() + " " + (rj.paper ? "[PAPER] " : "") +
+              "REJECTED " + rj.side + " " + rj.pair + (rj.strategy ? " (" + rj.strategy + ")" : "") + " - " + rj.check + ": " + rj.reason;
+            box.prepend(line);
+            while (box.childElementCount > 20) box.lastElementChild.remove();
+            break;
+          }
+          case 'killswitch': {
+            const ks = parsed.data;
+            const banner = document.getElementById('killswitch');
+            banner.textContent = ks.engaged
+              ? "TRADING HALTED (" + ks.source + ", " + new Date(ks.time).toLocaleString() + "): " + ks.reason + (ks.flatten ? " - positions flattened" : "")
+              : "";
+            banner.style.display = ks.engaged ? "block" : "none";
+            break;
+          }
+          case 'mode': {
+            const banner = document.getElementById('mode');
+            banner.textContent = parsed.data.paper ? "PAPER TRADING (모의투자) - 실제 주문이 나가지 않습니다" : "";
+            banner.style.display = parsed.data.paper ? "block" : "none";
+            break;
+          }
+          case 'book': {
+            const bk = parsed.data;
+            if (!acceptPair(bk.pair)) break;
+            document.getElementById('book').textContent =
+              "Bid " + bk.bid + " (" + bk.bid_size.toFixed(4) + ") / Ask " + bk.ask + " (" + bk.ask_size.toFixed(4) + ")" +
+              " | Spread " + bk.spread + " (" + (bk.spread_rate * 100).toFixed(3) + "%)";
+            break;
+          }
+          case 'trade': {
+            const tr = parsed.data;
+            if (!acceptPair(tr.pair)) break;
+            const box = document.getElementById('trades');
+            const line = document.createElement('div');
+            line.textContent = new Date(tr.time).toLocaleTimeString() + " " + (tr.side === "bid" ? "BUY " : "SELL ") + tr.qty + " @ " + tr.price;
+            line.style.color = tr.side === "bid" ? "green" : "red";
+            box.prepend(line);
+            while (box.childElementCount > 20) box.lastElementChild.remove();
+            break;
+          }
+          case 'asset': {
+            const box = document.getElementById('assets');
+            box.textContent = parsed.data.balances
+              .map(b => b.currency + ": " + b.balance + " (locked " + b.locked + ")")
+              .join(" | ");
+            break;
+          }
           default:
             console.log("Unknown SSE event:", parsed);
         }
       };
   
+      // 킬 스위치 버튼
+      function sendKillSwitch(action) {
+        const labels = { halt: "새 주문을 막고 미체결 주문을 모두 취소할까요?", flatten: "미체결 주문을 취소하고 보유 코인을 모두 시장가 매도할까요?", resume: "거래를 다시 시작할까요?" };
+        if (!confirm(labels[action])) return;
+        const reason = action === "resume" ? "" : (prompt("사유", "") || "");
+        fetch('/killswitch', { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify({ action: action, reason: reason }) })
+          .then(res => { if (!res.ok) return res.text().then(t => alert(t)); });
+      }
+      document.getElementById('haltBtn').addEventListener('click', () => sendKillSwitch('halt'));
+      document.getElementById('flattenBtn').addEventListener('click', () => sendKillSwitch('flatten'));
+      document.getElementById('resumeBtn').addEventListener('click', () => sendKillSwitch('resume'));
+
       // getOrCreateLineDataset: indicator 이름에 따라 데이터셋과 고유 y축을 동적으로 생성 (priceChart에 추가)
       function getOrCreateLineDataset(chart, name) {
         const axisId = 'yIndicator_' + name;  // 고유 y축 ID
@@ -446,7 +910,20 @@ func (ws *WebServer) chartHandler(w http.ResponseWriter, r *http.Request) {
   </script>
 </head>
 <body>
+  <div id="mode" style="display:none; background:#ffe08a; font-weight:bold; padding:6px;"></div>
+  <div id="killswitch" style="display:none; background:#b00020; color:white; font-weight:bold; padding:6px;"></div>
+  <div>
+    <button id="haltBtn">Halt</button>
+    <button id="flattenBtn">Halt + Flatten</button>
+    <button id="resumeBtn">Resume</button>
+  </div>
   <h1>Mixed Chart: Candlestick + Indicators + Volume & Orders</h1>
+  <select id="pairSelect"></select>
+  <div id="assets"></div>
+  <div id="strategies"></div>
+  <div id="rejections" style="color:#b00020;"></div>
+  <div id="book"></div>
+  <div id="trades" style="font-family:monospace; max-height:200px; overflow-y:auto;"></div>
   <div id="charts">
     <canvas id="priceChart" width="1200" height="400"></canvas>
     <canvas id="volumeChart" width="1200" height="150"></canvas>
@@ -461,6 +938,7 @@ func (ws *WebServer) Start(port string) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/chart", ws.chartHandler)
 	mux.HandleFunc("/sse", ws.sseHandler)
+	mux.HandleFunc("/killswitch", ws.killSwitchHandler)
 	fmt.Println("[WebServer] Listening on", port)
 	return http.ListenAndServe(port, mux)
 }